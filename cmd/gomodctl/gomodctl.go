@@ -1,23 +1,38 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/beatlabs/gomodctl/internal"
 	"github.com/beatlabs/gomodctl/internal/cmd/check"
+	"github.com/beatlabs/gomodctl/internal/cmd/completion"
+	diffcmd "github.com/beatlabs/gomodctl/internal/cmd/diff"
+	graphcmd "github.com/beatlabs/gomodctl/internal/cmd/graph"
 	"github.com/beatlabs/gomodctl/internal/cmd/info"
 	licensecmd "github.com/beatlabs/gomodctl/internal/cmd/license"
+	outdatedcmd "github.com/beatlabs/gomodctl/internal/cmd/outdated"
+	sbomcmd "github.com/beatlabs/gomodctl/internal/cmd/sbom"
 	scancmd "github.com/beatlabs/gomodctl/internal/cmd/scan"
 	"github.com/beatlabs/gomodctl/internal/cmd/search"
 	updatecmd "github.com/beatlabs/gomodctl/internal/cmd/update"
+	whycmd "github.com/beatlabs/gomodctl/internal/cmd/why"
 	"github.com/beatlabs/gomodctl/internal/godoc"
+	"github.com/beatlabs/gomodctl/internal/httpclient"
 	"github.com/beatlabs/gomodctl/internal/license"
+	"github.com/beatlabs/gomodctl/internal/logger"
 	"github.com/beatlabs/gomodctl/internal/module"
+	"github.com/beatlabs/gomodctl/internal/printer"
+	"github.com/beatlabs/gomodctl/internal/sbom"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -26,6 +41,19 @@ import (
 var ro RootOptions
 var version string
 
+// cancelRun cancels the context of the command, deadline does it once --max-runtime elapsed.
+var (
+	cancelRun context.CancelFunc
+	deadline  *time.Timer
+)
+
+// result collects the printed result when --output is set, it is written to the file once the command is done.
+// Results streamed line by line, e.g. of scan --format jsonl, are written to outputFile as they are printed instead.
+var (
+	result     bytes.Buffer
+	outputFile *os.File
+)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "gomodctl",
@@ -37,15 +65,61 @@ Example:
   gomodctl search mongo
 
 This command will search in all public Go packages and return matching results for term "mongo".`,
-	Version: version,
+	Version:       version,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if ro.output != "" && printer.FormatOf(cmd.Flags()) == scancmd.FormatJSONLines {
+			f, err := printer.CreateFile(ro.output)
+			if err != nil {
+				return err
+			}
+
+			outputFile = f
+			printer.SetOutput(f)
+		} else if ro.output != "" {
+			printer.SetOutput(&result)
+		}
+
+		if err := printer.SetColorMode(viper.GetString("color")); err != nil {
+			return err
+		}
+
+		// the context is created before flags are parsed, so the deadline starts here instead of context.WithTimeout.
+		if maxRuntime := viper.GetDuration("max_runtime"); maxRuntime > 0 {
+			deadline = time.AfterFunc(maxRuntime, cancelRun)
+		}
+
+		// commands with document formats of their own, e.g. sbom, validate --format themselves.
+		if cmd.Flags().Lookup("format") != cmd.Root().PersistentFlags().Lookup("format") {
+			return nil
+		}
+
+		return printer.ValidateFormat(printer.FormatOf(cmd.Flags()), strings.Fields(cmd.Annotations[printer.FormatsAnnotation])...)
+	},
 }
 
 // RootOptions is exported.
 type RootOptions struct {
-	config   string
-	registry string
-	json     bool
-	path     string
+	config      string
+	registry    string
+	index       string
+	token       string
+	json        bool
+	format      string
+	path        string
+	proxy       string
+	cacheTTL    time.Duration
+	noCache     bool
+	concurrency int
+	timeout     time.Duration
+	retries     int
+	rate        float64
+	maxRuntime  time.Duration
+	verbose     int
+	quiet       bool
+	stdin       bool
+	output      string
+	color       string
 }
 
 // Execute is exported.
@@ -56,6 +130,7 @@ func Execute() {
 	debug.SetGCPercent(-1)
 
 	ctx, cancel := context.WithCancel(context.Background())
+	cancelRun = cancel
 
 	signals := make(chan os.Signal, 1)
 
@@ -72,26 +147,75 @@ func Execute() {
 	ro.config = viper.GetString("config")
 	ro.registry = viper.GetString("registry")
 
-	gd := godoc.NewClient(ctx)
+	gd := godoc.NewRegistry(ctx)
 	checker := module.Checker{Ctx: ctx}
 	updater := module.Updater{Ctx: ctx}
 	licenseChecker, err := license.NewChecker(ctx)
 	scanner := module.Scanner{Ctx: ctx}
+	differ := module.Differ{Ctx: ctx}
+	explainer := module.Explainer{Ctx: ctx}
+	grapher := module.Grapher{Ctx: ctx}
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 
 	// Add sub-commands
+	infoCmd := info.NewCmdInfo(gd, &checker)
+	checkCmd := check.NewCmdCheck(&checker)
+	licenseCmd := licensecmd.NewCmdLicense(licenseChecker)
+	whyCmd := whycmd.NewCmdWhy(&explainer)
+	graphCmd := graphcmd.NewCmdGraph(&grapher)
+
+	// module arguments are completed with modules required by go.mod.
+	for _, cmd := range []*cobra.Command{infoCmd, checkCmd, licenseCmd, whyCmd, graphCmd} {
+		cmd.ValidArgsFunction = completion.ModulePaths(&checker)
+	}
+
 	rootCmd.AddCommand(search.NewCmdSearch(gd))
-	rootCmd.AddCommand(info.NewCmdInfo(gd))
-	rootCmd.AddCommand(check.NewCmdCheck(&checker))
+	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(outdatedcmd.NewCmdOutdated(&checker))
 	rootCmd.AddCommand(updatecmd.NewCmdUpdate(&updater))
-	rootCmd.AddCommand(licensecmd.NewCmdLicense(licenseChecker))
+	rootCmd.AddCommand(licenseCmd)
 	rootCmd.AddCommand(scancmd.NewCmdScan(&scanner))
+	rootCmd.AddCommand(sbomcmd.NewCmdSBOM(sbom.NewGenerator(ctx, licenseChecker)))
+	rootCmd.AddCommand(diffcmd.NewCmdDiff(&differ))
+	rootCmd.AddCommand(whyCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(completion.NewCmdCompletion())
 
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		fmt.Println(err)
+	err = rootCmd.ExecuteContext(ctx)
+	module.RemoveStdinDir()
+
+	// Stop reports false once the timer fired, i.e. the command was aborted.
+	if deadline != nil && !deadline.Stop() && err != nil {
+		err = wrapExitError(err, fmt.Errorf("--max-runtime of %s exceeded", viper.GetDuration("max_runtime")))
+	}
+
+	// commands exiting with an error status may have printed a result too, e.g. check with available updates.
+	if outputFile != nil {
+		if outErr := outputFile.Close(); outErr != nil {
+			fmt.Fprintln(os.Stderr, outErr)
+			os.Exit(1)
+		}
+	} else if ro.output != "" && (err == nil || result.Len() > 0) {
+		if outErr := printer.WriteFile(ro.output, result.Bytes()); outErr != nil {
+			fmt.Fprintln(os.Stderr, outErr)
+			os.Exit(1)
+		}
+	}
+
+	if err != nil {
+		var exitErr *internal.ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.Err != nil {
+				fmt.Fprintln(os.Stderr, exitErr.Err)
+			}
+			os.Exit(exitErr.Code)
+		}
+
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
@@ -99,22 +223,74 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&ro.config, "config", "", "config file (default is $HOME/gomodctl.yml)")
-	rootCmd.PersistentFlags().StringVar(&ro.registry, "registry", "", "URI of the registry to be used for search")
+	rootCmd.PersistentFlags().StringVar(&ro.registry, "registry", "", "URI of the registry to be used for search, a comma separated list is tried in order and the first responsive one is used")
+	rootCmd.PersistentFlags().StringVar(&ro.index, "index", "", "URL or file of a static JSON package index used by search and info instead of the registry")
+	rootCmd.PersistentFlags().StringVar(&ro.token, "token", "", "bearer token sent to the registry (default is $GOMODCTL_TOKEN)")
 	rootCmd.PersistentFlags().BoolVar(&ro.json, "json", false, "Print JSON result")
-	rootCmd.PersistentFlags().StringVar(&ro.path, "path", "", "Optional go.mod parent directory")
+	rootCmd.PersistentFlags().StringVar(&ro.format, "format", printer.FormatTable, "output format: "+strings.Join(printer.Formats, ", ")+", check, scan, license and outdated support yaml too, check and scan support junit, scan supports sarif and jsonl")
+	rootCmd.PersistentFlags().MarkDeprecated("json", "use --format json instead")
+	rootCmd.PersistentFlags().StringVar(&ro.path, "path", "", "Optional go.mod parent directory, - reads go.mod from stdin, check accepts a glob pattern of module directories")
+	rootCmd.PersistentFlags().StringVar(&ro.output, "output", "", "write the result to the file instead of stdout, logs and progress still go to stderr, scan --format jsonl streams into it")
+	rootCmd.PersistentFlags().StringVar(&ro.color, "color", printer.ColorAuto, "when to color table output: "+strings.Join(printer.ColorModes, ", ")+", auto colors it only in a terminal unless NO_COLOR is set")
+	rootCmd.PersistentFlags().BoolVar(&ro.stdin, "stdin", false, "read go.mod from stdin, same as --path -")
+	rootCmd.PersistentFlags().StringVar(&ro.proxy, "proxy", "", "Go module proxy list used to resolve versions (default is $GOPROXY or https://proxy.golang.org)")
+	rootCmd.PersistentFlags().DurationVar(&ro.cacheTTL, "cache-ttl", module.DefaultCacheTTL, "how long resolved versions are cached on disk")
+	rootCmd.PersistentFlags().BoolVar(&ro.noCache, "no-cache", false, "bypass the version cache")
+	rootCmd.PersistentFlags().IntVar(&ro.concurrency, "concurrency", 0, "number of modules resolved concurrently (default is GOMAXPROCS*4)")
+	rootCmd.PersistentFlags().DurationVar(&ro.timeout, "timeout", httpclient.DefaultTimeout, "timeout of a single HTTP request, 0 disables it")
+	rootCmd.PersistentFlags().IntVar(&ro.retries, "retries", httpclient.DefaultRetries, "number of retries of failed HTTP requests, with exponential backoff")
+	rootCmd.PersistentFlags().Float64Var(&ro.rate, "rate", 0, "maximum number of HTTP requests per second, 0 means no limit")
+	rootCmd.PersistentFlags().DurationVar(&ro.maxRuntime, "max-runtime", 0, "abort the whole command after the duration, e.g. 5m, results resolved so far are still reported, 0 means no limit")
+	rootCmd.PersistentFlags().CountVarP(&ro.verbose, "verbose", "v", "log what gomodctl does to stderr, repeat for debug logs of HTTP requests and cache lookups")
+	rootCmd.PersistentFlags().BoolVarP(&ro.quiet, "quiet", "q", false, "log only errors")
 	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
 	viper.BindPFlag("registry", rootCmd.PersistentFlags().Lookup("registry"))
+	viper.BindPFlag("index", rootCmd.PersistentFlags().Lookup("index"))
+	viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token"))
+	viper.BindEnv("token", "GOMODCTL_TOKEN")
 	viper.BindPFlag("json", rootCmd.PersistentFlags().Lookup("json"))
+	viper.BindPFlag("format", rootCmd.PersistentFlags().Lookup("format"))
 	viper.BindPFlag("path", rootCmd.PersistentFlags().Lookup("path"))
+	viper.BindPFlag("color", rootCmd.PersistentFlags().Lookup("color"))
+	viper.BindPFlag("proxy", rootCmd.PersistentFlags().Lookup("proxy"))
+	viper.BindPFlag("cache_ttl", rootCmd.PersistentFlags().Lookup("cache-ttl"))
+	viper.BindPFlag("no_cache", rootCmd.PersistentFlags().Lookup("no-cache"))
+	viper.BindPFlag("concurrency", rootCmd.PersistentFlags().Lookup("concurrency"))
+	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindPFlag("retries", rootCmd.PersistentFlags().Lookup("retries"))
+	viper.BindPFlag("rate", rootCmd.PersistentFlags().Lookup("rate"))
+	viper.BindPFlag("max_runtime", rootCmd.PersistentFlags().Lookup("max-runtime"))
+}
+
+// wrapExitError prefixes the error of the command with cause, keeping the exit status of an ExitError.
+func wrapExitError(err, cause error) error {
+	var exitErr *internal.ExitError
+	if !errors.As(err, &exitErr) {
+		return fmt.Errorf("%v: %w", cause, err)
+	}
+
+	if exitErr.Err == nil {
+		return &internal.ExitError{Code: exitErr.Code, Err: cause}
+	}
+
+	return &internal.ExitError{Code: exitErr.Code, Err: fmt.Errorf("%v: %w", cause, exitErr.Err)}
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	viper.SetConfigType("yaml")
+	logger.SetLevel(logger.LevelOf(ro.verbose, ro.quiet))
+
+	// commands read --path when their arguments are validated, which is after initConfig.
+	if ro.stdin {
+		ro.path = internal.StdinPath
+	}
 
 	if ro.config != "" {
-		// Use config file from the flag.
+		// Use config file from the flag, its type is detected from the extension, YAML if there is none.
 		viper.SetConfigFile(ro.config)
+		if filepath.Ext(ro.config) == "" {
+			viper.SetConfigType("yaml")
+		}
 	} else {
 		// Find home directory.
 		home, err := homedir.Dir()
@@ -123,23 +299,90 @@ func initConfig() {
 			os.Exit(1)
 		}
 
-		if ro.path != "" {
-			viper.AddConfigPath(ro.path)
+		if ro.path != "" && ro.path != internal.StdinPath {
+			for _, dir := range configDirs(ro.path) {
+				viper.AddConfigPath(dir)
+			}
+		}
+		for _, dir := range configDirs(".") {
+			viper.AddConfigPath(dir)
 		}
-		viper.AddConfigPath(".")
 		viper.AddConfigPath(home)
 
+		// every extension supported by viper is tried, e.g. gomodctl.yml, gomodctl.json or gomodctl.toml.
 		viper.SetConfigName("gomodctl")
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	// read in environment variables that match, e.g. GOMODCTL_REGISTRY for registry or GOMODCTL_CACHE_TTL for cache_ttl.
+	viper.SetEnvPrefix("gomodctl")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	viper.AutomaticEnv()
 
 	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		log.Println("Using config file:", viper.ConfigFileUsed())
-	} else {
-		log.Println(err)
+	err := viper.ReadInConfig()
+
+	var notFound viper.ConfigFileNotFoundError
+	switch {
+	case err == nil:
+		logger.Infof("using config file %s", viper.ConfigFileUsed())
+		ignoreProjectHooks()
+	case errors.As(err, &notFound):
+		logger.Debugf("%v", err)
+	default:
+		logger.Warnf("%v", err)
+	}
+}
+
+// ignoreProjectHooks ignores on_update of a config file found in the module or repository directories,
+// otherwise checking a cloned repository would run any command its config sets.
+// on_update is only read from --config, the config in the home directory and GOMODCTL_ON_UPDATE.
+func ignoreProjectHooks() {
+	if ro.config != "" || !viper.InConfig("on_update") {
+		return
 	}
+
+	if home, err := homedir.Dir(); err == nil && filepath.Dir(viper.ConfigFileUsed()) == home {
+		return
+	}
+
+	logger.Warnf("on_update of %s is ignored, set it in --config or the config in the home directory", viper.ConfigFileUsed())
+	viper.Set("on_update", os.Getenv("GOMODCTL_ON_UPDATE"))
+}
+
+// configDirs returns dir and its parents up to the module or repository root, the first one with go.mod or .git.
+// If there is no such root, every parent up to the file system root is returned.
+func configDirs(dir string) []string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return []string{dir}
+	}
+
+	var dirs []string
+
+	for {
+		dirs = append(dirs, dir)
+
+		if isRoot(dir) {
+			return dirs
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dirs
+		}
+		dir = parent
+	}
+}
+
+// isRoot reports whether dir contains go.mod or .git.
+func isRoot(dir string) bool {
+	for _, name := range []string{"go.mod", ".git"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+
+	return false
 }
 
 func main() {
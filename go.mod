@@ -11,8 +11,11 @@ require (
 	github.com/olekukonko/tablewriter v0.0.4
 	github.com/securego/gosec v0.0.0-20200401082031-e946c8c39989
 	github.com/spf13/cobra v1.1.1
+	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.7.1
 	github.com/stretchr/testify v1.7.0
 	github.com/ulikunitz/xz v0.5.10 // indirect
 	golang.org/x/mod v0.4.1
+	golang.org/x/term v0.0.0-20201126162022-7de9c90e9dd1
+	gopkg.in/yaml.v2 v2.2.8
 )
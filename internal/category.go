@@ -0,0 +1,66 @@
+package internal
+
+import "errors"
+
+// Categories of errors of check results, errors.Is matches the category of a categorized error, see Categorize.
+var (
+	// ErrNetwork is the category of requests which failed without a response, e.g. a timeout or a refused connection.
+	// Retrying may fix them.
+	ErrNetwork = errors.New("network error")
+	// ErrNotFound is the category of modules and versions which don't exist on any proxy.
+	ErrNotFound = errors.New("not found")
+	// ErrProxy is the category of unexpected responses of a proxy, e.g. 500 Internal Server Error or 403 Forbidden.
+	ErrProxy = errors.New("proxy error")
+	// ErrParse is the category of responses and versions which can't be parsed.
+	ErrParse = errors.New("parse error")
+)
+
+// categories are the names of the categories in output, see ErrorCategory.
+var categories = []struct {
+	err  error
+	name string
+}{
+	{ErrNetwork, "network"},
+	{ErrNotFound, "not-found"},
+	{ErrProxy, "proxy"},
+	{ErrParse, "parse"},
+}
+
+type categorizedError struct {
+	category error
+	err      error
+}
+
+func (e *categorizedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *categorizedError) Unwrap() error {
+	return e.err
+}
+
+func (e *categorizedError) Is(target error) bool {
+	return target == e.category
+}
+
+// Categorize returns the error in the category, its message doesn't change and errors.Is matches both
+// the category and the error. A nil error stays nil.
+func Categorize(category, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &categorizedError{category: category, err: err}
+}
+
+// ErrorCategory returns the name of the category of the error: network, not-found, proxy or parse,
+// empty if it has none.
+func ErrorCategory(err error) string {
+	for _, c := range categories {
+		if errors.Is(err, c.err) {
+			return c.name
+		}
+	}
+
+	return ""
+}
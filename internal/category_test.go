@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorize(t *testing.T) {
+	errTest := errors.New("test")
+
+	err := Categorize(ErrNotFound, errTest)
+	assert.Equal(t, "test", err.Error())
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.Is(err, errTest))
+	assert.False(t, errors.Is(err, ErrNetwork))
+	assert.Equal(t, "not-found", ErrorCategory(err))
+
+	assert.Equal(t, "network", ErrorCategory(fmt.Errorf("example.com/a: %w", Categorize(ErrNetwork, errTest))))
+	assert.Equal(t, "proxy", ErrorCategory(Categorize(ErrProxy, errTest)))
+	assert.Equal(t, "parse", ErrorCategory(Categorize(ErrParse, errTest)))
+	assert.Empty(t, ErrorCategory(errTest))
+	assert.Empty(t, ErrorCategory(nil))
+
+	assert.NoError(t, Categorize(ErrParse, nil))
+}
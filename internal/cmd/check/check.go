@@ -1,22 +1,99 @@
 package check
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/logger"
 	"github.com/beatlabs/gomodctl/internal/printer"
+	"github.com/beatlabs/gomodctl/internal/progress"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // Checker is exported.
 type Checker interface {
-	Check(path string) (map[string]internal.CheckResult, error)
+	Check(path string, options internal.CheckOptions) (map[string]internal.CheckResult, error)
+	CheckModule(modulePath, version string, options internal.CheckOptions) (internal.CheckResult, error)
+	Unused(path string) ([]internal.UnusedModule, error)
+	Severities(results map[string]internal.CheckResult) (map[string]internal.Severity, error)
 }
 
 // Options is exported.
 type Options struct {
-	Path string
-	JSON bool
+	// Module and Version are set when a single module is checked, go.mod isn't read then.
+	Module     string
+	Version    string
+	Path       string
+	JSON       bool
+	Format     string
+	OnlyMajor  bool
+	OnlyMinor  bool
+	OnlyPatch  bool
+	Exclude    []string
+	DirectOnly bool
+	Unused     bool
+	Pre        bool
+	FailOn     string
+	ExitCode   int
+	StaleAfter string
+	GroupBy    string
+	Lock       string
+	WriteLock  string
+	Since      string
+	GoVersion  string
+	Changed    bool
+	Vendor     bool
+	Secure     bool
+	// Exec is the command template run for every module with an update, on_update config is used if it's empty.
+	Exec     string
+	ExecFail bool
+	// Prioritize orders the printed modules, direct dependencies are listed first if it's empty.
+	Prioritize string
+	// FailOnError makes check exit with ExitCodeError if any module failed to resolve, the others are reported anyway.
+	FailOnError bool
+	// ModulesFile lists modules checked instead of the requirements of go.mod, see readModulesFile.
+	ModulesFile string
+	// Notes adds release notes URLs of the latest versions, see internal.ReleaseNotesURL.
+	Notes bool
+	// Watch is the interval of polls of ExecuteWatch, check runs once if it is empty.
+	Watch string
+	// Base is a git revision whose go.mod the modules are compared with, only added and changed ones are checked.
+	Base string
+	// ModuleRetries is how often versions of a module are looked up again after a network error.
+	ModuleRetries int
+	// TimeoutPerModule limits resolving of each module, the others are still checked if one times out.
+	TimeoutPerModule time.Duration
+
+	staleAfter time.Duration
+	watch      time.Duration
+	since      time.Time
+	hook       *internal.Hook
+	priority   internal.Priority
+}
+
+const (
+	// ExitCodeError is the exit status when check fails.
+	ExitCodeError = 2
+	// DefaultModuleRetries is the default of --module-retries, requests are retried by the HTTP client too, see --retries.
+	DefaultModuleRetries = 1
+
+	failOnAny = "any"
+)
+
+// failOnTypes maps --fail-on values to the update types which fail the check.
+var failOnTypes = map[string][]internal.UpdateType{
+	failOnAny:                    {internal.UpdateMajor, internal.UpdateMinor, internal.UpdatePatch},
+	string(internal.UpdateMajor): {internal.UpdateMajor},
+	string(internal.UpdateMinor): {internal.UpdateMajor, internal.UpdateMinor},
+	string(internal.UpdatePatch): {internal.UpdateMajor, internal.UpdateMinor, internal.UpdatePatch},
 }
 
 // NewCmdCheck returns an instance of Search command.
@@ -24,39 +101,550 @@ func NewCmdCheck(checker Checker) *cobra.Command {
 	o := Options{}
 
 	cmd := &cobra.Command{
-		Use:   "check [module name]",
+		Use:   "check [module name[@version]]",
 		Short: "check local module for updates",
-		Long:  `get list of local module and check them for updates`,
+		Long: `get list of local module and check them for updates.
+If a module is given, only the latest version of that module is resolved without reading go.mod,
+with an optional version the update type is computed relative to it.`,
 		Args: func(cmd *cobra.Command, args []string) error {
-			return nil
-		},
-		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) > 1 {
+				return errors.New("at most one module can be checked")
+			}
+
 			o.Fill(cmd)
-			o.Execute(checker)
+
+			if len(args) == 1 {
+				o.Module, o.Version = splitModuleVersion(args[0])
+			}
+
+			return o.validate()
 		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if o.watch > 0 {
+				return o.ExecuteWatch(cmd.Context(), checker)
+			}
+
+			return o.Execute(cmd.Context(), checker)
+		},
+		Annotations: map[string]string{printer.FormatsAnnotation: printer.FormatJUnit + " " + printer.FormatYAML},
 	}
 
+	cmd.Flags().Bool("only-major", false, "only report modules with a higher major version available")
+	cmd.Flags().Bool("only-minor", false, "only report the latest version within the current major")
+	cmd.Flags().Bool("only-patch", false, "only report the latest patch within the current major.minor")
+	cmd.Flags().StringArray("exclude", nil, "glob pattern of module paths to ignore, can be repeated")
+	cmd.Flags().Bool("direct-only", false, "leave out modules required with an // indirect comment")
+	cmd.Flags().Bool("pre", false, "consider prereleases, by default they are reported only if there is no stable version")
+	cmd.Flags().Bool("unused", false, "report modules required by go.mod which go mod tidy would remove instead of updates")
+	cmd.Flags().String("fail-on", failOnAny, "exit with --exit-code on updates of the given type or higher: any, major, minor or patch")
+	cmd.Flags().Int("exit-code", 1, "exit status when updates are available")
+	cmd.Flags().Bool("fail-on-error", false, "exit with status 2 if any module failed to resolve, results of the others are printed first")
+	cmd.Flags().Duration("timeout-per-module", 0, "fail a module which isn't resolved within the duration, e.g. 5s, the others are still checked, 0 means no limit")
+	cmd.Flags().Int("module-retries", DefaultModuleRetries, "number of retries of a module whose versions failed with a network error, after a backoff from 1s, other errors fail at once")
+	cmd.Flags().String("stale-after", "", "mark modules whose current version was published longer ago, e.g. 90d")
+	cmd.Flags().String("group-by", "", "print a section per group: update-type, host or org, JSON and YAML aren't grouped")
+	cmd.Flags().String("lock", "", "only report modules whose latest version changed since the given lock file was written")
+	cmd.Flags().String("write-lock", "", "write resolved latest versions to the given lock file")
+	cmd.Flags().String("since", "", "only report modules whose latest version was published since, e.g. 7d or 2021-06-01")
+	cmd.Flags().String("go-version", "", "skip versions whose go.mod requires a newer Go, e.g. 1.21 (default is the version of the go command)")
+	cmd.Flags().Bool("changed", false, "only check modules whose require lines in go.mod changed since git HEAD")
+	cmd.Flags().String("base", "", "only check modules added or changed since go.mod of the git revision, e.g. origin/main, downgrades fail the check")
+	cmd.Flags().String("modules-file", "", "check the modules listed in the file, one module[@version] per line, instead of go.mod")
+	cmd.Flags().Bool("vendor", false, "fail if vendor/modules.txt doesn't match go.mod, vendored versions are checked for updates")
+	cmd.Flags().Bool("secure", false, "recommend the lowest version fixing all known advisories of vulnerable modules, looked up in the OSV database")
+	cmd.Flags().String("exec", "", "command run for every module with an update, e.g. 'notify {{.Path}} {{.Local}} {{.Latest}}'")
+	cmd.Flags().Bool("exec-fail", false, "exit with status 2 if a command of --exec fails")
+	cmd.Flags().Bool("notes", false, "add a release notes URL of the latest version of each module with an update, GitHub modules only")
+	cmd.Flags().String("watch", "", "check again every interval, e.g. 1h or 1d, redrawing the table and marking new releases, until interrupted")
+	cmd.Flags().String("prioritize", "", "order of printed modules: security, staleness, type or alpha (default is direct dependencies first)")
+
 	return cmd
 }
 
 // Fill fills flags into options.
 func (o *Options) Fill(cmd *cobra.Command) {
-	o.JSON, _ = cmd.Flags().GetBool("json")
+	o.Format = printer.FormatOf(cmd.Flags())
+	o.JSON = o.Format == printer.FormatJSON
 	o.Path, _ = cmd.Flags().GetString("path")
+	o.OnlyMajor, _ = cmd.Flags().GetBool("only-major")
+	o.OnlyMinor, _ = cmd.Flags().GetBool("only-minor")
+	o.OnlyPatch, _ = cmd.Flags().GetBool("only-patch")
+	o.Exclude, _ = cmd.Flags().GetStringArray("exclude")
+	o.DirectOnly, _ = cmd.Flags().GetBool("direct-only")
+	o.Unused, _ = cmd.Flags().GetBool("unused")
+	o.Pre, _ = cmd.Flags().GetBool("pre")
+	o.FailOn, _ = cmd.Flags().GetString("fail-on")
+	o.ExitCode, _ = cmd.Flags().GetInt("exit-code")
+	o.FailOnError, _ = cmd.Flags().GetBool("fail-on-error")
+	o.ModuleRetries, _ = cmd.Flags().GetInt("module-retries")
+	o.TimeoutPerModule, _ = cmd.Flags().GetDuration("timeout-per-module")
+	o.StaleAfter, _ = cmd.Flags().GetString("stale-after")
+	o.GroupBy, _ = cmd.Flags().GetString("group-by")
+	o.Lock, _ = cmd.Flags().GetString("lock")
+	o.WriteLock, _ = cmd.Flags().GetString("write-lock")
+	o.Since, _ = cmd.Flags().GetString("since")
+	o.GoVersion, _ = cmd.Flags().GetString("go-version")
+	o.Changed, _ = cmd.Flags().GetBool("changed")
+	o.ModulesFile, _ = cmd.Flags().GetString("modules-file")
+	o.Base, _ = cmd.Flags().GetString("base")
+	o.Vendor, _ = cmd.Flags().GetBool("vendor")
+	o.Secure, _ = cmd.Flags().GetBool("secure")
+	o.Exec, _ = cmd.Flags().GetString("exec")
+	if o.Exec == "" {
+		o.Exec = viper.GetString("on_update")
+	}
+	o.ExecFail, _ = cmd.Flags().GetBool("exec-fail")
+	o.Prioritize, _ = cmd.Flags().GetString("prioritize")
+	o.Notes, _ = cmd.Flags().GetBool("notes")
+	o.Watch, _ = cmd.Flags().GetString("watch")
+}
+
+func (o *Options) validate() error {
+	n := 0
+	for _, b := range []bool{o.OnlyMajor, o.OnlyMinor, o.OnlyPatch} {
+		if b {
+			n++
+		}
+	}
+
+	if n > 1 {
+		return errors.New("only one of --only-major, --only-minor and --only-patch can be set")
+	}
+
+	if o.Unused && o.Module != "" {
+		return errors.New("--unused can't be used with a module argument")
+	}
+
+	if o.Changed && (o.Unused || o.Module != "") {
+		return errors.New("--changed can't be used with --unused or a module argument")
+	}
+
+	if o.Vendor && (o.Unused || o.Module != "") {
+		return errors.New("--vendor can't be used with --unused or a module argument")
+	}
+
+	if o.Base != "" && (o.Changed || o.Unused || o.Module != "" || o.ModulesFile != "") {
+		return errors.New("--base can't be used with --changed, --unused, --modules-file or a module argument")
+	}
+
+	if o.ModulesFile != "" && (o.Module != "" || o.Unused || o.Changed || o.Vendor) {
+		return errors.New("--modules-file can't be used with a module argument, --unused, --changed or --vendor")
+	}
+
+	if o.Unused && o.Secure {
+		return errors.New("--secure can't be used with --unused")
+	}
+
+	if o.Unused && o.Format == printer.FormatJUnit {
+		return errors.New("--format junit can't be used with --unused")
+	}
+
+	if o.Unused && (o.Lock != "" || o.WriteLock != "") {
+		return errors.New("--lock and --write-lock can't be used with --unused")
+	}
+
+	if o.ModuleRetries < 0 {
+		return fmt.Errorf("invalid --module-retries value %d, it can't be negative", o.ModuleRetries)
+	}
+
+	if o.TimeoutPerModule < 0 {
+		return fmt.Errorf("invalid --timeout-per-module value %s, it can't be negative", o.TimeoutPerModule)
+	}
+
+	if _, ok := failOnTypes[o.FailOn]; !ok {
+		return fmt.Errorf("invalid --fail-on value %q, must be any, major, minor or patch", o.FailOn)
+	}
+
+	if _, ok := groupers[o.GroupBy]; o.GroupBy != "" && !ok {
+		return fmt.Errorf("invalid --group-by value %q, must be update-type, host or org", o.GroupBy)
+	}
+
+	if o.StaleAfter != "" {
+		var err error
+		if o.staleAfter, err = internal.ParseDays(o.StaleAfter); err != nil {
+			return fmt.Errorf("--stale-after: %w", err)
+		}
+	}
+
+	if o.Since != "" {
+		var err error
+		if o.since, err = internal.ParseSince(o.Since, time.Now()); err != nil {
+			return fmt.Errorf("--since: %w", err)
+		}
+	}
+
+	if o.Prioritize != "" {
+		var err error
+		if o.priority, err = internal.ParsePriority(o.Prioritize); err != nil {
+			return fmt.Errorf("--prioritize: %w", err)
+		}
+	}
+
+	if o.Watch != "" {
+		var err error
+		if o.watch, err = internal.ParseDays(o.Watch); err != nil || o.watch <= 0 {
+			return fmt.Errorf("invalid --watch value %q, a positive duration such as 1h or 1d is expected", o.Watch)
+		}
+
+		if o.Format != printer.FormatTable || o.Unused || o.Lock != "" || o.WriteLock != "" || o.Exec != "" || o.GroupBy != "" {
+			return errors.New("--watch prints tables only, it can't be used with --format, --unused, --lock, --write-lock, --exec or --group-by")
+		}
+	}
+
+	if o.Exec != "" && !o.Unused {
+		var err error
+		if o.hook, err = internal.NewHook(o.Exec); err != nil {
+			return fmt.Errorf("--exec: %w", err)
+		}
+	}
+
+	return nil
 }
 
-// Execute is exported.
-func (o *Options) Execute(checker Checker) {
-	checkResults, err := checker.Check(o.Path)
+func (o *Options) checkOptions() internal.CheckOptions {
+	options := internal.CheckOptions{
+		Scope:         internal.ScopeLatest,
+		Exclude:       o.Exclude,
+		DirectOnly:    o.DirectOnly,
+		Prerelease:    o.Pre,
+		GoVersion:     o.GoVersion,
+		Changed:       o.Changed,
+		Base:          o.Base,
+		Vendor:        o.Vendor,
+		Secure:        o.Secure,
+		Retries:       o.ModuleRetries,
+		ModuleTimeout: o.TimeoutPerModule,
+		// every poll of --watch has to see new releases.
+		NoCache: o.watch > 0,
+	}
+
+	switch {
+	case o.OnlyMajor:
+		options.Scope = internal.ScopeMajor
+	case o.OnlyMinor:
+		options.Scope = internal.ScopeMinor
+	case o.OnlyPatch:
+		options.Scope = internal.ScopePatch
+	}
+
+	return options
+}
+
+// Execute is exported, hooks of --exec are killed once ctx is done.
+func (o *Options) Execute(ctx context.Context, checker Checker) error {
+	if o.Unused {
+		return o.executeUnused(checker)
+	}
+
+	checkResults, err := o.check(checker)
+	if err != nil {
+		// an aborted check, e.g. by --max-runtime, still reports the modules resolved so far.
+		if checkResults != nil {
+			printer.Print(NewResultPrinter(checkResults), o.Format)
+		}
+
+		return &internal.ExitError{Code: ExitCodeError, Err: err}
+	}
+
+	checkResults, err = o.applyLock(checkResults)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return &internal.ExitError{Code: ExitCodeError, Err: err}
 	}
 
-	rp := NewResultPrinter(checkResults)
-	if o.JSON {
-		printer.PrintJSON(rp)
+	if o.Since != "" {
+		checkResults = releasedSince(checkResults, o.since)
+	}
+
+	order, err := o.order(checker, checkResults)
+	if err != nil {
+		return &internal.ExitError{Code: ExitCodeError, Err: err}
+	}
+
+	if o.GroupBy != "" && !o.JSON && o.Format != printer.FormatYAML && o.Format != printer.FormatJUnit {
+		o.printGroups(checkResults, order)
 	} else {
-		printer.PrintTable(rp)
+		rp := NewResultPrinter(checkResults)
+		rp.StaleAfter = o.staleAfter
+		rp.FailOn = failOnTypes[o.FailOn]
+		rp.Order = order
+		rp.Notes = o.Notes
+		printer.Print(rp, o.Format)
+	}
+
+	if o.Format == printer.FormatTable {
+		fmt.Fprintf(printer.Output(), "\n%s\n", newSummary(checkResults))
+	}
+
+	if err := o.runHooks(ctx, checkResults); err != nil && o.ExecFail {
+		return &internal.ExitError{Code: ExitCodeError, Err: err}
+	}
+
+	if n := vendorMismatches(checkResults); n > 0 {
+		return &internal.ExitError{Code: ExitCodeError, Err: fmt.Errorf("%w: %d modules differ, run go mod vendor", internal.ErrVendorMismatch, n)}
+	}
+
+	if n := failedModules(checkResults); n > 0 {
+		err := fmt.Errorf("%d of %d modules failed to resolve", n, len(checkResults))
+		if o.FailOnError {
+			return &internal.ExitError{Code: ExitCodeError, Err: err}
+		}
+
+		logger.Warnf("%v", err)
+	}
+
+	if n := taggedPseudoVersions(checkResults); n > 0 {
+		logger.Warnf("%d modules are required at a pseudo-version although they have tagged releases, consider moving to a tag", n)
+	}
+
+	if o.shouldFail(checkResults) {
+		return &internal.ExitError{Code: o.ExitCode}
+	}
+
+	return nil
+}
+
+// order returns module names in order of --prioritize, nil keeps the default order.
+func (o *Options) order(checker Checker, checkResults map[string]internal.CheckResult) ([]string, error) {
+	if o.Prioritize == "" {
+		return nil, nil
 	}
+
+	var severities map[string]internal.Severity
+	if o.priority == internal.PrioritySecurity {
+		var err error
+		if severities, err = checker.Severities(checkResults); err != nil {
+			return nil, fmt.Errorf("--prioritize security: %w", err)
+		}
+	}
+
+	return internal.Prioritize(checkResults, o.priority, severities), nil
+}
+
+// vendorMismatches returns the number of results failed with internal.ErrVendorMismatch.
+func vendorMismatches(checkResults map[string]internal.CheckResult) int {
+	n := 0
+
+	for _, result := range checkResults {
+		if errors.Is(result.Error, internal.ErrVendorMismatch) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// failedModules returns the number of results which failed to resolve, see internal.CheckResult.Failed.
+func failedModules(checkResults map[string]internal.CheckResult) int {
+	n := 0
+
+	for _, result := range checkResults {
+		if result.Failed() {
+			n++
+		}
+	}
+
+	return n
+}
+
+// taggedPseudoVersions returns the number of results required at a pseudo-version which could move to a tag, see internal.CheckResult.TagAvailable.
+func taggedPseudoVersions(checkResults map[string]internal.CheckResult) int {
+	n := 0
+
+	for _, result := range checkResults {
+		if result.TagAvailable() {
+			n++
+		}
+	}
+
+	return n
+}
+
+// executeUnused reports unused modules, it fails with --exit-code if there is any.
+func (o *Options) executeUnused(checker Checker) error {
+	unused, err := checker.Unused(o.Path)
+	if err != nil {
+		return &internal.ExitError{Code: ExitCodeError, Err: err}
+	}
+
+	printer.Print(NewUnusedPrinter(unused), o.Format)
+
+	if len(unused) > 0 {
+		return &internal.ExitError{Code: o.ExitCode}
+	}
+
+	return nil
+}
+
+func (o *Options) check(checker Checker) (map[string]internal.CheckResult, error) {
+	if o.Module == "" {
+		options := o.checkOptions()
+
+		if o.ModulesFile != "" {
+			modules, err := readModulesFile(o.ModulesFile)
+			if err != nil {
+				return nil, err
+			}
+
+			options.Modules = modules
+		}
+
+		spinner := progress.StartIfEnabled(o.Format, "checked %d/%d modules")
+		defer spinner.Stop()
+
+		options.Progress = spinner.Update
+
+		return checker.Check(o.Path, options)
+	}
+
+	result, err := checker.CheckModule(o.Module, o.Version, o.checkOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]internal.CheckResult{o.Module: result}, nil
+}
+
+// applyLock writes --write-lock with every result and returns only results changed since --lock.
+// The lock is read before it is written, so both may name the same file.
+func (o *Options) applyLock(checkResults map[string]internal.CheckResult) (map[string]internal.CheckResult, error) {
+	changed := checkResults
+
+	if o.Lock != "" {
+		lock, err := internal.ReadLock(o.Lock)
+		if err != nil {
+			return nil, err
+		}
+
+		changed = make(map[string]internal.CheckResult)
+		for name, result := range checkResults {
+			if lock.Changed(name, result) {
+				changed[name] = result
+			}
+		}
+	}
+
+	if o.WriteLock != "" {
+		err := internal.WriteLock(o.WriteLock, internal.NewLock(checkResults))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return changed, nil
+}
+
+// runHooks runs the --exec hook for every module with an update in order of module paths,
+// failing hooks are logged and an error is returned if any failed.
+func (o *Options) runHooks(ctx context.Context, checkResults map[string]internal.CheckResult) error {
+	if o.hook == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(checkResults))
+	for name, result := range checkResults {
+		if result.Updatable() {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	failed := 0
+
+	for _, name := range names {
+		// output of hooks goes to stderr so it doesn't mix with the printed result.
+		if err := o.hook.Run(ctx, name, checkResults[name], os.Stderr); err != nil {
+			logger.Warnf("%v", err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d hooks failed", failed, len(names))
+	}
+
+	return nil
+}
+
+// releasedSince returns results whose latest version was published since the given time, errors are kept.
+func releasedSince(checkResults map[string]internal.CheckResult, since time.Time) map[string]internal.CheckResult {
+	released := make(map[string]internal.CheckResult)
+
+	for name, result := range checkResults {
+		if result.Error != nil || result.ReleasedSince(since) {
+			released[name] = result
+		}
+	}
+
+	return released
+}
+
+// splitModuleVersion splits module@version argument, version is empty if it isn't given.
+func splitModuleVersion(arg string) (string, string) {
+	if i := strings.LastIndex(arg, "@"); i >= 0 {
+		return arg[:i], arg[i+1:]
+	}
+
+	return arg, ""
+}
+
+// readModulesFile reads the modules of --modules-file, one module path per line with an optional @version.
+// Empty lines and lines starting with # are skipped.
+func readModulesFile(file string) ([]internal.Requirement, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := []internal.Requirement{}
+
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		path, version := splitModuleVersion(line)
+		if path == "" || strings.ContainsAny(line, " \t") {
+			return nil, fmt.Errorf("%s:%d: invalid module %q, module[@version] is expected", file, i+1, line)
+		}
+
+		modules = append(modules, internal.Requirement{Path: path, Version: version})
+	}
+
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("%s lists no modules", file)
+	}
+
+	return modules, nil
+}
+
+// shouldFail reports whether any module has an update matching --fail-on.
+// Modules downgraded since --base fail it too.
+func (o *Options) shouldFail(checkResults map[string]internal.CheckResult) bool {
+	for _, result := range checkResults {
+		if result.BaseDowngrade() || fails(result, failOnTypes[o.FailOn]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fails reports whether the result has an update of one of the given types, failed results don't.
+func fails(result internal.CheckResult, types []internal.UpdateType) bool {
+	if result.Error != nil {
+		return false
+	}
+
+	updateType := result.UpdateType()
+	for _, t := range types {
+		if updateType == t {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,104 @@
+package check
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+)
+
+const (
+	groupUpToDate = "up-to-date"
+	groupError    = "error"
+)
+
+// groupers maps --group-by values to functions returning the group of a module.
+var groupers = map[string]func(name string, result internal.CheckResult) string{
+	"update-type": func(_ string, result internal.CheckResult) string {
+		if result.Error != nil {
+			return groupError
+		}
+
+		if t := result.UpdateType(); t != internal.UpdateNone {
+			return string(t)
+		}
+
+		return groupUpToDate
+	},
+	"host": func(name string, _ internal.CheckResult) string {
+		return pathElement(name, 0)
+	},
+	"org": func(name string, _ internal.CheckResult) string {
+		return pathElement(name, 1)
+	},
+}
+
+// updateTypeOrder lists update-type groups from the most urgent.
+var updateTypeOrder = map[string]int{
+	string(internal.UpdateMajor): 0,
+	string(internal.UpdateMinor): 1,
+	string(internal.UpdatePatch): 2,
+	groupUpToDate:                3,
+	groupError:                   4,
+}
+
+// pathElement returns the i-th element of the module path, - if there is none.
+func pathElement(name string, i int) string {
+	elements := strings.Split(name, "/")
+	if i >= len(elements) {
+		return "-"
+	}
+
+	return elements[i]
+}
+
+// groupResults splits results by the group of --group-by and returns the groups in print order.
+func groupResults(results map[string]internal.CheckResult, by string) ([]string, map[string]map[string]internal.CheckResult) {
+	grouper := groupers[by]
+	groups := make(map[string]map[string]internal.CheckResult)
+
+	for name, result := range results {
+		group := grouper(name, result)
+		if groups[group] == nil {
+			groups[group] = make(map[string]internal.CheckResult)
+		}
+
+		groups[group][name] = result
+	}
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if by == "update-type" {
+			return updateTypeOrder[names[i]] < updateTypeOrder[names[j]]
+		}
+
+		return names[i] < names[j]
+	})
+
+	return names, groups
+}
+
+// printGroups prints a section with a header and the number of modules per group.
+func (o *Options) printGroups(results map[string]internal.CheckResult, order []string) {
+	names, groups := groupResults(results, o.GroupBy)
+
+	for i, name := range names {
+		if i > 0 {
+			fmt.Fprintln(printer.Output())
+		}
+
+		fmt.Fprintf(printer.Output(), "%s (%d)\n", name, len(groups[name]))
+
+		rp := NewResultPrinter(groups[name])
+		rp.StaleAfter = o.staleAfter
+		rp.Order = order
+		rp.Notes = o.Notes
+		printer.Print(rp, o.Format)
+	}
+}
@@ -1,15 +1,73 @@
 package check
 
 import (
+	"errors"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Masterminds/semver"
 	"github.com/beatlabs/gomodctl/internal"
 	"github.com/beatlabs/gomodctl/internal/printer"
 )
 
+// SchemaVersion is version of the JSON output, it is increased on breaking changes.
+const SchemaVersion = 1
+
+// JSONResult is the JSON document of check result.
+type JSONResult struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	GeneratedAt   time.Time    `json:"generatedAt"`
+	Modules       []JSONModule `json:"modules"`
+	Summary       JSONSummary  `json:"summary"`
+}
+
+// JSONModule is check result of a single module.
+type JSONModule struct {
+	Path           string              `json:"path"`
+	LocalVersion   *string             `json:"localVersion"`
+	LocalVersions  []string            `json:"localVersions"`
+	LatestVersion  *string             `json:"latestVersion"`
+	LatestPath     *string             `json:"latestPath"`
+	SkippedVersion *string             `json:"skippedVersion"`
+	SkippedGo      *string             `json:"skippedGo"`
+	UpdateType     internal.UpdateType `json:"updateType"`
+	Replaced       bool                `json:"replaced"`
+	Indirect       bool                `json:"indirect"`
+	Retracted      bool                `json:"retracted"`
+	Pseudo         bool                `json:"pseudo"`
+	Untagged       bool                `json:"untagged"`
+	Deprecated     *string             `json:"deprecated"`
+	Advisories     []string            `json:"advisories"`
+	SecureVersion  *string             `json:"secureVersion"`
+	Prerelease     bool                `json:"prerelease"`
+	LocalTime      *time.Time          `json:"localTime"`
+	LatestTime     *time.Time          `json:"latestTime"`
+	Stale          bool                `json:"stale"`
+	ReleaseNotes   *string             `json:"releaseNotes"`
+	BaseVersion    *string             `json:"baseVersion"`
+	Added          bool                `json:"added"`
+	Downgraded     bool                `json:"downgraded"`
+	Error          *string             `json:"error"`
+	ErrorCategory  *string             `json:"errorCategory"`
+	TimedOut       bool                `json:"timedOut"`
+}
+
 // ResultPrinter implements Printer interface for Check command.
 type ResultPrinter struct {
 	Result map[string]internal.CheckResult
+	// StaleAfter marks modules whose local version is older, zero disables it.
+	StaleAfter time.Duration
+	// FailOn are update types reported as failures in JUnit output, any update fails if it is nil.
+	FailOn []internal.UpdateType
+	// Order lists module names in print order, names missing from it follow in the default order.
+	Order []string
+	// Notes adds release notes URLs of updates, see internal.ReleaseNotesURL.
+	Notes bool
+	// NewReleases marks modules whose latest version was released since the previous poll of watch.
+	NewReleases map[string]bool
 }
 
 // NewResultPrinter creates a new instance of ResultPrinter.
@@ -19,38 +77,482 @@ func NewResultPrinter(results map[string]internal.CheckResult) *ResultPrinter {
 	}
 }
 
-// TableData returns table friendly result.
+// TableData returns table friendly result, direct dependencies are listed before indirect ones.
 func (p *ResultPrinter) TableData() *printer.TableData {
 	var data [][]string
+	var colors [][]printer.Color
+
+	now := time.Now()
+	secure := p.vulnerable()
+
+	for _, name := range p.names() {
+		result := p.Result[name]
+		released := p.NewReleases[name]
+
+		localVersion := "-"
+		if len(result.LocalVersions) > 1 {
+			localVersion = "[" + strings.Join(localVersions(result), ", ") + "]"
+		} else if result.LocalVersion != nil {
+			localVersion = result.LocalVersion.Original()
+		}
+		if result.Replaced {
+			localVersion += " (replaced)"
+		}
+		if result.Retracted {
+			localVersion += " (RETRACTED)"
+		}
+		if result.TagAvailable() {
+			localVersion += " (pseudo-version)"
+		}
+		localVersion += baseString(result)
+
+		label := name
+		if result.Indirect {
+			label += " (indirect)"
+		}
+		if result.Deprecated != "" {
+			label += " (DEPRECATED: " + result.Deprecated + ")"
+		}
+
+		localTime := dateString(result.LocalTime)
+		if result.Stale(p.StaleAfter, now) {
+			localTime += " (stale)"
+		}
 
-	for name, result := range p.Result {
 		r := []string{
-			name,
-			result.LocalVersion.Original(),
+			label,
+			localVersion,
+			localTime,
 		}
 
 		if result.Error != nil {
-			r = append(r, result.Error.Error())
+			r = append(r, result.Error.Error(), "")
+		} else if result.Untagged {
+			r = append(r, "untagged", "")
 		} else {
-			r = append(r, result.LatestVersion.Original())
+			latestVersion := result.LatestVersion.Original()
+			if result.LatestVersion.Prerelease() != "" {
+				latestVersion += " (prerelease)"
+			}
+			if result.LatestPath != "" {
+				latestVersion += " (import path change: " + result.LatestPath + ")"
+			}
+			if result.SkippedVersion != nil {
+				latestVersion += " (" + result.SkippedVersion.Original() + " requires go " + result.SkippedGo + ")"
+			}
+			if released {
+				latestVersion += " (NEW)"
+			}
+
+			r = append(r, latestVersion, dateString(result.LatestTime))
+		}
+
+		if secure {
+			r = append(r, secureString(result))
+		}
+
+		if p.Notes {
+			notes := "-"
+			if url := releaseNotes(name, result); url != "" {
+				notes = url
+			}
+
+			r = append(r, notes)
 		}
 
 		data = append(data, r)
+		colors = append(colors, []printer.Color{0: deprecationColor(result), 3: updateColor(result)})
+	}
+
+	header := []string{"Module", "Current", "Published", "Latest", "Published"}
+	footer := []string{"", "", "", "number of modules", strconv.Itoa(len(p.Result))}
+	if secure {
+		header = append(header, "Secure")
+		footer = append([]string{""}, footer...)
+	}
+
+	if p.Notes {
+		header = append(header, "Release notes")
+		footer = append([]string{""}, footer...)
 	}
 
 	td := &printer.TableData{
-		Header:       []string{"Module", "Current", "Latest"},
-		Footer:       []string{"", "number of modules", strconv.Itoa(len(p.Result))},
+		Header:       header,
+		Footer:       footer,
 		RowSeparator: "-",
 		ShowBorder:   false,
 		ShowRowLine:  false,
 		Data:         data,
+		Colors:       colors,
 	}
 
 	return td
 }
 
+// baseString describes the change of the module since the base go.mod, empty without --base.
+func baseString(result internal.CheckResult) string {
+	switch {
+	case result.Base == nil:
+		return ""
+	case result.Base.Change == internal.DiffAdded:
+		return " (added)"
+	case result.BaseDowngrade():
+		return " (DOWNGRADED from " + result.Base.OldVersion.Original() + ")"
+	case result.Base.OldVersion != nil:
+		return " (from " + result.Base.OldVersion.Original() + ")"
+	}
+
+	return ""
+}
+
+// releaseNotes returns the release notes URL of the update of the module, empty if it has none.
+// A module checked without a current version gets the release page of the latest version.
+func releaseNotes(name string, result internal.CheckResult) string {
+	switch {
+	case result.Updatable():
+		return internal.ReleaseNotesURL(name, result.LocalVersion.Original(), result.LatestVersion.Original())
+	case result.Error == nil && result.LocalVersion == nil && result.LatestVersion != nil:
+		return internal.ReleaseNotesURL(name, "", result.LatestVersion.Original())
+	}
+
+	return ""
+}
+
+// vulnerable reports whether any module has advisories, only then the Secure column is printed.
+func (p *ResultPrinter) vulnerable() bool {
+	for _, result := range p.Result {
+		if len(result.Advisories) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// secureString returns the lowest version fixing the advisories of the module, "-" if it has none.
+func secureString(result internal.CheckResult) string {
+	switch {
+	case len(result.Advisories) == 0:
+		return "-"
+	case result.SecureVersion == nil:
+		return "not fixed (" + strings.Join(result.Advisories, ", ") + ")"
+	}
+
+	return result.SecureVersion.Original() + " (" + strings.Join(result.Advisories, ", ") + ")"
+}
+
+// JUnitData returns result as a JUnit test suite, modules with an update matching FailOn fail and those which can't be checked are errors.
+func (p *ResultPrinter) JUnitData() *printer.JUnitTestSuite {
+	failOn := p.FailOn
+	if failOn == nil {
+		failOn = failOnTypes[failOnAny]
+	}
+
+	suite := &printer.JUnitTestSuite{Name: "gomodctl check"}
+
+	for _, name := range p.names() {
+		result := p.Result[name]
+
+		c := printer.JUnitTestCase{Name: name, Classname: "gomodctl.check"}
+
+		switch {
+		case result.Error != nil:
+			c.Error = &printer.JUnitMessage{Message: result.Error.Error(), Type: "CheckError"}
+		case result.BaseDowngrade():
+			c.Failure = &printer.JUnitMessage{
+				Message: fmt.Sprintf("downgraded since the base: %s -> %s", result.Base.OldVersion.Original(), result.LocalVersion.Original()),
+				Type:    "downgrade",
+			}
+		case fails(result, failOn):
+			c.Failure = &printer.JUnitMessage{
+				Message: fmt.Sprintf("%s update available: %s -> %s", result.UpdateType(), result.LocalVersion.Original(), result.LatestVersion.Original()),
+				Type:    string(result.UpdateType()),
+				Text:    junitDetails(result),
+			}
+		case result.Updatable():
+			c.SystemOut = fmt.Sprintf("%s update available: %s -> %s", result.UpdateType(), result.LocalVersion.Original(), result.LatestVersion.Original())
+		case result.Deprecated != "":
+			c.SystemOut = "module is deprecated: " + result.Deprecated
+		case result.Untagged:
+			c.SystemOut = "module has no tagged releases"
+		}
+
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	return suite
+}
+
+// junitDetails describes the update of a failing JUnit test case.
+func junitDetails(result internal.CheckResult) string {
+	details := []string{"current: " + result.LocalVersion.Original(), "latest: " + result.LatestVersion.Original()}
+
+	if result.LatestPath != "" {
+		details = append(details, "import path change: "+result.LatestPath)
+	}
+	if !result.LatestTime.IsZero() {
+		details = append(details, "published: "+dateString(result.LatestTime))
+	}
+	if result.Retracted {
+		details = append(details, "current version is retracted")
+	}
+	if result.TagAvailable() {
+		details = append(details, "current version is a pseudo-version, move to a tagged release")
+	}
+	if result.Deprecated != "" {
+		details = append(details, "module is deprecated: "+result.Deprecated)
+	}
+	if len(result.Advisories) > 0 {
+		details = append(details, "secure: "+secureString(result))
+	}
+
+	return strings.Join(details, "\n")
+}
+
+// deprecationColor highlights modules deprecated by their authors in red.
+func deprecationColor(result internal.CheckResult) printer.Color {
+	if result.Deprecated != "" {
+		return printer.ColorRed
+	}
+
+	return printer.ColorNone
+}
+
+// updateColor highlights the latest version by kind of the update, major updates are red, minor yellow and patch green.
+// Network errors and timed out modules, which a later check may not get, are yellow and errors of other categories red.
+func updateColor(result internal.CheckResult) printer.Color {
+	if result.Error != nil {
+		return errorColor(result.Error)
+	}
+
+	switch result.UpdateType() {
+	case internal.UpdateMajor:
+		return printer.ColorRed
+	case internal.UpdateMinor:
+		return printer.ColorYellow
+	case internal.UpdatePatch:
+		return printer.ColorGreen
+	}
+
+	return printer.ColorNone
+}
+
+func errorColor(err error) printer.Color {
+	switch {
+	case errors.Is(err, internal.ErrNetwork), errors.Is(err, internal.ErrModuleTimeout):
+		return printer.ColorYellow
+	case internal.ErrorCategory(err) != "":
+		return printer.ColorRed
+	}
+
+	return printer.ColorNone
+}
+
 // JSONData returns JSON friendly result.
 func (p *ResultPrinter) JSONData() interface{} {
-	return p.Result
+	modules := make([]JSONModule, 0, len(p.Result))
+
+	now := time.Now()
+
+	for _, name := range p.names() {
+		result := p.Result[name]
+
+		m := JSONModule{
+			Path:          name,
+			LocalVersion:  versionString(result.LocalVersion),
+			LocalVersions: localVersions(result),
+			LatestVersion: versionString(result.LatestVersion),
+			UpdateType:    result.UpdateType(),
+			Replaced:      result.Replaced,
+			Indirect:      result.Indirect,
+			Retracted:     result.Retracted,
+			Pseudo:        result.Pseudo,
+			Untagged:      result.Untagged,
+			Prerelease:    !result.Untagged && result.LatestVersion != nil && result.LatestVersion.Prerelease() != "",
+			LocalTime:     timePointer(result.LocalTime),
+			LatestTime:    timePointer(result.LatestTime),
+			Stale:         result.Stale(p.StaleAfter, now),
+			TimedOut:      result.TimedOut(),
+			Advisories:    result.Advisories,
+			SecureVersion: versionString(result.SecureVersion),
+		}
+
+		if m.Advisories == nil {
+			m.Advisories = []string{}
+		}
+
+		if result.LatestPath != "" {
+			latestPath := result.LatestPath
+			m.LatestPath = &latestPath
+		}
+
+		if result.Deprecated != "" {
+			deprecated := result.Deprecated
+			m.Deprecated = &deprecated
+		}
+
+		if url := releaseNotes(name, result); p.Notes && url != "" {
+			m.ReleaseNotes = &url
+		}
+
+		if result.Base != nil {
+			m.BaseVersion = versionString(result.Base.OldVersion)
+			m.Added = result.Base.Change == internal.DiffAdded
+			m.Downgraded = result.BaseDowngrade()
+		}
+
+		if result.SkippedVersion != nil {
+			m.SkippedVersion = versionString(result.SkippedVersion)
+			skippedGo := result.SkippedGo
+			m.SkippedGo = &skippedGo
+		}
+
+		if result.Error != nil {
+			e := result.Error.Error()
+			m.Error = &e
+		}
+
+		if category := internal.ErrorCategory(result.Error); category != "" {
+			m.ErrorCategory = &category
+		}
+
+		modules = append(modules, m)
+	}
+
+	if p.Order == nil {
+		sort.Slice(modules, func(i, j int) bool {
+			return modules[i].Path < modules[j].Path
+		})
+	}
+
+	return JSONResult{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		Modules:       modules,
+		Summary:       newSummary(p.Result),
+	}
+}
+
+// names returns module names in Order, the others sorted by path, direct dependencies first.
+func (p *ResultPrinter) names() []string {
+	names := make([]string, 0, len(p.Result))
+	seen := make(map[string]bool)
+
+	for _, name := range p.Order {
+		if _, ok := p.Result[name]; ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	ordered := len(names)
+	for name := range p.Result {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+
+	rest := names[ordered:]
+	sort.Slice(rest, func(i, j int) bool {
+		a, b := p.Result[rest[i]], p.Result[rest[j]]
+		if a.Indirect != b.Indirect {
+			return !a.Indirect
+		}
+
+		return rest[i] < rest[j]
+	})
+
+	return names
+}
+
+// dateString returns the publish date, - if it is unknown.
+func dateString(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Format("2006-01-02")
+}
+
+func timePointer(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+// localVersions returns every local version of the result, there are several if workspace modules require different ones.
+func localVersions(result internal.CheckResult) []string {
+	versions := make([]string, 0, len(result.LocalVersions))
+	for _, v := range result.LocalVersions {
+		versions = append(versions, v.Original())
+	}
+
+	if len(versions) == 0 && result.LocalVersion != nil {
+		versions = append(versions, result.LocalVersion.Original())
+	}
+
+	return versions
+}
+
+func versionString(v *semver.Version) *string {
+	if v == nil {
+		return nil
+	}
+
+	s := v.Original()
+
+	return &s
+}
+
+// UnusedPrinter implements Printer interface for modules reported by --unused.
+type UnusedPrinter struct {
+	Unused []internal.UnusedModule
+}
+
+// NewUnusedPrinter creates a new instance of UnusedPrinter.
+func NewUnusedPrinter(unused []internal.UnusedModule) *UnusedPrinter {
+	return &UnusedPrinter{Unused: unused}
+}
+
+// JSONUnusedModule is a module which go mod tidy would remove.
+type JSONUnusedModule struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Indirect bool   `json:"indirect"`
+}
+
+// TableData returns table friendly result.
+func (p *UnusedPrinter) TableData() *printer.TableData {
+	var data [][]string
+
+	for _, m := range p.Unused {
+		name := m.Path
+		if m.Indirect {
+			name += " (indirect)"
+		}
+
+		data = append(data, []string{name, m.Version})
+	}
+
+	return &printer.TableData{
+		Header:       []string{"Unused module", "Version"},
+		Footer:       []string{"number of modules", strconv.Itoa(len(p.Unused))},
+		RowSeparator: "-",
+		ShowBorder:   false,
+		ShowRowLine:  false,
+		Data:         data,
+	}
+}
+
+// JSONData returns JSON friendly result.
+func (p *UnusedPrinter) JSONData() interface{} {
+	modules := make([]JSONUnusedModule, 0, len(p.Unused))
+
+	for _, m := range p.Unused {
+		modules = append(modules, JSONUnusedModule{Path: m.Path, Version: m.Version, Indirect: m.Indirect})
+	}
+
+	return modules
 }
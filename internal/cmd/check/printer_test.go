@@ -0,0 +1,28 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultPrinterNotes(t *testing.T) {
+	rp := NewResultPrinter(map[string]internal.CheckResult{
+		"github.com/foo/bar": {
+			LocalVersion:  semver.MustParse("v1.0.0"),
+			LatestVersion: semver.MustParse("v1.1.0"),
+			Indirect:      true,
+			Deprecated:    "use github.com/foo/baz",
+		},
+	})
+	rp.Notes = true
+
+	td := rp.TableData()
+	assert.Len(t, td.Data, 1)
+
+	row := td.Data[0]
+	assert.Equal(t, "github.com/foo/bar (indirect) (DEPRECATED: use github.com/foo/baz)", row[0])
+	assert.Equal(t, "https://github.com/foo/bar/compare/v1.0.0...v1.1.0", row[len(row)-1], "the URL is built from the module path")
+}
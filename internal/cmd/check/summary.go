@@ -0,0 +1,97 @@
+package check
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// JSONSummary counts check results by kind of their update, modules which failed or are ignored are counted separately.
+// ErrorCategories counts the errors by category, see internal.ErrorCategory, errors without one aren't counted.
+type JSONSummary struct {
+	UpToDate        int            `json:"upToDate"`
+	Patch           int            `json:"patch"`
+	Minor           int            `json:"minor"`
+	Major           int            `json:"major"`
+	Errors          int            `json:"errors"`
+	ErrorCategories map[string]int `json:"errorCategories,omitempty"`
+	Ignored         int            `json:"ignored"`
+}
+
+// newSummary counts the results, see JSONSummary.
+func newSummary(results map[string]internal.CheckResult) JSONSummary {
+	s := JSONSummary{}
+
+	for _, result := range results {
+		switch {
+		case result.Failed():
+			s.Errors++
+
+			if category := internal.ErrorCategory(result.Error); category != "" {
+				if s.ErrorCategories == nil {
+					s.ErrorCategories = make(map[string]int)
+				}
+
+				s.ErrorCategories[category]++
+			}
+		case result.Error != nil:
+			s.Ignored++
+		case result.UpdateType() == internal.UpdateMajor:
+			s.Major++
+		case result.UpdateType() == internal.UpdateMinor:
+			s.Minor++
+		case result.UpdateType() == internal.UpdatePatch:
+			s.Patch++
+		default:
+			s.UpToDate++
+		}
+	}
+
+	return s
+}
+
+// String returns the summary line printed after the table, e.g. "12 up to date, 5 patch, 3 minor, 2 major, 1 error".
+// Ignored modules and error categories are mentioned only if there are any, e.g. "2 errors (1 network, 1 not-found)".
+func (s JSONSummary) String() string {
+	errors := "errors"
+	if s.Errors == 1 {
+		errors = "error"
+	}
+
+	parts := []string{
+		fmt.Sprintf("%d up to date", s.UpToDate),
+		fmt.Sprintf("%d patch", s.Patch),
+		fmt.Sprintf("%d minor", s.Minor),
+		fmt.Sprintf("%d major", s.Major),
+		fmt.Sprintf("%d %s", s.Errors, errors) + s.categoriesString(),
+	}
+
+	if s.Ignored > 0 {
+		parts = append(parts, fmt.Sprintf("%d ignored", s.Ignored))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// categoriesString returns the counts of error categories in parentheses, sorted by name, empty if there are none.
+func (s JSONSummary) categoriesString() string {
+	if len(s.ErrorCategories) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(s.ErrorCategories))
+	for category := range s.ErrorCategories {
+		names = append(names, category)
+	}
+
+	sort.Strings(names)
+
+	categories := make([]string, 0, len(names))
+	for _, category := range names {
+		categories = append(categories, fmt.Sprintf("%d %s", s.ErrorCategories[category], category))
+	}
+
+	return " (" + strings.Join(categories, ", ") + ")"
+}
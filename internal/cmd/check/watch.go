@@ -0,0 +1,79 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"github.com/beatlabs/gomodctl/internal/printer"
+)
+
+// clearScreen moves the cursor home and clears the terminal, so every poll redraws the table.
+const clearScreen = "\033[H\033[2J"
+
+// ExecuteWatch checks the modules every --watch interval until the context is done, e.g. on SIGINT.
+// The version cache is bypassed, so every poll sees new releases. Modules whose latest version is newer than in the
+// previous poll are marked. A failing first poll fails the command, later ones are logged and retried.
+func (o *Options) ExecuteWatch(ctx context.Context, checker Checker) error {
+	var previous map[string]internal.CheckResult
+
+	for {
+		checkResults, err := o.check(checker)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && previous == nil:
+			return &internal.ExitError{Code: ExitCodeError, Err: err}
+		case err != nil:
+			logger.Warnf("%v, retrying in %s", err, o.watch)
+		default:
+			o.draw(checkResults, newReleases(previous, checkResults))
+			previous = checkResults
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(o.watch):
+		}
+	}
+}
+
+// draw prints the results of a poll, over the previous one in a terminal.
+func (o *Options) draw(checkResults map[string]internal.CheckResult, released map[string]bool) {
+	out := printer.Output()
+
+	if printer.IsTerminal() {
+		fmt.Fprint(out, clearScreen)
+	} else {
+		fmt.Fprintln(out)
+	}
+
+	now := time.Now()
+	fmt.Fprintf(out, "checked at %s, next check at %s\n\n", now.Format("2006-01-02 15:04:05"), now.Add(o.watch).Format("15:04:05"))
+
+	rp := NewResultPrinter(checkResults)
+	rp.StaleAfter = o.staleAfter
+	rp.Notes = o.Notes
+	rp.NewReleases = released
+	printer.Print(rp, o.Format)
+
+	fmt.Fprintf(out, "\n%s\n", newSummary(checkResults))
+}
+
+// newReleases returns modules whose latest version is newer than in the previous results, none in the first poll.
+func newReleases(previous, current map[string]internal.CheckResult) map[string]bool {
+	released := make(map[string]bool)
+
+	for name, result := range current {
+		before, ok := previous[name]
+		if ok && before.LatestVersion != nil && result.LatestVersion != nil && result.LatestVersion.GreaterThan(before.LatestVersion) {
+			released[name] = true
+		}
+	}
+
+	return released
+}
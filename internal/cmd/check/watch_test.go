@@ -0,0 +1,48 @@
+package check
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReleases(t *testing.T) {
+	result := func(latest string) internal.CheckResult {
+		if latest == "" {
+			return internal.CheckResult{Error: internal.ErrNetwork}
+		}
+
+		return internal.CheckResult{LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse(latest)}
+	}
+
+	previous := map[string]internal.CheckResult{
+		"example.com/same":   result("v1.1.0"),
+		"example.com/newer":  result("v1.1.0"),
+		"example.com/failed": result(""),
+	}
+	current := map[string]internal.CheckResult{
+		"example.com/same":   result("v1.1.0"),
+		"example.com/newer":  result("v1.2.0"),
+		"example.com/failed": result("v1.2.0"),
+		"example.com/added":  result("v1.2.0"),
+	}
+
+	assert.Empty(t, newReleases(nil, current), "nothing is new in the first poll")
+	assert.Equal(t, map[string]bool{"example.com/newer": true}, newReleases(previous, current))
+}
+
+func TestWatchOptions(t *testing.T) {
+	o := Options{Format: printer.FormatTable, FailOn: failOnAny, Watch: "1d"}
+	assert.NoError(t, o.validate())
+	assert.Equal(t, 24*time.Hour, o.watch)
+	assert.True(t, o.checkOptions().NoCache, "every poll bypasses the version cache")
+
+	o = Options{Format: printer.FormatTable, FailOn: failOnAny, Watch: "0s"}
+	assert.Error(t, o.validate())
+
+	assert.False(t, (&Options{}).checkOptions().NoCache)
+}
@@ -0,0 +1,88 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ModuleLister is exported.
+type ModuleLister interface {
+	Required(path string) ([]string, error)
+}
+
+// shells are the supported arguments of completion command.
+var shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// NewCmdCompletion returns an instance of Completion command.
+func NewCmdCompletion() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "generate shell completion script",
+		Long: `generate the completion script of gomodctl for the given shell.
+
+To load completions in the current bash session:
+
+  source <(gomodctl completion bash)
+
+For zsh, write the script into a directory of your $fpath, e.g.:
+
+  gomodctl completion zsh > "${fpath[1]}/_gomodctl"
+
+For fish:
+
+  gomodctl completion fish > ~/.config/fish/completions/gomodctl.fish`,
+		ValidArgs:             shells,
+		DisableFlagsInUseLine: true,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("one shell is required: %s", strings.Join(shells, ", "))
+			}
+
+			return cobra.OnlyValidArgs(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletion(out)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			default:
+				return root.GenPowerShellCompletion(out)
+			}
+		},
+	}
+
+	return cmd
+}
+
+// ModulePaths completes the first argument with paths of modules required by go.mod in --path.
+func ModulePaths(lister ModuleLister) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		path, _ := cmd.Flags().GetString("path")
+
+		required, err := lister.Required(path)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var paths []string
+		for _, p := range required {
+			if strings.HasPrefix(p, toComplete) {
+				paths = append(paths, p)
+			}
+		}
+
+		return paths, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,67 @@
+package diff
+
+import (
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+	"github.com/spf13/cobra"
+)
+
+// Differ is exported.
+type Differ interface {
+	Diff(oldPath, newPath string) (map[string]internal.DiffResult, error)
+}
+
+// Options is exported.
+type Options struct {
+	Old    string
+	New    string
+	JSON   bool
+	Format string
+}
+
+// NewCmdDiff returns an instance of Diff command.
+func NewCmdDiff(differ Differ) *cobra.Command {
+	o := Options{}
+
+	cmd := &cobra.Command{
+		Use:   "diff <old go.mod> <new go.mod>",
+		Short: "compare requirements of two go.mod files",
+		Long: `compare requirements of two go.mod files and report added, removed and changed modules,
+version changes are classified as major, minor or patch. Directories containing go.mod are accepted too.`,
+		Example: `  gomodctl diff old/go.mod new/go.mod`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+				return err
+			}
+
+			o.Old, o.New = args[0], args[1]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			o.Fill(cmd)
+			return o.Execute(differ)
+		},
+	}
+
+	return cmd
+}
+
+// Fill fills flags into options.
+func (o *Options) Fill(cmd *cobra.Command) {
+	o.Format = printer.FormatOf(cmd.Flags())
+	o.JSON = o.Format == printer.FormatJSON
+}
+
+// Execute is exported.
+func (o *Options) Execute(differ Differ) error {
+	results, err := differ.Diff(o.Old, o.New)
+	if err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	printer.Print(NewResultPrinter(results), o.Format)
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package diff
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+)
+
+// JSONModule is the JSON representation of a changed module.
+type JSONModule struct {
+	Path       string              `json:"path"`
+	Change     internal.DiffChange `json:"change"`
+	OldVersion *string             `json:"oldVersion"`
+	NewVersion *string             `json:"newVersion"`
+	UpdateType internal.UpdateType `json:"updateType"`
+	Downgrade  bool                `json:"downgrade"`
+	Indirect   bool                `json:"indirect"`
+}
+
+// ResultPrinter implements Printer interface for Diff command.
+type ResultPrinter struct {
+	Result map[string]internal.DiffResult
+}
+
+// NewResultPrinter creates a new instance of ResultPrinter.
+func NewResultPrinter(results map[string]internal.DiffResult) *ResultPrinter {
+	return &ResultPrinter{Result: results}
+}
+
+// TableData returns table friendly result, modules are grouped by change.
+func (p *ResultPrinter) TableData() *printer.TableData {
+	var data [][]string
+
+	for _, name := range p.names() {
+		result := p.Result[name]
+
+		if result.Indirect {
+			name += " (indirect)"
+		}
+
+		updateType := ""
+		if result.Change == internal.DiffChanged {
+			updateType = string(result.UpdateType())
+			if result.Downgrade() {
+				updateType += " (downgrade)"
+			}
+		}
+
+		data = append(data, []string{
+			string(result.Change),
+			name,
+			versionOrDash(result.OldVersion),
+			versionOrDash(result.NewVersion),
+			updateType,
+		})
+	}
+
+	return &printer.TableData{
+		Header:       []string{"Change", "Module", "Old", "New", "Type"},
+		Footer:       []string{"", "", "", "number of modules", strconv.Itoa(len(p.Result))},
+		RowSeparator: "-",
+		ShowBorder:   false,
+		ShowRowLine:  false,
+		Data:         data,
+	}
+}
+
+// JSONData returns JSON friendly result.
+func (p *ResultPrinter) JSONData() interface{} {
+	modules := make([]JSONModule, 0, len(p.Result))
+
+	for _, name := range p.names() {
+		result := p.Result[name]
+
+		modules = append(modules, JSONModule{
+			Path:       name,
+			Change:     result.Change,
+			OldVersion: versionString(result.OldVersion),
+			NewVersion: versionString(result.NewVersion),
+			UpdateType: result.UpdateType(),
+			Downgrade:  result.Downgrade(),
+			Indirect:   result.Indirect,
+		})
+	}
+
+	return modules
+}
+
+var changeOrder = map[internal.DiffChange]int{
+	internal.DiffAdded:   0,
+	internal.DiffRemoved: 1,
+	internal.DiffChanged: 2,
+}
+
+// names returns module names sorted by change and path.
+func (p *ResultPrinter) names() []string {
+	names := make([]string, 0, len(p.Result))
+	for name := range p.Result {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		a, b := changeOrder[p.Result[names[i]].Change], changeOrder[p.Result[names[j]].Change]
+		if a != b {
+			return a < b
+		}
+
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+func versionString(v *semver.Version) *string {
+	if v == nil {
+		return nil
+	}
+
+	s := v.Original()
+
+	return &s
+}
+
+func versionOrDash(v *semver.Version) string {
+	if v == nil {
+		return "-"
+	}
+
+	return v.Original()
+}
@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+func encodeDOT(graph *internal.Graph) []byte {
+	var b strings.Builder
+
+	b.WriteString("digraph modules {\n")
+	b.WriteString("\trankdir=LR;\n")
+	fmt.Fprintf(&b, "\t%s [shape=box];\n", strconv.Quote(graph.Root.String()))
+
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s;\n", strconv.Quote(edge.From.String()), strconv.Quote(edge.To.String()))
+	}
+
+	b.WriteString("}\n")
+
+	return []byte(b.String())
+}
@@ -0,0 +1,131 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// FormatDOT is Graphviz DOT format.
+	FormatDOT = "dot"
+	// FormatMermaid is Mermaid flowchart format.
+	FormatMermaid = "mermaid"
+)
+
+// encoder encodes the module graph into a document of a format.
+type encoder func(graph *internal.Graph) []byte
+
+var encoders = map[string]encoder{
+	FormatDOT:     encodeDOT,
+	FormatMermaid: encodeMermaid,
+}
+
+// Grapher defines interface to collect the module graph.
+type Grapher interface {
+	Graph(path string, options internal.GraphOptions) (*internal.Graph, error)
+}
+
+// Options is exported.
+type Options struct {
+	Root       string
+	Path       string
+	Format     string
+	Depth      int
+	DirectOnly bool
+}
+
+// NewCmdGraph returns an instance of Graph command.
+func NewCmdGraph(grapher Grapher) *cobra.Command {
+	o := Options{}
+
+	cmd := &cobra.Command{
+		Use:   "graph [module[@version]]",
+		Short: "print the module dependency graph",
+		Long: `print the module graph of local module as Graphviz DOT or Mermaid,
+if a module is given only the subgraph of modules it requires is printed.`,
+		Example: `  gomodctl graph | dot -Tsvg > graph.svg
+  gomodctl graph --format mermaid --direct-only
+  gomodctl graph github.com/spf13/cobra --depth 2`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("at most one module can be the root of the graph")
+			}
+
+			o.Fill(cmd)
+
+			if len(args) == 1 {
+				o.Root = args[0]
+			}
+
+			return o.validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return o.Execute(grapher)
+		},
+	}
+
+	cmd.Flags().String("format", FormatDOT, "graph format: "+strings.Join(formats(), ", "))
+	cmd.Flags().Int("depth", 0, "maximum number of requirement levels below the root, 0 means no limit")
+	cmd.Flags().Bool("direct-only", false, "only print direct requirements of the root, same as --depth 1")
+
+	return cmd
+}
+
+// Fill fills flags into options.
+func (o *Options) Fill(cmd *cobra.Command) {
+	o.Path, _ = cmd.Flags().GetString("path")
+	o.Format, _ = cmd.Flags().GetString("format")
+	o.Depth, _ = cmd.Flags().GetInt("depth")
+	o.DirectOnly, _ = cmd.Flags().GetBool("direct-only")
+}
+
+func (o *Options) validate() error {
+	if _, ok := encoders[o.Format]; !ok {
+		return fmt.Errorf("invalid --format value %q, must be one of %s", o.Format, strings.Join(formats(), ", "))
+	}
+
+	if o.Depth < 0 {
+		return errors.New("--depth can't be negative")
+	}
+
+	if o.DirectOnly && o.Depth > 1 {
+		return errors.New("--direct-only can't be used with --depth higher than 1")
+	}
+
+	return nil
+}
+
+// Execute is exported.
+func (o *Options) Execute(grapher Grapher) error {
+	options := internal.GraphOptions{Root: o.Root, Depth: o.Depth}
+	if o.DirectOnly {
+		options.Depth = 1
+	}
+
+	graph, err := grapher.Graph(o.Path, options)
+	if err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	_, err = printer.Output().Write(encoders[o.Format](graph))
+
+	return err
+}
+
+func formats() []string {
+	var names []string
+	for name := range encoders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+func encodeMermaid(graph *internal.Graph) []byte {
+	var b strings.Builder
+
+	// node ids are assigned by first appearance, labels of nodes are given only then.
+	ids := make(map[internal.Requirement]string)
+	node := func(r internal.Requirement) string {
+		if id, ok := ids[r]; ok {
+			return id
+		}
+
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[r] = id
+
+		return fmt.Sprintf("%s[%q]", id, r.String())
+	}
+
+	b.WriteString("graph LR\n")
+
+	if len(graph.Edges) == 0 {
+		fmt.Fprintf(&b, "\t%s\n", node(graph.Root))
+	}
+
+	for _, edge := range graph.Edges {
+		from := node(edge.From)
+		fmt.Fprintf(&b, "\t%s --> %s\n", from, node(edge.To))
+	}
+
+	return []byte(b.String())
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Masterminds/semver"
 	"github.com/beatlabs/gomodctl/internal"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -18,6 +19,14 @@ type Infoer interface {
 	Info(path string) (string, error)
 	Imports(path string) ([]string, error)
 	Importers(path string) ([]string, error)
+	ImporterCount(path string) (int, error)
+	SubPackages(path string) ([]string, error)
+	Readme(path string) (string, error)
+}
+
+// Versioner lists available versions of a module.
+type Versioner interface {
+	Versions(modulePath string) ([]*semver.Version, error)
 }
 
 // Options is exported.
@@ -26,10 +35,13 @@ type Options struct {
 	ShowImports   bool
 	ShowImporters bool
 	WithDoc       bool
+	WithReadme    bool
+	Versions      bool
+	Limit         int
 }
 
 // NewCmdInfo returns an instance of Search command.
-func NewCmdInfo(ig Infoer) *cobra.Command {
+func NewCmdInfo(ig Infoer, versioner Versioner) *cobra.Command {
 	o := Options{}
 
 	cmd := &cobra.Command{
@@ -46,13 +58,22 @@ func NewCmdInfo(ig Infoer) *cobra.Command {
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Fill(cmd)
-			o.Execute(ig)
+
+			if o.Versions {
+				o.ExecuteVersions(versioner)
+				return
+			}
+
+			o.Execute(ig, versioner)
 		},
 	}
 
 	cmd.Flags().BoolP("imports", "i", false, "--imports")
 	cmd.Flags().BoolP("importers", "e", false, "--importers")
 	cmd.Flags().BoolP("with-doc", "d", false, "--with-doc")
+	cmd.Flags().Bool("readme", false, "show the first section of README of the repository")
+	cmd.Flags().Bool("versions", false, "list available versions of the given module path from the newest")
+	cmd.Flags().Int("limit", 0, "maximum number of versions listed by --versions, all by default")
 
 	return cmd
 }
@@ -62,10 +83,13 @@ func (o *Options) Fill(cmd *cobra.Command) {
 	o.ShowImports, _ = cmd.Flags().GetBool("imports")
 	o.ShowImporters, _ = cmd.Flags().GetBool("importers")
 	o.WithDoc, _ = cmd.Flags().GetBool("with-doc")
+	o.WithReadme, _ = cmd.Flags().GetBool("readme")
+	o.Versions, _ = cmd.Flags().GetBool("versions")
+	o.Limit, _ = cmd.Flags().GetInt("limit")
 }
 
 // Execute is exported.
-func (o *Options) Execute(ig Infoer) {
+func (o *Options) Execute(ig Infoer, versioner Versioner) {
 	searchResults, err := ig.Search(o.Term)
 	if err != nil {
 		fmt.Println(err)
@@ -79,8 +103,24 @@ func (o *Options) Execute(ig Infoer) {
 
 	top := searchResults[0]
 
+	// popularity is optional, it is left out if the registry doesn't expose it.
+	importedBy := ""
+	if top.ImportCount > 0 {
+		importedBy = fmt.Sprintf("Imported by: %d packages", top.ImportCount)
+	}
+	if count, err := ig.ImporterCount(top.Path); err == nil && count > 0 {
+		if importedBy == "" {
+			importedBy = fmt.Sprintf("Imported by: %d known importers", count)
+		} else {
+			importedBy += fmt.Sprintf(" (%d known importers)", count)
+		}
+	}
+	if importedBy != "" {
+		fmt.Printf("%s\n\n", importedBy)
+	}
+
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Path", "Stars", "Import count", "Score"})
+	table.SetHeader([]string{"Path", "Stars", "Imported by", "Score"})
 	table.SetBorder(false)
 	table.Append([]string{
 		top.Path,
@@ -90,6 +130,38 @@ func (o *Options) Execute(ig Infoer) {
 	})
 	table.Render()
 
+	width := terminalWidth()
+
+	if top.Synopsis != "" {
+		fmt.Println("\nSynopsis:")
+		fmt.Println(wrap(top.Synopsis, width))
+	}
+
+	if notes := releaseNotes(versioner, top.Path); notes != "" {
+		fmt.Println("\nRelease notes:")
+		fmt.Println(notes)
+	}
+
+	subPackages, err := ig.SubPackages(top.Path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(subPackages) > 0 {
+		fmt.Println("\nSub-packages:")
+		fmt.Println(strings.Join(subPackages, "\n"))
+	}
+
+	if o.WithReadme {
+		readme, err := ig.Readme(top.Path)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("\nReadme:")
+		fmt.Println(wrap(firstSection(readme), width))
+	}
+
 	if o.WithDoc {
 		infoResult, err := ig.Info(top.Path)
 		if err != nil {
@@ -120,3 +192,56 @@ func (o *Options) Execute(ig Infoer) {
 		fmt.Println(strings.Join(importers, "\n"))
 	}
 }
+
+// releaseNotes returns the release notes URL of the latest version of the module providing the package, see internal.ReleaseNotesURL.
+// The module is the longest prefix of the package path with tagged versions, only GitHub repositories are looked up.
+func releaseNotes(versioner Versioner, path string) string {
+	if !strings.HasPrefix(path, "github.com/") {
+		return ""
+	}
+
+	parts := strings.Split(path, "/")
+
+	for n := len(parts); n >= 3; n-- {
+		modulePath := strings.Join(parts[:n], "/")
+
+		versions, err := versioner.Versions(modulePath)
+		if err != nil || len(versions) == 0 {
+			continue
+		}
+
+		latest := versions[0]
+		for _, v := range versions {
+			if v.Prerelease() == "" {
+				latest = v
+				break
+			}
+		}
+
+		return internal.ReleaseNotesURL(modulePath, "", latest.Original())
+	}
+
+	return ""
+}
+
+// ExecuteVersions prints available versions of the module, prereleases are marked.
+func (o *Options) ExecuteVersions(versioner Versioner) {
+	versions, err := versioner.Versions(o.Term)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if o.Limit > 0 && len(versions) > o.Limit {
+		versions = versions[:o.Limit]
+	}
+
+	for _, v := range versions {
+		if v.Prerelease() != "" {
+			fmt.Printf("%s (prerelease)\n", v.Original())
+			continue
+		}
+
+		fmt.Println(v.Original())
+	}
+}
@@ -0,0 +1,92 @@
+package info
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// defaultWidth is used when the output isn't a terminal.
+const defaultWidth = 80
+
+// terminalWidth returns width of the terminal of stdout.
+func terminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 {
+		return defaultWidth
+	}
+
+	return width
+}
+
+// wrap breaks lines of text longer than width at spaces, indentation of the lines is kept.
+// Lines of markdown code blocks are left as they are.
+func wrap(text string, width int) string {
+	var b strings.Builder
+
+	inCode := false
+
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		}
+
+		if inCode || strings.HasPrefix(line, "```") || len(line) <= width {
+			b.WriteString(line)
+			continue
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		column := 0
+
+		for j, word := range strings.Fields(line) {
+			switch {
+			case j == 0:
+				b.WriteString(indent)
+				column = len(indent)
+			case column+1+len(word) > width:
+				b.WriteString("\n")
+				b.WriteString(indent)
+				column = len(indent)
+			default:
+				b.WriteString(" ")
+				column++
+			}
+
+			b.WriteString(word)
+			column += len(word)
+		}
+	}
+
+	return b.String()
+}
+
+// firstSection returns a markdown document up to its second heading, lines starting with # in code blocks aren't headings.
+func firstSection(markdown string) string {
+	var lines []string
+
+	headings := 0
+	inCode := false
+
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		}
+
+		if !inCode && strings.HasPrefix(line, "#") {
+			headings++
+			if headings > 1 {
+				break
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -1,11 +1,20 @@
 package license
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/beatlabs/gomodctl/internal"
 	"github.com/beatlabs/gomodctl/internal/printer"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// ExitCodeViolation is the exit status when any license violates the policy.
+	ExitCodeViolation = 1
+	// ExitCodeError is the exit status when licenses can't be checked.
+	ExitCodeError = 2
 )
 
 // Typer defines interface to check for license types.
@@ -20,7 +29,14 @@ type Options struct {
 	Module  string
 	Version string
 	JSON    bool
+	Format  string
 	Path    string
+	Allow   []string
+	Deny    []string
+	// MinConfidence reports licenses matched with a lower confidence as unknown.
+	MinConfidence float64
+	// Summary prints the number of modules per license instead of every module.
+	Summary bool
 }
 
 // NewCmdLicense returns an instance of License command.
@@ -28,9 +44,10 @@ func NewCmdLicense(typer Typer) *cobra.Command {
 	o := Options{}
 
 	cmd := &cobra.Command{
-		Use:   "license [module name] [version]",
-		Short: "fetch license of module, version is optional",
-		Long:  `fetch license of module, if version is empty it will use latest version`,
+		Use:         "license [module name] [version]",
+		Short:       "fetch license of module, version is optional",
+		Long:        `fetch license of module, if version is empty it will use latest version`,
+		Annotations: map[string]string{printer.FormatsAnnotation: printer.FormatYAML},
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
 				o.Module = args[0]
@@ -40,44 +57,99 @@ func NewCmdLicense(typer Typer) *cobra.Command {
 				o.Version = args[1]
 			}
 
-			return nil
+			o.Fill(cmd)
+			return o.validate()
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			o.Execute(typer)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return o.Execute(typer)
 		},
 	}
 
+	cmd.Flags().StringArray("allow", nil, "allowed license, any other license fails the command, can be repeated")
+	cmd.Flags().StringArray("deny", nil, "denied license, can be repeated")
+	cmd.Flags().Bool("offline", false, "read module sources only from the local module cache, never from the proxy")
+	cmd.Flags().Bool("summary", false, "print the number of modules per license instead of every module")
+	cmd.Flags().Float64("min-confidence", 0, "report licenses matched with a lower confidence, from 0 to 1, as unknown")
+	viper.BindPFlag("offline", cmd.Flags().Lookup("offline"))
+	viper.BindPFlag("min_confidence", cmd.Flags().Lookup("min-confidence"))
+
 	return cmd
 }
 
 // Fill fills flags into options.
 func (o *Options) Fill(cmd *cobra.Command) {
-	o.JSON, _ = cmd.Flags().GetBool("json")
+	o.Format = printer.FormatOf(cmd.Flags())
+	o.JSON = o.Format == printer.FormatJSON
 	o.Path, _ = cmd.Flags().GetString("path")
+	o.Allow, _ = cmd.Flags().GetStringArray("allow")
+	o.Deny, _ = cmd.Flags().GetStringArray("deny")
+	o.MinConfidence = viper.GetFloat64("min_confidence")
+	o.Summary, _ = cmd.Flags().GetBool("summary")
+}
+
+func (o *Options) validate() error {
+	if o.MinConfidence < 0 || o.MinConfidence > 1 {
+		return errors.New("--min-confidence must be between 0 and 1")
+	}
+
+	if o.Summary && o.Module != "" {
+		return errors.New("--summary can't be used with a module, it summarizes all dependencies")
+	}
+
+	return nil
 }
 
 // Execute executes command on given Typer and prints output.
-func (o *Options) Execute(op Typer) {
+// Modules violating the policy are listed after the table, JSON and YAML output has them in Violation of every module.
+func (o *Options) Execute(op Typer) error {
+	policy := NewPolicy(o.Allow, o.Deny)
+	structured := o.JSON || o.Format == printer.FormatYAML
+
+	var types map[string]internal.LicenseResult
+
 	if o.Version == "" && o.Module == "" {
-		types, err := op.Types(o.Path)
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
+		var err error
 
-		rp := NewResultPrinter(types)
-		if o.JSON {
-			printer.PrintJSON(rp)
-		} else {
-			printer.PrintTable(rp)
+		types, err = op.Types(o.Path)
+		if err != nil {
+			return &internal.ExitError{Code: ExitCodeError, Err: err}
 		}
 	} else {
 		licenseType, err := op.Type(o.Module, o.Version)
 		if err != nil {
-			fmt.Println(err)
-			return
+			return &internal.ExitError{Code: ExitCodeError, Err: err}
+		}
+
+		types = map[string]internal.LicenseResult{o.Module: internal.NewLicenseResult(nil, licenseType)}
+
+		if !structured {
+			fmt.Fprintln(printer.Output(), licenseType)
 		}
+	}
+
+	var violations []Violation
+	if !policy.IsEmpty() {
+		violations = policy.Violations(types)
+	}
 
-		fmt.Println(licenseType)
+	switch {
+	case o.Summary:
+		printer.Print(NewSummaryPrinter(types, violations), o.Format)
+	case o.Module == "" || structured:
+		printer.Print(NewResultPrinter(types, violations), o.Format)
 	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if !structured {
+		fmt.Fprintln(printer.Output(), "\nLicense policy violations:")
+		for _, v := range violations {
+			fmt.Fprintln(printer.Output(), v)
+		}
+	}
+
+	return &internal.ExitError{Code: ExitCodeViolation}
 }
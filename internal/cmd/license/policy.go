@@ -0,0 +1,126 @@
+package license
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
+)
+
+// Policy contains allowed and denied licenses.
+// If allowed licenses are set, any other license including unknown ones is a violation.
+type Policy struct {
+	Allowed map[string]bool
+	Denied  map[string]bool
+}
+
+// Violation is a module which doesn't comply with the policy.
+type Violation struct {
+	Module  string `json:"module"`
+	License string `json:"license"`
+	Reason  string `json:"reason"`
+}
+
+// NewPolicy merges allowed_licenses and denied_licenses config with the given licenses.
+func NewPolicy(allowed, denied []string) Policy {
+	p := Policy{
+		Allowed: make(map[string]bool),
+		Denied:  make(map[string]bool),
+	}
+
+	for _, l := range append(viper.GetStringSlice("allowed_licenses"), allowed...) {
+		p.Allowed[normalize(l)] = true
+	}
+
+	for _, l := range append(viper.GetStringSlice("denied_licenses"), denied...) {
+		p.Denied[normalize(l)] = true
+	}
+
+	return p
+}
+
+// IsEmpty reports whether there is nothing to enforce.
+func (p Policy) IsEmpty() bool {
+	return len(p.Allowed) == 0 && len(p.Denied) == 0
+}
+
+// Violations returns modules violating the policy sorted by module.
+func (p Policy) Violations(results map[string]internal.LicenseResult) []Violation {
+	var violations []Violation
+
+	for name, result := range results {
+		if reason := p.check(result); reason != "" {
+			violations = append(violations, Violation{
+				Module:  name,
+				License: licenseOf(result),
+				Reason:  reason,
+			})
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Module < violations[j].Module
+	})
+
+	return violations
+}
+
+// check returns the reason of violation, empty if result complies with the policy.
+// For compound expressions any operand of OR and every operand of AND must comply.
+func (p Policy) check(result internal.LicenseResult) string {
+	expression := &internal.LicenseExpression{License: licenseOf(result)}
+	if result.Expression != "" && result.Error == nil {
+		if e, err := internal.ParseLicenseExpression(result.Expression); err == nil {
+			expression = e
+		}
+	}
+
+	notDenied := func(license string) bool {
+		return !p.Denied[license]
+	}
+
+	if !expression.Satisfies(notDenied) {
+		return "denied"
+	}
+
+	allowed := func(license string) bool {
+		return len(p.Allowed) == 0 || p.Allowed[license]
+	}
+
+	if !expression.Satisfies(allowed) {
+		if isUnknown(result) {
+			return "unknown license"
+		}
+
+		return "not allowed"
+	}
+
+	return ""
+}
+
+// normalize maps policy licenses to SPDX identifiers, names which can't be mapped are kept as they are.
+func normalize(license string) string {
+	if id := internal.NormalizeLicense(license); id != internal.NoAssertion {
+		return id
+	}
+
+	return license
+}
+
+func isUnknown(result internal.LicenseResult) bool {
+	return result.Error != nil || result.SPDXID == "" || result.SPDXID == internal.NoAssertion
+}
+
+func licenseOf(result internal.LicenseResult) string {
+	if isUnknown(result) {
+		return internal.NoAssertion
+	}
+
+	return result.SPDXID
+}
+
+// String is exported.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s (%s)", v.Module, v.License, v.Reason)
+}
@@ -0,0 +1,64 @@
+package license
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyViolations(t *testing.T) {
+	results := map[string]internal.LicenseResult{
+		"example.com/mit":     internal.NewLicenseResult(nil, "MIT License"),
+		"example.com/agpl":    internal.NewLicenseResult(nil, "AGPL-3.0"),
+		"example.com/unknown": internal.NewLicenseResult(nil, internal.UnknownLicense),
+		"example.com/or":      internal.NewLicenseResult(nil, "MIT OR GPL-3.0-only"),
+		"example.com/and":     internal.NewLicenseResult(nil, "MIT AND GPL-3.0-only"),
+	}
+
+	failed := internal.NewLicenseResult(nil, "MIT")
+	failed.Error = errors.New("not in the module cache")
+	results["example.com/failed"] = failed
+
+	assert.True(t, NewPolicy(nil, nil).IsEmpty())
+	assert.Empty(t, NewPolicy(nil, nil).Violations(results))
+
+	assert.Equal(t, []Violation{
+		{Module: "example.com/agpl", License: "AGPL-3.0-only", Reason: "denied"},
+	}, NewPolicy(nil, []string{"AGPL-3.0"}).Violations(results), "denied licenses are normalized")
+
+	assert.Equal(t, []Violation{
+		{Module: "example.com/and", License: "MIT AND GPL-3.0-only", Reason: "denied"},
+	}, NewPolicy(nil, []string{"GPL-3.0-only"}).Violations(results), "an OR complies if any side isn't denied")
+
+	assert.Equal(t, []Violation{
+		{Module: "example.com/agpl", License: "AGPL-3.0-only", Reason: "not allowed"},
+		{Module: "example.com/and", License: "MIT AND GPL-3.0-only", Reason: "not allowed"},
+		{Module: "example.com/failed", License: internal.NoAssertion, Reason: "unknown license"},
+		{Module: "example.com/unknown", License: internal.NoAssertion, Reason: "unknown license"},
+	}, NewPolicy([]string{"MIT"}, nil).Violations(results), "every side of AND must be allowed")
+}
+
+func TestResultPrinterViolations(t *testing.T) {
+	results := map[string]internal.LicenseResult{
+		"example.com/mit": internal.NewLicenseResult(nil, "MIT"),
+		"example.com/gpl": internal.NewLicenseResult(nil, "GPL-3.0-only"),
+	}
+	violations := NewPolicy([]string{"MIT"}, nil).Violations(results)
+
+	b, err := json.Marshal(NewResultPrinter(results, violations).JSONData())
+	assert.NoError(t, err)
+
+	var got map[string]map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, "GPL-3.0-only", got["example.com/gpl"]["SPDXID"])
+	assert.Equal(t, map[string]interface{}{"module": "example.com/gpl", "license": "GPL-3.0-only", "reason": "not allowed"}, got["example.com/gpl"]["Violation"])
+	assert.NotContains(t, got["example.com/mit"], "Violation")
+
+	assert.Equal(t, []LicenseCount{
+		{License: "GPL-3.0-only", Count: 1, Violations: []string{"example.com/gpl"}},
+		{License: "MIT", Count: 1},
+	}, NewSummaryPrinter(results, violations).JSONData())
+}
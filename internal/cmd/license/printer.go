@@ -2,7 +2,9 @@ package license
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/beatlabs/gomodctl/internal"
 	"github.com/beatlabs/gomodctl/internal/printer"
@@ -11,11 +13,27 @@ import (
 // ResultPrinter implements Printer interface for License command.
 type ResultPrinter struct {
 	licenseResults map[string]internal.LicenseResult
+	violations     map[string]Violation
 }
 
-// NewResultPrinter creates a new instance of ResultPrinter.
-func NewResultPrinter(m map[string]internal.LicenseResult) *ResultPrinter {
-	return &ResultPrinter{licenseResults: m}
+// NewResultPrinter creates a new instance of ResultPrinter, violations of the license policy are added to JSON output.
+func NewResultPrinter(m map[string]internal.LicenseResult, violations []Violation) *ResultPrinter {
+	return &ResultPrinter{licenseResults: m, violations: violationsByModule(violations)}
+}
+
+func violationsByModule(violations []Violation) map[string]Violation {
+	m := make(map[string]Violation, len(violations))
+	for _, v := range violations {
+		m[v.Module] = v
+	}
+
+	return m
+}
+
+// jsonResult is the JSON output of a module, Violation is set if the module violates the license policy.
+type jsonResult struct {
+	internal.LicenseResult
+	Violation *Violation `json:",omitempty"`
 }
 
 // TableData returns table friendly result.
@@ -28,18 +46,23 @@ func (r *ResultPrinter) TableData() *printer.TableData {
 			result.LocalVersion.Original(),
 		}
 
-		if result.Error != nil {
-			r = append(r, fmt.Sprintf("failed because of: %s", result.Error.Error()))
-		} else {
-			r = append(r, result.Type)
+		switch {
+		case result.Error != nil:
+			r = append(r, fmt.Sprintf("failed because of: %s", result.Error.Error()), "-", "-")
+		case result.Overridden:
+			r = append(r, result.SPDXID+" (overridden)", "-", "-")
+		case result.NoLicenseFile():
+			r = append(r, result.SPDXID+" (no license file)", "-", "-")
+		default:
+			r = append(r, result.SPDXID, strconv.FormatFloat(result.Confidence, 'f', 2, 64), licenseFiles(result))
 		}
 
 		data = append(data, r)
 	}
 
 	td := &printer.TableData{
-		Header:       []string{"Module", "Version", "License"},
-		Footer:       []string{"", "number of modules", strconv.Itoa(len(r.licenseResults))},
+		Header:       []string{"Module", "Version", "License", "Confidence", "File"},
+		Footer:       []string{"", "", "", "number of modules", strconv.Itoa(len(r.licenseResults))},
 		RowSeparator: "-",
 		ShowBorder:   false,
 		ShowRowLine:  false,
@@ -49,7 +72,112 @@ func (r *ResultPrinter) TableData() *printer.TableData {
 	return td
 }
 
+// licenseFiles returns paths of the license files of the result, JSON output has offsets and snippets of the matches too.
+func licenseFiles(result internal.LicenseResult) string {
+	files := make([]string, 0, len(result.Files))
+
+	for _, f := range result.Files {
+		files = append(files, f.Path)
+	}
+
+	return strings.Join(files, ", ")
+}
+
 // JSONData returns JSON friendly result.
 func (r *ResultPrinter) JSONData() interface{} {
-	return r.licenseResults
+	results := make(map[string]jsonResult, len(r.licenseResults))
+
+	for name, result := range r.licenseResults {
+		jr := jsonResult{LicenseResult: result}
+		if v, ok := r.violations[name]; ok {
+			jr.Violation = &v
+		}
+
+		results[name] = jr
+	}
+
+	return results
+}
+
+// SummaryPrinter implements Printer interface for --summary, it counts modules per license.
+type SummaryPrinter struct {
+	licenseResults map[string]internal.LicenseResult
+	violations     map[string]Violation
+}
+
+// NewSummaryPrinter creates a new instance of SummaryPrinter, modules violating the license policy are added to JSON output.
+func NewSummaryPrinter(m map[string]internal.LicenseResult, violations []Violation) *SummaryPrinter {
+	return &SummaryPrinter{licenseResults: m, violations: violationsByModule(violations)}
+}
+
+// LicenseCount is the number of modules with a license, Violations are the modules of them violating the license policy.
+type LicenseCount struct {
+	License    string   `json:"license"`
+	Count      int      `json:"count"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// unknownLicense is the summary label of modules whose license isn't known, see isUnknown.
+const unknownLicense = "unknown"
+
+// counts returns modules per normalized SPDX identifier, the most common license first and unknown licenses last.
+func (r *SummaryPrinter) counts() []LicenseCount {
+	m := make(map[string]int)
+	violations := make(map[string][]string)
+
+	for name, result := range r.licenseResults {
+		license := unknownLicense
+		if !isUnknown(result) {
+			license = result.SPDXID
+		}
+
+		m[license]++
+
+		if _, ok := r.violations[name]; ok {
+			violations[license] = append(violations[license], name)
+		}
+	}
+
+	counts := make([]LicenseCount, 0, len(m))
+	for license, count := range m {
+		sort.Strings(violations[license])
+		counts = append(counts, LicenseCount{License: license, Count: count, Violations: violations[license]})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if (counts[i].License == unknownLicense) != (counts[j].License == unknownLicense) {
+			return counts[j].License == unknownLicense
+		}
+
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+
+		return counts[i].License < counts[j].License
+	})
+
+	return counts
+}
+
+// TableData returns table friendly result.
+func (r *SummaryPrinter) TableData() *printer.TableData {
+	var data [][]string
+
+	for _, c := range r.counts() {
+		data = append(data, []string{c.License, strconv.Itoa(c.Count)})
+	}
+
+	return &printer.TableData{
+		Header:       []string{"License", "Modules"},
+		Footer:       []string{"number of modules", strconv.Itoa(len(r.licenseResults))},
+		RowSeparator: "-",
+		ShowBorder:   false,
+		ShowRowLine:  false,
+		Data:         data,
+	}
+}
+
+// JSONData returns JSON friendly result.
+func (r *SummaryPrinter) JSONData() interface{} {
+	return r.counts()
 }
@@ -0,0 +1,102 @@
+package outdated
+
+import (
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+	"github.com/beatlabs/gomodctl/internal/progress"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ExitCodeOutdated is the exit status when any module is outdated.
+	ExitCodeOutdated = 1
+	// ExitCodeError is the exit status when outdated fails.
+	ExitCodeError = 2
+)
+
+// Checker is exported.
+type Checker interface {
+	Check(path string, options internal.CheckOptions) (map[string]internal.CheckResult, error)
+}
+
+// Options is exported.
+type Options struct {
+	Path       string
+	JSON       bool
+	Format     string
+	All        bool
+	DirectOnly bool
+	Exclude    []string
+}
+
+// NewCmdOutdated returns an instance of Outdated command.
+func NewCmdOutdated(checker Checker) *cobra.Command {
+	o := Options{}
+
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "list outdated modules, one line per module",
+		Long: `list modules required by go.mod with a newer version, one aligned line per module like npm outdated.
+Wanted is the newest version within the current major version, or within the current minor version for v0 versions,
+latest the newest version of all.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			o.Fill(cmd)
+			return o.Execute(checker)
+		},
+		Annotations: map[string]string{printer.FormatsAnnotation: printer.FormatYAML},
+	}
+
+	cmd.Flags().Bool("all", false, "list up-to-date modules too")
+	cmd.Flags().Bool("direct-only", false, "leave out modules required with an // indirect comment")
+	cmd.Flags().StringArray("exclude", nil, "glob pattern of module paths to ignore, can be repeated")
+
+	return cmd
+}
+
+// Fill fills flags into options.
+func (o *Options) Fill(cmd *cobra.Command) {
+	o.Format = printer.FormatOf(cmd.Flags())
+	o.JSON = o.Format == printer.FormatJSON
+	o.Path, _ = cmd.Flags().GetString("path")
+	o.All, _ = cmd.Flags().GetBool("all")
+	o.DirectOnly, _ = cmd.Flags().GetBool("direct-only")
+	o.Exclude, _ = cmd.Flags().GetStringArray("exclude")
+}
+
+// Execute checks modules for the wanted and the latest versions and prints the outdated ones.
+// It exits with ExitCodeOutdated if any module is outdated.
+func (o *Options) Execute(checker Checker) error {
+	spinner := progress.StartIfEnabled(o.Format, "checked %d/%d modules")
+
+	results, err := checker.Check(o.Path, internal.CheckOptions{
+		Scope:      internal.ScopeLatest,
+		Exclude:    o.Exclude,
+		DirectOnly: o.DirectOnly,
+		Progress:   spinner.Update,
+		Wanted:     true,
+	})
+	spinner.Stop()
+
+	if err != nil {
+		return &internal.ExitError{Code: ExitCodeError, Err: err}
+	}
+
+	modules := newModules(results)
+
+	rp := NewResultPrinter(modules, o.All)
+	if o.Format == printer.FormatTable {
+		rp.PrintCompact(printer.Output())
+	} else {
+		printer.Print(rp, o.Format)
+	}
+
+	for _, m := range modules {
+		if m.Outdated() {
+			return &internal.ExitError{Code: ExitCodeOutdated}
+		}
+	}
+
+	return nil
+}
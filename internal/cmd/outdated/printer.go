@@ -0,0 +1,189 @@
+package outdated
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+)
+
+// Module is the wanted and the latest version of a module.
+type Module struct {
+	Path    string
+	Current *semver.Version
+	// Wanted is the newest version within the current major version, or within the current minor version for v0
+	// versions, Latest the newest version of all.
+	Wanted *semver.Version
+	Latest *semver.Version
+	// LatestPath is set if Latest belongs to a module with a higher major version suffix.
+	LatestPath string
+	Replaced   bool
+	Indirect   bool
+	Error      error
+}
+
+// Outdated reports whether a newer version of the module is available.
+func (m Module) Outdated() bool {
+	return m.Error == nil && !m.Replaced && m.Current != nil && m.Latest != nil && m.Latest.GreaterThan(m.Current)
+}
+
+// newModules returns modules of check results with wanted versions, see CheckOptions.Wanted.
+func newModules(results map[string]internal.CheckResult) []Module {
+	modules := make([]Module, 0, len(results))
+
+	for name, r := range results {
+		modules = append(modules, Module{
+			Path:       name,
+			Current:    r.LocalVersion,
+			Wanted:     r.WantedVersion,
+			Latest:     r.LatestVersion,
+			LatestPath: r.LatestPath,
+			Replaced:   r.Replaced,
+			Indirect:   r.Indirect,
+			Error:      r.Error,
+		})
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Path < modules[j].Path
+	})
+
+	return modules
+}
+
+// JSONModule is the JSON representation of an outdated module.
+type JSONModule struct {
+	Path       string  `json:"path"`
+	Current    *string `json:"current"`
+	Wanted     *string `json:"wanted"`
+	Latest     *string `json:"latest"`
+	LatestPath *string `json:"latestPath"`
+	Indirect   bool    `json:"indirect"`
+	Error      *string `json:"error"`
+}
+
+// ResultPrinter implements Printer interface for Outdated command.
+type ResultPrinter struct {
+	modules []Module
+	// all lists up-to-date modules too.
+	all bool
+}
+
+// NewResultPrinter creates a new instance of ResultPrinter, up-to-date modules are left out unless all is set.
+func NewResultPrinter(modules []Module, all bool) *ResultPrinter {
+	return &ResultPrinter{modules: modules, all: all}
+}
+
+// listed returns modules to print, modules which failed are always listed.
+func (p *ResultPrinter) listed() []Module {
+	var listed []Module
+
+	for _, m := range p.modules {
+		if p.all || m.Error != nil || m.Outdated() {
+			listed = append(listed, m)
+		}
+	}
+
+	return listed
+}
+
+// TableData returns table friendly result.
+func (p *ResultPrinter) TableData() *printer.TableData {
+	var data [][]string
+
+	for _, m := range p.listed() {
+		name := m.Path
+		if m.Indirect {
+			name += " (indirect)"
+		}
+
+		if m.Error != nil {
+			data = append(data, []string{name, versionString(m.Current), "-", m.Error.Error()})
+			continue
+		}
+
+		latest := versionString(m.Latest)
+		if m.LatestPath != "" {
+			latest += " (" + m.LatestPath + ")"
+		}
+
+		data = append(data, []string{name, versionString(m.Current), versionString(m.Wanted), latest})
+	}
+
+	return &printer.TableData{
+		Header:       []string{"Module", "Current", "Wanted", "Latest"},
+		RowSeparator: "-",
+		ShowBorder:   false,
+		ShowRowLine:  false,
+		Data:         data,
+	}
+}
+
+// PrintCompact prints the table data as aligned lines without borders.
+func (p *ResultPrinter) PrintCompact(w io.Writer) {
+	td := p.TableData()
+	if len(td.Data) == 0 {
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, strings.Join(td.Header, "\t"))
+	for _, row := range td.Data {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	tw.Flush()
+}
+
+// JSONData returns JSON friendly result.
+func (p *ResultPrinter) JSONData() interface{} {
+	modules := make([]JSONModule, 0, len(p.modules))
+
+	for _, m := range p.listed() {
+		jm := JSONModule{
+			Path:     m.Path,
+			Current:  versionPointer(m.Current),
+			Wanted:   versionPointer(m.Wanted),
+			Latest:   versionPointer(m.Latest),
+			Indirect: m.Indirect,
+		}
+
+		if m.LatestPath != "" {
+			latestPath := m.LatestPath
+			jm.LatestPath = &latestPath
+		}
+
+		if m.Error != nil {
+			err := m.Error.Error()
+			jm.Error = &err
+		}
+
+		modules = append(modules, jm)
+	}
+
+	return modules
+}
+
+func versionString(v *semver.Version) string {
+	if v == nil {
+		return "-"
+	}
+
+	return v.Original()
+}
+
+func versionPointer(v *semver.Version) *string {
+	if v == nil {
+		return nil
+	}
+
+	s := v.Original()
+
+	return &s
+}
@@ -0,0 +1,154 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// cycloneDXSpecVersion is the CycloneDX specification the document conforms to.
+const cycloneDXSpecVersion = "1.5"
+
+type cycloneDXDocument struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type     string             `json:"type"`
+	BOMRef   string             `json:"bom-ref,omitempty"`
+	Name     string             `json:"name"`
+	Version  string             `json:"version,omitempty"`
+	PURL     string             `json:"purl,omitempty"`
+	Licenses []cycloneDXLicense `json:"licenses,omitempty"`
+}
+
+// cycloneDXLicense is either a license or an SPDX expression.
+type cycloneDXLicense struct {
+	License    *cycloneDXLicenseID `json:"license,omitempty"`
+	Expression string              `json:"expression,omitempty"`
+}
+
+type cycloneDXLicenseID struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func encodeCycloneDX(bom *internal.BOM, meta metadata) ([]byte, error) {
+	serialNumber, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	main := newCycloneDXComponent(bom.Main, "application")
+
+	document := cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + serialNumber,
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: meta.Timestamp.Format(time.RFC3339),
+			Tools: cycloneDXTools{Components: []cycloneDXComponent{{
+				Type:    "application",
+				Name:    toolName,
+				Version: meta.ToolVersion,
+			}}},
+			Component: main,
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	refs := packageURLs(bom)
+
+	document.Dependencies = append(document.Dependencies, newCycloneDXDependency(bom.Main, refs))
+	for _, component := range bom.Components {
+		document.Components = append(document.Components, newCycloneDXComponent(component, "library"))
+		document.Dependencies = append(document.Dependencies, newCycloneDXDependency(component, refs))
+	}
+
+	b, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+func newCycloneDXComponent(component internal.Component, componentType string) cycloneDXComponent {
+	purl := component.PackageURL()
+
+	return cycloneDXComponent{
+		Type:     componentType,
+		BOMRef:   purl,
+		Name:     component.Path,
+		Version:  component.Version,
+		PURL:     purl,
+		Licenses: newCycloneDXLicenses(component.License),
+	}
+}
+
+func newCycloneDXDependency(component internal.Component, refs map[string]string) cycloneDXDependency {
+	dependency := cycloneDXDependency{
+		Ref:       component.PackageURL(),
+		DependsOn: []string{},
+	}
+
+	for _, path := range component.Dependencies {
+		dependency.DependsOn = append(dependency.DependsOn, refs[path])
+	}
+
+	return dependency
+}
+
+// newCycloneDXLicenses returns licenses of the result, unknown licenses are left out.
+func newCycloneDXLicenses(result internal.LicenseResult) []cycloneDXLicense {
+	switch {
+	case result.Error != nil || result.SPDXID == "":
+		return nil
+	case result.Expression != "":
+		return []cycloneDXLicense{{Expression: result.Expression}}
+	case result.SPDXID != internal.NoAssertion:
+		return []cycloneDXLicense{{License: &cycloneDXLicenseID{ID: result.SPDXID}}}
+	case result.Type != internal.UnknownLicense:
+		return []cycloneDXLicense{{License: &cycloneDXLicenseID{Name: result.Type}}}
+	}
+
+	return nil
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,132 @@
+package sbom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// FormatCycloneDX is CycloneDX 1.5 JSON format.
+	FormatCycloneDX = "cyclonedx"
+	// FormatSPDX is SPDX 2.3 tag-value format.
+	FormatSPDX = "spdx"
+
+	toolName = "gomodctl"
+)
+
+// encoder encodes BOM into a document of a format.
+type encoder func(bom *internal.BOM, meta metadata) ([]byte, error)
+
+// metadata describes generation of the document.
+type metadata struct {
+	ToolVersion string
+	Timestamp   time.Time
+}
+
+var encoders = map[string]encoder{
+	FormatCycloneDX: encodeCycloneDX,
+	FormatSPDX:      encodeSPDX,
+}
+
+// Generator defines interface to collect BOM of a module.
+type Generator interface {
+	Generate(path string) (*internal.BOM, error)
+}
+
+// Options is exported.
+type Options struct {
+	Path        string
+	Format      string
+	Output      string
+	ToolVersion string
+}
+
+// NewCmdSBOM returns an instance of SBOM command.
+func NewCmdSBOM(generator Generator) *cobra.Command {
+	o := Options{}
+
+	cmd := &cobra.Command{
+		Use:   "sbom",
+		Short: "generate software bill of materials of local module",
+		Long:  `generate software bill of materials listing every module with its version and license`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			o.Fill(cmd)
+			return o.validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return o.Execute(generator)
+		},
+	}
+
+	cmd.Flags().String("format", FormatCycloneDX, "document format: "+strings.Join(formats(), ", "))
+	cmd.Flags().StringP("output", "o", "", "write document to the file instead of stdout")
+
+	return cmd
+}
+
+// Fill fills flags into options.
+func (o *Options) Fill(cmd *cobra.Command) {
+	o.Path, _ = cmd.Flags().GetString("path")
+	o.Format, _ = cmd.Flags().GetString("format")
+	o.Output, _ = cmd.Flags().GetString("output")
+	o.ToolVersion = cmd.Root().Version
+}
+
+func (o *Options) validate() error {
+	if _, ok := encoders[o.Format]; !ok {
+		return fmt.Errorf("invalid --format value %q, must be one of %s", o.Format, strings.Join(formats(), ", "))
+	}
+
+	return nil
+}
+
+// Execute is exported.
+func (o *Options) Execute(generator Generator) error {
+	bom, err := generator.Generate(o.Path)
+	if err != nil {
+		return err
+	}
+
+	document, err := encoders[o.Format](bom, metadata{
+		ToolVersion: o.ToolVersion,
+		Timestamp:   time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if o.Output == "" {
+		_, err = printer.Output().Write(document)
+		return err
+	}
+
+	return printer.WriteFile(o.Output, document)
+}
+
+// packageURLs maps paths of the BOM components to their package URLs.
+func packageURLs(bom *internal.BOM) map[string]string {
+	purls := map[string]string{bom.Main.Path: bom.Main.PackageURL()}
+	for _, component := range bom.Components {
+		purls[component.Path] = component.PackageURL()
+	}
+
+	return purls
+}
+
+func formats() []string {
+	var names []string
+	for name := range encoders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
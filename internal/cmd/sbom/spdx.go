@@ -0,0 +1,86 @@
+package sbom
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// spdxVersion is the SPDX specification the document conforms to.
+const spdxVersion = "SPDX-2.3"
+
+func encodeSPDX(bom *internal.BOM, meta metadata) ([]byte, error) {
+	uuid, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	creator := toolName
+	if meta.ToolVersion != "" {
+		creator += "-" + meta.ToolVersion
+	}
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "SPDXVersion: %s\n", spdxVersion)
+	fmt.Fprintf(&b, "DataLicense: CC0-1.0\n")
+	fmt.Fprintf(&b, "SPDXID: SPDXRef-DOCUMENT\n")
+	fmt.Fprintf(&b, "DocumentName: %s\n", bom.Main.Path)
+	fmt.Fprintf(&b, "DocumentNamespace: https://spdx.org/spdxdocs/%s-%s\n", bom.Main.Path, uuid)
+	fmt.Fprintf(&b, "Creator: Tool: %s\n", creator)
+	fmt.Fprintf(&b, "Created: %s\n", meta.Timestamp.Format(time.RFC3339))
+
+	// ids maps component paths to SPDX identifiers, the main module is the first package.
+	ids := map[string]string{bom.Main.Path: spdxPackageID(0)}
+	for i, component := range bom.Components {
+		ids[component.Path] = spdxPackageID(i + 1)
+	}
+
+	writeSPDXPackage(&b, bom.Main, ids[bom.Main.Path])
+	for _, component := range bom.Components {
+		writeSPDXPackage(&b, component, ids[component.Path])
+	}
+
+	fmt.Fprintf(&b, "\nRelationship: SPDXRef-DOCUMENT DESCRIBES %s\n", ids[bom.Main.Path])
+	writeSPDXRelationships(&b, bom.Main, ids)
+	for _, component := range bom.Components {
+		writeSPDXRelationships(&b, component, ids)
+	}
+
+	return b.Bytes(), nil
+}
+
+func writeSPDXPackage(b *bytes.Buffer, component internal.Component, id string) {
+	fmt.Fprintf(b, "\nPackageName: %s\n", component.Path)
+	fmt.Fprintf(b, "SPDXID: %s\n", id)
+	if component.Version != "" {
+		fmt.Fprintf(b, "PackageVersion: %s\n", component.Version)
+	}
+	fmt.Fprintf(b, "PackageDownloadLocation: NOASSERTION\n")
+	fmt.Fprintf(b, "FilesAnalyzed: false\n")
+	fmt.Fprintf(b, "PackageLicenseConcluded: %s\n", spdxLicense(component.License))
+	fmt.Fprintf(b, "PackageLicenseDeclared: NOASSERTION\n")
+	fmt.Fprintf(b, "PackageCopyrightText: NOASSERTION\n")
+	fmt.Fprintf(b, "ExternalRef: PACKAGE-MANAGER purl %s\n", component.PackageURL())
+}
+
+func writeSPDXRelationships(b *bytes.Buffer, component internal.Component, ids map[string]string) {
+	for _, path := range component.Dependencies {
+		fmt.Fprintf(b, "Relationship: %s DEPENDS_ON %s\n", ids[component.Path], ids[path])
+	}
+}
+
+// spdxLicense returns license expression of the result, NOASSERTION if it isn't known.
+func spdxLicense(result internal.LicenseResult) string {
+	if result.Error != nil || result.SPDXID == "" {
+		return internal.NoAssertion
+	}
+
+	return result.SPDXID
+}
+
+func spdxPackageID(i int) string {
+	return fmt.Sprintf("SPDXRef-Package-%d", i)
+}
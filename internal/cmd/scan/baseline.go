@@ -0,0 +1,105 @@
+package scan
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// baseline is the file of --baseline and --write-baseline, it lists accepted advisories of module versions.
+type baseline struct {
+	Vulnerabilities []baselineEntry `json:"vulnerabilities"`
+}
+
+// baselineEntry is an accepted advisory, it matches only the same module version.
+type baselineEntry struct {
+	ID      string `json:"id"`
+	Module  string `json:"module"`
+	Version string `json:"version"`
+}
+
+// readBaseline reads a baseline file, entries are returned as a set.
+func readBaseline(file string) (map[baselineEntry]bool, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	b := baseline{}
+	if err := json.Unmarshal(content, &b); err != nil {
+		return nil, err
+	}
+
+	entries := make(map[baselineEntry]bool, len(b.Vulnerabilities))
+	for _, entry := range b.Vulnerabilities {
+		entries[entry] = true
+	}
+
+	return entries, nil
+}
+
+// writeBaseline writes the advisories of the results into a baseline file, sorted by module, version and ID.
+func writeBaseline(file string, results map[string]internal.VulnerabilityResult) error {
+	b := baseline{Vulnerabilities: []baselineEntry{}}
+
+	for path, result := range results {
+		for _, v := range result.Vulnerabilities {
+			b.Vulnerabilities = append(b.Vulnerabilities, newBaselineEntry(path, result, v))
+		}
+	}
+
+	sort.Slice(b.Vulnerabilities, func(i, j int) bool {
+		x, y := b.Vulnerabilities[i], b.Vulnerabilities[j]
+		if x.Module != y.Module {
+			return x.Module < y.Module
+		}
+
+		if x.Version != y.Version {
+			return x.Version < y.Version
+		}
+
+		return x.ID < y.ID
+	})
+
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, append(content, '\n'), 0644)
+}
+
+func newBaselineEntry(path string, result internal.VulnerabilityResult, v internal.Vulnerability) baselineEntry {
+	entry := baselineEntry{ID: v.ID, Module: path}
+	if result.LocalVersion != nil {
+		entry.Version = result.LocalVersion.Original()
+	}
+
+	return entry
+}
+
+// suppress removes advisories of the baseline from the result and returns how many were removed.
+// Upgrades are dropped with the last advisory, there is nothing left for them to fix.
+func suppress(entries map[baselineEntry]bool, path string, result *internal.VulnerabilityResult) int {
+	if len(entries) == 0 || len(result.Vulnerabilities) == 0 {
+		return 0
+	}
+
+	var kept []internal.Vulnerability
+	for _, v := range result.Vulnerabilities {
+		if !entries[newBaselineEntry(path, *result, v)] {
+			kept = append(kept, v)
+		}
+	}
+
+	n := len(result.Vulnerabilities) - len(kept)
+
+	result.Vulnerabilities = kept
+	if len(kept) == 0 {
+		result.Upgrades = nil
+	}
+
+	return n
+}
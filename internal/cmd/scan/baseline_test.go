@@ -0,0 +1,93 @@
+package scan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "baseline")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "baseline.json")
+
+	accepted := map[string]internal.VulnerabilityResult{
+		"example.com/a": {
+			LocalVersion:    semver.MustParse("v1.0.0"),
+			Vulnerabilities: []internal.Vulnerability{{ID: "GO-2021-0002"}, {ID: "GO-2021-0001"}},
+		},
+		"example.com/b": {
+			LocalVersion:    semver.MustParse("v0.3.0"),
+			Vulnerabilities: []internal.Vulnerability{{ID: "GO-2021-0003"}},
+		},
+	}
+	require.NoError(t, writeBaseline(file, accepted))
+
+	entries, err := readBaseline(file)
+	require.NoError(t, err)
+	assert.Equal(t, map[baselineEntry]bool{
+		{ID: "GO-2021-0001", Module: "example.com/a", Version: "v1.0.0"}: true,
+		{ID: "GO-2021-0002", Module: "example.com/a", Version: "v1.0.0"}: true,
+		{ID: "GO-2021-0003", Module: "example.com/b", Version: "v0.3.0"}: true,
+	}, entries)
+
+	// a new advisory of an accepted version is still reported.
+	result := internal.VulnerabilityResult{
+		LocalVersion:    semver.MustParse("v1.0.0"),
+		Vulnerabilities: []internal.Vulnerability{{ID: "GO-2021-0001"}, {ID: "GO-2021-0004"}},
+		Upgrades:        []internal.Requirement{{Path: "example.com/a", Version: "v1.1.0"}},
+	}
+	assert.Equal(t, 1, suppress(entries, "example.com/a", &result))
+	assert.Equal(t, []internal.Vulnerability{{ID: "GO-2021-0004"}}, result.Vulnerabilities)
+	assert.NotEmpty(t, result.Upgrades, "upgrades are kept while any advisory is left")
+
+	// an accepted advisory is reported again for another module or version.
+	result = internal.VulnerabilityResult{
+		LocalVersion:    semver.MustParse("v0.3.0"),
+		Vulnerabilities: []internal.Vulnerability{{ID: "GO-2021-0001"}},
+	}
+	assert.Equal(t, 0, suppress(entries, "example.com/b", &result))
+	assert.Len(t, result.Vulnerabilities, 1)
+
+	result = internal.VulnerabilityResult{
+		LocalVersion:    semver.MustParse("v1.2.0"),
+		Vulnerabilities: []internal.Vulnerability{{ID: "GO-2021-0001"}},
+	}
+	assert.Equal(t, 0, suppress(entries, "example.com/a", &result), "an upgrade is reported again")
+	assert.Len(t, result.Vulnerabilities, 1)
+
+	// upgrades are dropped with the last advisory.
+	result = internal.VulnerabilityResult{
+		LocalVersion:    semver.MustParse("v0.3.0"),
+		Vulnerabilities: []internal.Vulnerability{{ID: "GO-2021-0003"}},
+		Upgrades:        []internal.Requirement{{Path: "example.com/b", Version: "v0.4.0"}},
+	}
+	assert.Equal(t, 1, suppress(entries, "example.com/b", &result))
+	assert.Empty(t, result.Vulnerabilities)
+	assert.Nil(t, result.Upgrades)
+}
+
+func TestWriteBaselineEmpty(t *testing.T) {
+	dir, err := ioutil.TempDir("", "baseline")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "baseline.json")
+	require.NoError(t, writeBaseline(file, nil))
+
+	content, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"vulnerabilities\": []\n}\n", string(content))
+
+	entries, err := readBaseline(file)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,80 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+)
+
+// validateGosec rejects formats and flags of the OSV scan, they don't apply to gosec issues.
+func (o *Options) validateGosec() error {
+	switch o.Format {
+	case printer.FormatTable, printer.FormatJSON, printer.FormatYAML:
+	default:
+		return fmt.Errorf("--gosec can't be used with --format %s, only table, json and yaml are supported", o.Format)
+	}
+
+	if o.MinSeverity != "" || o.FailOnSeverity != "" || o.Changed || o.DB != "" || o.DBURL != "" || o.Upgrades ||
+		len(o.Trust) > 0 || o.ProdOnly || o.Baseline != "" || o.WriteBaseline != "" {
+		return errors.New("--gosec can't be used with flags of the OSV scan")
+	}
+
+	return nil
+}
+
+// executeGosec prints issues found by gosec, they don't fail the scan.
+func (o *Options) executeGosec(scanner Scanner) error {
+	results, err := scanner.Gosec(o.Path)
+	if err != nil {
+		return &internal.ExitError{Code: ExitCodeError, Err: err}
+	}
+
+	printer.Print(&GosecPrinter{results: results}, o.Format)
+
+	return nil
+}
+
+// GosecPrinter implements Printer interface for scan --gosec.
+type GosecPrinter struct {
+	results map[string]internal.GosecResult
+}
+
+// TableData returns table friendly result.
+func (r *GosecPrinter) TableData() *printer.TableData {
+	names := make([]string, 0, len(r.results))
+	for name := range r.results {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var data [][]string
+
+	for _, name := range names {
+		for _, issue := range r.results[name].Issues {
+			data = append(data, []string{
+				name,
+				issue.Confidence,
+				issue.Severity,
+				issue.Cwe.URL,
+				fmt.Sprintf("%s\nln:%s | col:%s \n%s", issue.File, issue.Line, issue.Column, issue.Code),
+			})
+		}
+	}
+
+	return &printer.TableData{
+		Header:       []string{"Module", "Confidence", "Severity", "CWE", "Line,Column"},
+		RowSeparator: "-",
+		ShowBorder:   false,
+		ShowRowLine:  true,
+		Data:         data,
+	}
+}
+
+// JSONData returns JSON friendly result.
+func (r *GosecPrinter) JSONData() interface{} {
+	return r.results
+}
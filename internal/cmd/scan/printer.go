@@ -0,0 +1,228 @@
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+)
+
+// trustedText marks modules matching a trusted prefix.
+const trustedText = "trusted, not scanned"
+
+// ResultPrinter implements Printer interface for Scan command.
+type ResultPrinter struct {
+	vulnerabilityResults map[string]internal.VulnerabilityResult
+	// FailOnSeverity is the lowest rating of advisories reported as failures in JUnit output, every advisory fails if it is unset.
+	FailOnSeverity internal.Severity
+}
+
+// NewResultPrinter creates a new instance of ResultPrinter.
+func NewResultPrinter(m map[string]internal.VulnerabilityResult) *ResultPrinter {
+	return &ResultPrinter{vulnerabilityResults: m}
+}
+
+// JSONModule is the JSON representation of a scanned module.
+type JSONModule struct {
+	Path            string                   `json:"path"`
+	LocalVersion    string                   `json:"localVersion"`
+	Vulnerabilities []internal.Vulnerability `json:"vulnerabilities"`
+	Upgrades        []JSONUpgrade            `json:"upgrades"`
+	Trusted         bool                     `json:"trusted"`
+	Error           *string                  `json:"error"`
+}
+
+// JSONUpgrade is the JSON representation of a direct dependency upgrade fixing a transitive module.
+type JSONUpgrade struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+}
+
+// TableData returns table friendly result, only vulnerable, trusted and failed modules are listed.
+func (r *ResultPrinter) TableData() *printer.TableData {
+	var data [][]string
+	vulnerabilities := 0
+
+	for _, name := range r.names() {
+		result := r.vulnerabilityResults[name]
+
+		if result.Error != nil {
+			data = append(data, []string{name, result.LocalVersion.Original(), fmt.Sprintf("failed because of: %s", result.Error.Error()), "", ""})
+			continue
+		}
+
+		if result.Trusted {
+			data = append(data, []string{name, result.LocalVersion.Original(), trustedText, "", ""})
+			continue
+		}
+
+		for _, v := range result.Vulnerabilities {
+			vulnerabilities++
+
+			id := v.ID
+			if len(v.Aliases) > 0 {
+				id = fmt.Sprintf("%s\n%s", v.ID, strings.Join(v.Aliases, "\n"))
+			}
+
+			fixed := v.Fixed
+			if fixed == "" {
+				fixed = "not fixed"
+			}
+
+			for _, u := range result.Upgrades {
+				fixed += fmt.Sprintf("\nvia %s@%s", u.Path, u.Version)
+			}
+
+			data = append(data, []string{name, result.LocalVersion.Original(), id, severity(v), fixed})
+		}
+	}
+
+	td := &printer.TableData{
+		Header:       []string{"Module", "Version", "Advisory", "Severity", "Fixed"},
+		Footer:       []string{"", "", "", "number of vulnerabilities", strconv.Itoa(vulnerabilities)},
+		RowSeparator: "-",
+		ShowBorder:   false,
+		ShowRowLine:  true,
+		Data:         data,
+	}
+
+	return td
+}
+
+// JSONData returns JSON friendly result.
+func (r *ResultPrinter) JSONData() interface{} {
+	modules := make([]JSONModule, 0, len(r.vulnerabilityResults))
+
+	for _, name := range r.names() {
+		modules = append(modules, jsonModule(name, r.vulnerabilityResults[name]))
+	}
+
+	return modules
+}
+
+// jsonModule returns the JSON representation of a scanned module, it is a line of jsonl output too.
+func jsonModule(name string, result internal.VulnerabilityResult) JSONModule {
+	m := JSONModule{
+		Path:            name,
+		LocalVersion:    result.LocalVersion.Original(),
+		Vulnerabilities: result.Vulnerabilities,
+		Trusted:         result.Trusted,
+	}
+
+	if m.Vulnerabilities == nil {
+		m.Vulnerabilities = []internal.Vulnerability{}
+	}
+
+	m.Upgrades = []JSONUpgrade{}
+	for _, u := range result.Upgrades {
+		m.Upgrades = append(m.Upgrades, JSONUpgrade{Path: u.Path, Version: u.Version})
+	}
+
+	if result.Error != nil {
+		e := result.Error.Error()
+		m.Error = &e
+	}
+
+	return m
+}
+
+// JUnitData returns result as a JUnit test suite, vulnerable modules fail and those which can't be scanned are errors.
+// Advisories rated lower than FailOnSeverity are listed in the output of the passing test case.
+func (r *ResultPrinter) JUnitData() *printer.JUnitTestSuite {
+	suite := &printer.JUnitTestSuite{Name: "gomodctl scan"}
+
+	for _, name := range r.names() {
+		result := r.vulnerabilityResults[name]
+
+		c := printer.JUnitTestCase{Name: name, Classname: "gomodctl.scan"}
+
+		if result.Error != nil {
+			c.Error = &printer.JUnitMessage{Message: result.Error.Error(), Type: "ScanError"}
+			suite.Cases = append(suite.Cases, c)
+			continue
+		}
+
+		if result.Trusted {
+			c.SystemOut = trustedText
+			suite.Cases = append(suite.Cases, c)
+			continue
+		}
+
+		var failing, passing []string
+		var ids []string
+
+		for _, v := range result.Vulnerabilities {
+			if v.Rating.AtLeast(r.FailOnSeverity) {
+				failing = append(failing, advisory(v))
+				ids = append(ids, v.ID)
+			} else {
+				passing = append(passing, advisory(v))
+			}
+		}
+
+		if len(failing) > 0 {
+			c.Failure = &printer.JUnitMessage{
+				Message: fmt.Sprintf("%s is affected by %s", result.LocalVersion.Original(), strings.Join(ids, ", ")),
+				Type:    "Vulnerability",
+				Text:    strings.Join(append(failing, upgrades(result)...), "\n"),
+			}
+		}
+
+		c.SystemOut = strings.Join(passing, "\n")
+
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	return suite
+}
+
+// advisory describes the vulnerability on a single line.
+func advisory(v internal.Vulnerability) string {
+	id := v.ID
+	if len(v.Aliases) > 0 {
+		id += " (" + strings.Join(v.Aliases, ", ") + ")"
+	}
+
+	fixed := "not fixed"
+	if v.Fixed != "" {
+		fixed = "fixed in " + v.Fixed
+	}
+
+	return fmt.Sprintf("%s, severity %s, %s: %s", id, severity(v), fixed, v.Summary)
+}
+
+// upgrades describes the direct dependency upgrades fixing the module, one per line.
+func upgrades(result internal.VulnerabilityResult) []string {
+	lines := make([]string, 0, len(result.Upgrades))
+	for _, u := range result.Upgrades {
+		lines = append(lines, fmt.Sprintf("fixed by upgrading %s to %s", u.Path, u.Version))
+	}
+
+	return lines
+}
+
+func (r *ResultPrinter) names() []string {
+	names := make([]string, 0, len(r.vulnerabilityResults))
+	for name := range r.vulnerabilityResults {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// severity returns rating and score of the vulnerability.
+func severity(v internal.Vulnerability) string {
+	switch {
+	case v.Rating == internal.SeverityUnknown:
+		return "unknown"
+	case v.Score == 0:
+		return string(v.Rating)
+	}
+
+	return fmt.Sprintf("%s (%.1f)", v.Rating, v.Score)
+}
@@ -0,0 +1,198 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+const (
+	// FormatSARIF is SARIF 2.1.0 JSON format.
+	FormatSARIF = "sarif"
+
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	toolName           = "gomodctl"
+	toolInformationURI = "https://github.com/beatlabs/gomodctl"
+	advisoryURI        = "https://osv.dev/vulnerability/"
+)
+
+type sarifDocument struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string              `json:"id"`
+	ShortDescription sarifMessage        `json:"shortDescription"`
+	HelpURI          string              `json:"helpUri"`
+	Properties       sarifRuleProperties `json:"properties"`
+}
+
+// sarifRuleProperties are read by GitHub code scanning, security-severity is the CVSS score.
+type sarifRuleProperties struct {
+	SecuritySeverity string   `json:"security-severity,omitempty"`
+	Tags             []string `json:"tags"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	// Region is nil for modules which go.mod requires only transitively.
+	Region *sarifRegion `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// encodeSARIF encodes scan results as a SARIF report, every vulnerability is a result located at the require line in goMod.
+// Modules which can't be scanned are reported as notifications of an unsuccessful invocation.
+func encodeSARIF(r *ResultPrinter, goMod, toolVersion string) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			Version:        toolVersion,
+			InformationURI: toolInformationURI,
+			Rules:          []sarifRule{},
+		}},
+		Invocations: []sarifInvocation{{ExecutionSuccessful: true}},
+		Results:     []sarifResult{},
+	}
+
+	rules := make(map[string]bool)
+
+	for _, name := range r.names() {
+		result := r.vulnerabilityResults[name]
+
+		if result.Error != nil {
+			run.Invocations[0].ExecutionSuccessful = false
+			run.Invocations[0].ToolExecutionNotifications = append(run.Invocations[0].ToolExecutionNotifications, sarifNotification{
+				Level:   "error",
+				Message: sarifMessage{Text: fmt.Sprintf("%s: %s", name, result.Error)},
+			})
+
+			continue
+		}
+
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: goMod}}
+		if result.Position.IsValid() {
+			location.Region = &sarifRegion{StartLine: result.Position.Line, StartColumn: result.Position.Column}
+		}
+
+		fix := ""
+		if lines := upgrades(result); len(lines) > 0 {
+			fix = ", " + strings.Join(lines, " or ")
+		}
+
+		for _, v := range result.Vulnerabilities {
+			if !rules[v.ID] {
+				rules[v.ID] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, newSARIFRule(v))
+			}
+
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    v.ID,
+				Level:     sarifLevel(v.Rating),
+				Message:   sarifMessage{Text: fmt.Sprintf("%s@%s is affected by %s%s", name, result.LocalVersion.Original(), advisory(v), fix)},
+				Locations: []sarifLocation{{PhysicalLocation: location}},
+			})
+		}
+	}
+
+	document := sarifDocument{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	b, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+func newSARIFRule(v internal.Vulnerability) sarifRule {
+	summary := v.Summary
+	if summary == "" {
+		summary = v.ID
+	}
+
+	rule := sarifRule{
+		ID:               v.ID,
+		ShortDescription: sarifMessage{Text: summary},
+		HelpURI:          advisoryURI + v.ID,
+		Properties:       sarifRuleProperties{Tags: []string{"security", "vulnerability"}},
+	}
+
+	if v.Score > 0 {
+		rule.Properties.SecuritySeverity = strconv.FormatFloat(v.Score, 'f', 1, 64)
+	}
+
+	return rule
+}
+
+// sarifLevel maps the rating to a SARIF level, unrated advisories are warnings.
+func sarifLevel(rating internal.Severity) string {
+	switch rating {
+	case internal.SeverityCritical, internal.SeverityHigh:
+		return "error"
+	case internal.SeverityLow:
+		return "note"
+	}
+
+	return "warning"
+}
@@ -1,23 +1,56 @@
 package scan
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"path/filepath"
 
 	"github.com/beatlabs/gomodctl/internal"
-	"github.com/olekukonko/tablewriter"
-
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"github.com/beatlabs/gomodctl/internal/printer"
+	"github.com/beatlabs/gomodctl/internal/progress"
 	"github.com/spf13/cobra"
 )
 
+// FormatJSONLines prints every module as a JSON object on its own line as soon as it is scanned.
+const FormatJSONLines = "jsonl"
+
+const (
+	// ExitCodeVulnerable is the exit status when any module has known vulnerabilities.
+	ExitCodeVulnerable = 1
+	// ExitCodeError is the exit status when modules can't be scanned.
+	ExitCodeError = 2
+)
+
 // Scanner is exported.
 type Scanner interface {
-	Scan(path string) (map[string]internal.VulnerabilityResult, error)
+	Scan(path string, options internal.ScanOptions) (map[string]internal.VulnerabilityResult, error)
+	Gosec(path string) (map[string]internal.GosecResult, error)
 }
 
 // Options is exported.
 type Options struct {
-	Path string
+	Path           string
+	JSON           bool
+	Format         string
+	MinSeverity    string
+	FailOnSeverity string
+	Changed        bool
+	DB             string
+	DBURL          string
+	Upgrades       bool
+	Trust          []string
+	ProdOnly       bool
+	Baseline       string
+	WriteBaseline  string
+	ToolVersion    string
+	// Gosec runs the gosec static analyzer on sources of the dependencies instead of looking up advisories.
+	Gosec bool
+
+	minSeverity    internal.Severity
+	failOnSeverity internal.Severity
+	baseline       map[baselineEntry]bool
 }
 
 // NewCmdScan returns an instance of Scan command.
@@ -25,57 +58,262 @@ func NewCmdScan(scanner Scanner) *cobra.Command {
 	o := Options{}
 
 	cmd := &cobra.Command{
-		Use:   "scan [module name] [OPTIONS]",
-		Short: "scan local for security vulnerabilities",
-		Long:  `scan local module for security vulnerabilities`,
+		Use:   "scan [path]",
+		Short: "scan local module dependencies for known vulnerabilities",
+		Long:  `scan local module dependencies for known vulnerabilities of the OSV database`,
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 1 {
-				o.Path = ""
-			} else {
+			o.Fill(cmd)
+
+			if len(args) > 0 {
 				o.Path = args[0]
 			}
-			return nil
+
+			return o.validate()
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			o.Execute(scanner)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return o.Execute(scanner)
 		},
+		Annotations: map[string]string{printer.FormatsAnnotation: printer.FormatJUnit + " " + FormatSARIF + " " + printer.FormatYAML + " " + FormatJSONLines},
 	}
 
+	cmd.Flags().String("min-severity", "", "leave out advisories rated lower: low, medium, high or critical")
+	cmd.Flags().String("fail-on-severity", "", "exit with status 1 only on advisories of the given rating or higher: low, medium, high or critical")
+	cmd.Flags().Bool("changed", false, "only scan modules whose require lines in go.mod changed since git HEAD")
+	cmd.Flags().String("db", "", "read advisories from a local OSV database instead of the OSV API: a directory or a file of OSV JSON records or a zip of them")
+	cmd.Flags().String("db-url", "", "URL of an OSV API mirror (default is osv_url config or https://api.osv.dev)")
+	cmd.Flags().StringArray("trust", nil, "module path prefix which isn't scanned, e.g. github.com/mycompany, added to trusted_prefixes config, can be repeated")
+	cmd.Flags().Bool("prod-only", false, "only scan modules imported by non-test packages of the module, leaving out those used only by tests")
+	cmd.Flags().String("baseline", "", "file of accepted advisories written by --write-baseline, they aren't reported and don't fail the scan")
+	cmd.Flags().String("write-baseline", "", "write the advisories found into a baseline file, they don't fail the scan")
+	cmd.Flags().Bool("upgrades", false, "report upgrades of direct dependencies which pull in fixed versions of vulnerable transitive modules")
+	cmd.Flags().Bool("gosec", false, "run gosec on sources of the direct dependencies instead of looking up advisories in the OSV database")
+
 	return cmd
 }
 
-// Execute is exported.
-func (o *Options) Execute(scanner Scanner) {
+// Fill fills flags into options.
+func (o *Options) Fill(cmd *cobra.Command) {
+	o.Format = printer.FormatOf(cmd.Flags())
+	o.JSON = o.Format == printer.FormatJSON
+	o.Path, _ = cmd.Flags().GetString("path")
+	o.MinSeverity, _ = cmd.Flags().GetString("min-severity")
+	o.FailOnSeverity, _ = cmd.Flags().GetString("fail-on-severity")
+	o.Changed, _ = cmd.Flags().GetBool("changed")
+	o.DB, _ = cmd.Flags().GetString("db")
+	o.DBURL, _ = cmd.Flags().GetString("db-url")
+	o.Upgrades, _ = cmd.Flags().GetBool("upgrades")
+	o.Trust, _ = cmd.Flags().GetStringArray("trust")
+	o.ProdOnly, _ = cmd.Flags().GetBool("prod-only")
+	o.Baseline, _ = cmd.Flags().GetString("baseline")
+	o.WriteBaseline, _ = cmd.Flags().GetString("write-baseline")
+	o.Gosec, _ = cmd.Flags().GetBool("gosec")
+	o.ToolVersion = cmd.Root().Version
+}
+
+func (o *Options) validate() error {
 	var err error
-	var vulnerabilitiesResult map[string]internal.VulnerabilityResult
-	vulnerabilitiesResult, err = scanner.Scan(o.Path)
+
+	if o.DB != "" && o.DBURL != "" {
+		return errors.New("only one of --db and --db-url can be set")
+	}
+
+	if o.Baseline != "" && o.WriteBaseline != "" {
+		return errors.New("only one of --baseline and --write-baseline can be set")
+	}
+
+	if o.Gosec {
+		return o.validateGosec()
+	}
+
+	if o.Upgrades && o.Format == FormatJSONLines {
+		return errors.New("--upgrades can't be used with --format jsonl, upgrades are resolved once every module is scanned")
+	}
+
+	if o.MinSeverity != "" {
+		if o.minSeverity, err = internal.ParseSeverity(o.MinSeverity); err != nil {
+			return fmt.Errorf("--min-severity: %w", err)
+		}
+	}
+
+	if o.FailOnSeverity != "" {
+		if o.failOnSeverity, err = internal.ParseSeverity(o.FailOnSeverity); err != nil {
+			return fmt.Errorf("--fail-on-severity: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Execute is exported.
+func (o *Options) Execute(scanner Scanner) error {
+	if o.Gosec {
+		return o.executeGosec(scanner)
+	}
+
+	if o.Baseline != "" {
+		entries, err := readBaseline(o.Baseline)
+		if err != nil {
+			return &internal.ExitError{Code: ExitCodeError, Err: fmt.Errorf("baseline %s: %w", o.Baseline, err)}
+		}
+
+		o.baseline = entries
+	}
+
+	if o.Format == FormatJSONLines {
+		return o.executeStream(scanner)
+	}
+
+	spinner := progress.StartIfEnabled(o.Format, "scanned %d/%d modules")
+	vulnerabilitiesResult, err := scanner.Scan(o.Path, o.scanOptions(spinner.Update))
+	spinner.Stop()
 	if err != nil {
-		fmt.Println(err)
-		return
+		return &internal.ExitError{Code: ExitCodeError, Err: err}
+	}
+
+	if o.WriteBaseline != "" {
+		if err := writeBaseline(o.WriteBaseline, vulnerabilitiesResult); err != nil {
+			return &internal.ExitError{Code: ExitCodeError, Err: fmt.Errorf("baseline %s: %w", o.WriteBaseline, err)}
+		}
+	}
+
+	suppressed := 0
+	for path, result := range vulnerabilitiesResult {
+		suppressed += suppress(o.baseline, path, &result)
+		vulnerabilitiesResult[path] = result
+	}
+
+	o.logSuppressed(suppressed)
+
+	rp := NewResultPrinter(vulnerabilitiesResult)
+	rp.FailOnSeverity = o.failOnSeverity
+
+	if o.Format == FormatSARIF {
+		document, err := encodeSARIF(rp, goModURI(o.Path), o.ToolVersion)
+		if err != nil {
+			return &internal.ExitError{Code: ExitCodeError, Err: err}
+		}
+
+		if _, err := printer.Output().Write(document); err != nil {
+			return &internal.ExitError{Code: ExitCodeError, Err: err}
+		}
+	} else {
+		printer.Print(rp, o.Format)
+	}
+
+	code := 0
+	for _, result := range vulnerabilitiesResult {
+		if c := o.exitCode(result); c > code {
+			code = c
+		}
+	}
+
+	if code != 0 {
+		return &internal.ExitError{Code: code}
+	}
+
+	return nil
+}
+
+// executeStream prints every module as a line of JSON as soon as it is scanned, results aren't kept in memory.
+// Lines are in the order modules are done, not sorted.
+func (o *Options) executeStream(scanner Scanner) error {
+	encoder := json.NewEncoder(printer.Output())
+
+	var encodeErr error
+	code, suppressed := 0, 0
+	vulnerable := make(map[string]internal.VulnerabilityResult)
+
+	options := o.scanOptions(nil)
+	options.Stream = func(path string, result internal.VulnerabilityResult) {
+		if o.WriteBaseline != "" && len(result.Vulnerabilities) > 0 {
+			vulnerable[path] = result
+		}
+
+		suppressed += suppress(o.baseline, path, &result)
+
+		if encodeErr == nil {
+			encodeErr = encoder.Encode(jsonModule(path, result))
+		}
+
+		if c := o.exitCode(result); c > code {
+			code = c
+		}
+	}
+
+	if _, err := scanner.Scan(o.Path, options); err != nil {
+		return &internal.ExitError{Code: ExitCodeError, Err: err}
+	}
+
+	if encodeErr != nil {
+		return &internal.ExitError{Code: ExitCodeError, Err: encodeErr}
+	}
+
+	if o.WriteBaseline != "" {
+		if err := writeBaseline(o.WriteBaseline, vulnerable); err != nil {
+			return &internal.ExitError{Code: ExitCodeError, Err: fmt.Errorf("baseline %s: %w", o.WriteBaseline, err)}
+		}
+	}
+
+	o.logSuppressed(suppressed)
+
+	if code != 0 {
+		return &internal.ExitError{Code: code}
+	}
+
+	return nil
+}
+
+func (o *Options) scanOptions(progress func(done, total int)) internal.ScanOptions {
+	return internal.ScanOptions{
+		MinSeverity: o.minSeverity,
+		Progress:    progress,
+		Changed:     o.Changed,
+		DB:          o.DB,
+		DBURL:       o.DBURL,
+		Upgrades:    o.Upgrades,
+		Trust:       o.Trust,
+		ProdOnly:    o.ProdOnly,
 	}
-	renderResults(vulnerabilitiesResult)
 }
 
-// renderResults renders the vulnerabilities.
-func renderResults(vulnerabilitiesResult map[string]internal.VulnerabilityResult) {
-	var data [][]string
-	for name, result := range vulnerabilitiesResult {
-		for _, issue := range result.Issues {
-			data = append(data, []string{
-				name,
-				issue.Confidence,
-				issue.Severity,
-				issue.Cwe.URL,
-				fmt.Sprintf("%s\nln:%s | col:%s \n%s", issue.File, issue.Line, issue.Column, issue.Code),
-			})
+// logSuppressed logs how many advisories of the baseline aren't reported.
+func (o *Options) logSuppressed(n int) {
+	if o.Baseline != "" {
+		logger.Infof("%d advisories are in the baseline %s and aren't reported", n, o.Baseline)
+	}
+}
+
+// exitCode returns ExitCodeError if the module can't be scanned, ExitCodeVulnerable if it has an advisory rated
+// --fail-on-severity or higher and 0 otherwise. Advisories don't fail the scan while writing a baseline.
+func (o *Options) exitCode(result internal.VulnerabilityResult) int {
+	if result.Error != nil {
+		return ExitCodeError
+	}
+
+	if o.WriteBaseline != "" {
+		return 0
+	}
+
+	for _, v := range result.Vulnerabilities {
+		if v.Rating.AtLeast(o.failOnSeverity) {
+			return ExitCodeVulnerable
 		}
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Module", "Confidence", "Severity", "CWE", "Line,Column"})
-	table.SetBorder(false)
-	table.SetRowLine(true)
-	table.SetRowSeparator("-")
-	table.AppendBulk(data)
-	table.Render()
+	return 0
+}
+
+// goModURI returns the SARIF artifact location of go.mod in the given path, relative paths are kept relative.
+func goModURI(path string) string {
+	if path == internal.StdinPath {
+		path = ""
+	}
+
+	file := filepath.ToSlash(filepath.Join(path, "go.mod"))
+	if filepath.IsAbs(path) {
+		return "file://" + file
+	}
+
+	return file
 }
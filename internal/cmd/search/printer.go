@@ -10,36 +10,20 @@ import (
 
 // ResultPrinter implements Printer interface for Search command.
 type ResultPrinter struct {
-	List    []internal.SearchResult
-	ShowAll bool
+	List []internal.SearchResult
 }
 
 // NewResultPrinter creates a new instance of ResultPrinter.
-func NewResultPrinter(results []internal.SearchResult, showAll bool) *ResultPrinter {
+func NewResultPrinter(results []internal.SearchResult) *ResultPrinter {
 	return &ResultPrinter{
-		List:    results,
-		ShowAll: showAll,
+		List: results,
 	}
 }
 
-func (p *ResultPrinter) calcLimit(srs []internal.SearchResult) int {
-	c := len(srs)
-	if c > LIMIT {
-		if p.ShowAll {
-			return c
-		}
-		return LIMIT
-	}
-	return c
-}
-
 // TableData returns table friendly result.
 func (p *ResultPrinter) TableData() *printer.TableData {
 	var data [][]string
-	limit := p.calcLimit(p.List)
-	limitedResults := p.List[:limit]
-
-	for _, result := range limitedResults {
+	for _, result := range p.List {
 		data = append(data, []string{
 			result.Path,
 			strconv.Itoa(result.Stars),
@@ -49,7 +33,7 @@ func (p *ResultPrinter) TableData() *printer.TableData {
 	}
 
 	td := &printer.TableData{
-		Header:       []string{"Name", "Stars", "Import count", "Score"},
+		Header:       []string{"Name", "Stars", "Imported by", "Score"},
 		Footer:       []string{"", "", "number of modules", strconv.Itoa(len(data))},
 		RowSeparator: "-",
 		ShowBorder:   false,
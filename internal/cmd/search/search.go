@@ -3,6 +3,7 @@ package search
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/beatlabs/gomodctl/internal"
@@ -15,6 +16,13 @@ const (
 	LIMIT = 20
 )
 
+// Sort orders of the --sort flag.
+const (
+	SortRelevance = "relevance"
+	SortStars     = "stars"
+	SortName      = "name"
+)
+
 // Searcher is exported.
 type Searcher interface {
 	Search(term string) ([]internal.SearchResult, error)
@@ -24,7 +32,10 @@ type Searcher interface {
 type Options struct {
 	Term    string
 	ShowAll bool
+	Limit   int
+	Sort    string
 	JSON    bool
+	Format  string
 }
 
 // NewCmdSearch returns an instance of Search command.
@@ -41,15 +52,18 @@ func NewCmdSearch(searcher Searcher) *cobra.Command {
 			}
 
 			o.Term = strings.Join(args, " ")
-			return nil
+
+			o.Fill(cmd)
+			return o.validate()
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			o.Fill(cmd)
 			o.Execute(searcher)
 		},
 	}
 
-	cmd.Flags().BoolP("show-all", "a", o.ShowAll, "--show-all")
+	cmd.Flags().BoolP("show-all", "a", o.ShowAll, "show all results, overrides --limit")
+	cmd.Flags().Int("limit", LIMIT, "maximum number of results")
+	cmd.Flags().String("sort", SortRelevance, "order of results: relevance, stars or name")
 
 	return cmd
 }
@@ -57,7 +71,24 @@ func NewCmdSearch(searcher Searcher) *cobra.Command {
 // Fill fills flags into options.
 func (o *Options) Fill(cmd *cobra.Command) {
 	o.ShowAll, _ = cmd.Flags().GetBool("show-all")
-	o.JSON, _ = cmd.Flags().GetBool("json")
+	o.Limit, _ = cmd.Flags().GetInt("limit")
+	o.Sort, _ = cmd.Flags().GetString("sort")
+	o.Format = printer.FormatOf(cmd.Flags())
+	o.JSON = o.Format == printer.FormatJSON
+}
+
+func (o *Options) validate() error {
+	switch o.Sort {
+	case SortRelevance, SortStars, SortName:
+	default:
+		return fmt.Errorf("invalid --sort value %q, must be one of relevance, stars, name", o.Sort)
+	}
+
+	if o.Limit < 1 {
+		return fmt.Errorf("invalid --limit value %d, must be positive", o.Limit)
+	}
+
+	return nil
 }
 
 // Execute is exported.
@@ -72,10 +103,28 @@ func (o *Options) Execute(op Searcher) {
 		return
 	}
 
-	rp := NewResultPrinter(searchResults, o.ShowAll)
-	if o.JSON {
-		printer.PrintJSON(rp)
-	} else {
-		printer.PrintTable(rp)
+	searchResults = sortResults(searchResults, o.Sort)
+
+	if !o.ShowAll && len(searchResults) > o.Limit {
+		searchResults = searchResults[:o.Limit]
 	}
+
+	rp := NewResultPrinter(searchResults)
+	printer.Print(rp, o.Format)
+}
+
+// sortResults orders results by the given sort order, the registry returns them ordered by relevance.
+func sortResults(results []internal.SearchResult, by string) []internal.SearchResult {
+	switch by {
+	case SortStars:
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Stars > results[j].Stars
+		})
+	case SortName:
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Path < results[j].Path
+		})
+	}
+
+	return results
 }
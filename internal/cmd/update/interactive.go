@@ -0,0 +1,192 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"golang.org/x/term"
+)
+
+// ErrSelectionCanceled is returned when interactive selection is quit without confirming.
+var ErrSelectionCanceled = errors.New("selection canceled")
+
+const (
+	keyUp    = "\x1b[A"
+	keyDown  = "\x1b[B"
+	keyCtrlC = "\x03"
+
+	clearScreen = "\x1b[H\x1b[2J"
+
+	// headerLines is number of lines rendered above the module list.
+	headerLines = 3
+)
+
+// selector keeps state of the interactive module selection.
+type selector struct {
+	names    []string
+	results  map[string]internal.CheckResult
+	selected map[string]bool
+	cursor   int
+	offset   int
+	height   int
+}
+
+func newSelector(candidates map[string]internal.CheckResult, height int) *selector {
+	s := &selector{
+		results:  make(map[string]internal.CheckResult),
+		selected: make(map[string]bool),
+		height:   height,
+	}
+
+	for name, result := range candidates {
+		if result.Updatable() {
+			s.names = append(s.names, name)
+			s.results[name] = result
+		}
+	}
+
+	sort.Strings(s.names)
+
+	if s.height < 1 {
+		s.height = 1
+	}
+
+	return s
+}
+
+// handle applies a key press, it returns true when selection is done.
+func (s *selector) handle(key string) (bool, error) {
+	switch key {
+	case keyUp, "k":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case keyDown, "j":
+		if s.cursor < len(s.names)-1 {
+			s.cursor++
+		}
+	case " ":
+		name := s.names[s.cursor]
+		s.selected[name] = !s.selected[name]
+	case "a":
+		all := len(s.selected) != len(s.names)
+		s.selected = make(map[string]bool)
+		if all {
+			for _, name := range s.names {
+				s.selected[name] = true
+			}
+		}
+	case "\r", "\n":
+		return true, nil
+	case "q", keyCtrlC:
+		return true, ErrSelectionCanceled
+	}
+
+	// scroll to keep the cursor visible.
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+s.height {
+		s.offset = s.cursor - s.height + 1
+	}
+
+	return false, nil
+}
+
+func (s *selector) render(w io.Writer) {
+	var b strings.Builder
+
+	b.WriteString(clearScreen)
+	b.WriteString("Select modules to update\r\n")
+	b.WriteString("up/down: move, space: toggle, a: toggle all, enter: confirm, q: quit\r\n\r\n")
+
+	end := s.offset + s.height
+	if end > len(s.names) {
+		end = len(s.names)
+	}
+
+	for i := s.offset; i < end; i++ {
+		name := s.names[i]
+
+		cursor := " "
+		if i == s.cursor {
+			cursor = ">"
+		}
+
+		check := " "
+		if s.selected[name] {
+			check = "x"
+		}
+
+		result := s.results[name]
+		fmt.Fprintf(&b, "%s [%s] %s %s -> %s\r\n", cursor, check, name, result.LocalVersion.Original(), result.LatestVersion.Original())
+	}
+
+	fmt.Fprint(w, b.String())
+}
+
+// selection returns results of the selected modules.
+func (s *selector) selection() map[string]internal.CheckResult {
+	selection := make(map[string]internal.CheckResult)
+
+	for name := range s.selected {
+		if s.selected[name] {
+			selection[name] = s.results[name]
+		}
+	}
+
+	return selection
+}
+
+// selectModules lets the user pick modules to update from the given candidates in the terminal.
+func selectModules(candidates map[string]internal.CheckResult) (map[string]internal.CheckResult, error) {
+	in := int(os.Stdin.Fd())
+	out := int(os.Stdout.Fd())
+
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return nil, errors.New("interactive mode requires a terminal")
+	}
+
+	_, height, err := term.GetSize(out)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSelector(candidates, height-headerLines)
+	if len(s.names) == 0 {
+		return s.selection(), nil
+	}
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return nil, err
+	}
+	defer term.Restore(in, state)
+
+	buf := make([]byte, 3)
+
+	for {
+		s.render(os.Stdout)
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		done, err := s.handle(string(buf[:n]))
+		if done {
+			fmt.Print(clearScreen)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return s.selection(), nil
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package check
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// limit splits the candidates into the updates to apply and the ones deferred by --max-updates.
+// At most max updatable candidates are applied in order of --prioritize, the rest of the candidates are kept,
+// they aren't updated anyway.
+func (o *Options) limit(updater Updater, candidates map[string]internal.CheckResult) (map[string]internal.CheckResult, map[string]internal.CheckResult, error) {
+	if o.MaxUpdates <= 0 {
+		return candidates, nil, nil
+	}
+
+	updatable := make(map[string]internal.CheckResult)
+	for name, result := range candidates {
+		if result.Updatable() {
+			updatable[name] = result
+		}
+	}
+
+	if len(updatable) <= o.MaxUpdates {
+		return candidates, nil, nil
+	}
+
+	order, err := o.prioritize(updater, updatable)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deferred := make(map[string]internal.CheckResult)
+	for _, name := range order[o.MaxUpdates:] {
+		deferred[name] = candidates[name]
+	}
+
+	applied := make(map[string]internal.CheckResult)
+	for name, result := range candidates {
+		if _, ok := deferred[name]; !ok {
+			applied[name] = result
+		}
+	}
+
+	return applied, deferred, nil
+}
+
+// prioritize returns names of the results in order of --prioritize, severities are looked up once for security.
+func (o *Options) prioritize(updater Updater, results map[string]internal.CheckResult) ([]string, error) {
+	if o.priority == internal.PrioritySecurity && o.severities == nil {
+		var err error
+		if o.severities, err = updater.Severities(results); err != nil {
+			return nil, fmt.Errorf("--prioritize security: %w", err)
+		}
+	}
+
+	return internal.Prioritize(results, o.priority, o.severities), nil
+}
+
+// printDeferred lists the updates deferred by --max-updates.
+func printDeferred(deferred map[string]internal.CheckResult) {
+	if len(deferred) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(deferred))
+	for name := range deferred {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("\nUpdates deferred by --max-updates, not applied:\n")
+	for _, name := range names {
+		result := deferred[name]
+		fmt.Printf("  %s %s -> %s\n", name, result.LocalVersion.Original(), result.LatestVersion.Original())
+	}
+}
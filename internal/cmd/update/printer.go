@@ -1,6 +1,7 @@
 package check
 
 import (
+	"sort"
 	"strconv"
 
 	"github.com/beatlabs/gomodctl/internal"
@@ -10,6 +11,10 @@ import (
 // ResultPrinter implements Printer interface for Update command.
 type ResultPrinter struct {
 	Result map[string]internal.CheckResult
+	// Order lists module names in print order, see orderedNames.
+	Order []string
+	// Downgrades marks lower versions, only pins with --allow-downgrade write them.
+	Downgrades bool
 }
 
 // NewResultPrinter creates a new instance of ResultPrinter.
@@ -23,16 +28,28 @@ func NewResultPrinter(results map[string]internal.CheckResult) *ResultPrinter {
 func (p *ResultPrinter) TableData() *printer.TableData {
 	var data [][]string
 
-	for name, result := range p.Result {
+	for _, name := range orderedNames(p.Result, p.Order) {
+		result := p.Result[name]
+
+		localVersion := result.LocalVersion.Original()
+		if result.Replaced {
+			localVersion += " (replaced)"
+		}
+
 		r := []string{
 			name,
-			result.LocalVersion.Original(),
+			localVersion,
 		}
 
 		if result.Error != nil {
 			r = append(r, result.Error.Error())
 		} else {
-			r = append(r, result.LatestVersion.Original())
+			latestVersion := result.LatestVersion.Original()
+			if p.Downgrades && result.Downgrade() {
+				latestVersion += " (downgrade)"
+			}
+
+			r = append(r, latestVersion)
 		}
 
 		data = append(data, r)
@@ -54,3 +71,154 @@ func (p *ResultPrinter) TableData() *printer.TableData {
 func (p *ResultPrinter) JSONData() interface{} {
 	return p.Result
 }
+
+// PlanPrinter implements Printer interface for planned updates of dry run.
+type PlanPrinter struct {
+	Result map[string]internal.CheckResult
+	// Order lists module names in print order, see orderedNames.
+	Order []string
+	// Downgrades plans lower versions too, see ResultPrinter.Downgrades.
+	Downgrades bool
+}
+
+// NewPlanPrinter creates a new instance of PlanPrinter.
+func NewPlanPrinter(results map[string]internal.CheckResult) *PlanPrinter {
+	return &PlanPrinter{
+		Result: results,
+	}
+}
+
+// PlannedUpdate is a go.mod edit planned by dry run.
+type PlannedUpdate struct {
+	Path       string              `json:"path"`
+	From       string              `json:"from"`
+	To         string              `json:"to"`
+	UpdateType internal.UpdateType `json:"updateType"`
+}
+
+func (p *PlanPrinter) plan() []PlannedUpdate {
+	plan := []PlannedUpdate{}
+
+	for _, name := range orderedNames(p.Result, p.Order) {
+		result := p.Result[name]
+		downgrade := p.Downgrades && result.Downgrade()
+		if !result.Updatable() && !downgrade {
+			continue
+		}
+
+		updateType := result.UpdateType()
+		if downgrade {
+			updateType = internal.UpdateDowngrade
+		}
+
+		plan = append(plan, PlannedUpdate{
+			Path:       name,
+			From:       result.LocalVersion.Original(),
+			To:         result.LatestVersion.Original(),
+			UpdateType: updateType,
+		})
+	}
+
+	return plan
+}
+
+// orderedNames returns names of the results in the given order, names missing from it follow sorted by path.
+func orderedNames(results map[string]internal.CheckResult, order []string) []string {
+	names := make([]string, 0, len(results))
+	seen := make(map[string]bool)
+
+	for _, name := range order {
+		if _, ok := results[name]; ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	var rest []string
+	for name := range results {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+
+	sort.Strings(rest)
+
+	return append(names, rest...)
+}
+
+// TableData returns table friendly result.
+func (p *PlanPrinter) TableData() *printer.TableData {
+	var data [][]string
+
+	plan := p.plan()
+	for _, u := range plan {
+		data = append(data, []string{u.Path, u.From, u.To})
+	}
+
+	td := &printer.TableData{
+		Header:       []string{"Module", "Current", "Planned"},
+		Footer:       []string{"", "number of updates", strconv.Itoa(len(plan))},
+		RowSeparator: "-",
+		ShowBorder:   false,
+		ShowRowLine:  false,
+		Data:         data,
+	}
+
+	return td
+}
+
+// JSONData returns JSON friendly result.
+func (p *PlanPrinter) JSONData() interface{} {
+	return p.plan()
+}
+
+// IndirectPrinter implements Printer interface for // indirect comments corrected by --fix-indirect.
+type IndirectPrinter struct {
+	Fixes []internal.IndirectFix
+}
+
+// NewIndirectPrinter creates a new instance of IndirectPrinter.
+func NewIndirectPrinter(fixes []internal.IndirectFix) *IndirectPrinter {
+	return &IndirectPrinter{Fixes: fixes}
+}
+
+// JSONIndirectFix is a requirement whose // indirect comment is corrected.
+type JSONIndirectFix struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Indirect bool   `json:"indirect"`
+}
+
+// TableData returns table friendly result.
+func (p *IndirectPrinter) TableData() *printer.TableData {
+	var data [][]string
+
+	for _, f := range p.Fixes {
+		from, to := "indirect", "direct"
+		if f.Indirect {
+			from, to = to, from
+		}
+
+		data = append(data, []string{f.Path, f.Version, from, to})
+	}
+
+	return &printer.TableData{
+		Header:       []string{"Module", "Version", "Marked", "Corrected"},
+		Footer:       []string{"", "", "number of modules", strconv.Itoa(len(p.Fixes))},
+		RowSeparator: "-",
+		ShowBorder:   false,
+		ShowRowLine:  false,
+		Data:         data,
+	}
+}
+
+// JSONData returns JSON friendly result.
+func (p *IndirectPrinter) JSONData() interface{} {
+	fixes := make([]JSONIndirectFix, 0, len(p.Fixes))
+
+	for _, f := range p.Fixes {
+		fixes = append(fixes, JSONIndirectFix{Path: f.Path, Version: f.Version, Indirect: f.Indirect})
+	}
+
+	return fixes
+}
@@ -1,7 +1,10 @@
 package check
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/beatlabs/gomodctl/internal"
 	"github.com/beatlabs/gomodctl/internal/printer"
@@ -10,13 +13,46 @@ import (
 
 // Updater is exported.
 type Updater interface {
-	Update(path string) (map[string]internal.CheckResult, error)
+	Update(path string, options internal.UpdateOptions) (map[string]internal.CheckResult, error)
+	Plan(path string, options internal.UpdateOptions) (map[string]internal.CheckResult, error)
+	Apply(path string, updates map[string]internal.CheckResult, options internal.UpdateOptions) error
+	Bisect(path string, updates map[string]internal.CheckResult, options internal.UpdateOptions) (internal.BisectResult, error)
+	Restore(path string) error
+	Unused(path string) ([]internal.UnusedModule, error)
+	IndirectFixes(path string) ([]internal.IndirectFix, error)
+	Severities(updates map[string]internal.CheckResult) (map[string]internal.Severity, error)
+	Pin(path string, targets []internal.Requirement, options internal.UpdateOptions) (map[string]internal.CheckResult, error)
 }
 
 // Options is exported.
 type Options struct {
-	Path string
-	JSON bool
+	Path        string
+	JSON        bool
+	Format      string
+	Exclude     []string
+	DirectOnly  bool
+	Unused      bool
+	Pre         bool
+	Interactive bool
+	Backup      bool
+	Restore     bool
+	DryRun      bool
+	Verify      bool
+	Bisect      bool
+	GoVersion   string
+	MaxUpdates  int
+	Prioritize  string
+	// Targets are module@version arguments and --target values, only these modules are set to the versions.
+	Targets        []string
+	AllowDowngrade bool
+	// FixIndirect only corrects // indirect comments of go.mod, no version is changed.
+	FixIndirect bool
+
+	targets    []internal.Requirement
+	priority   internal.Priority
+	severities map[string]internal.Severity
+	// unused are the modules of --unused dropped from go.mod, see printUnused.
+	unused []internal.UnusedModule
 }
 
 // NewCmdUpdate returns an instance of Update command.
@@ -24,40 +60,368 @@ func NewCmdUpdate(updater Updater) *cobra.Command {
 	o := Options{}
 
 	cmd := &cobra.Command{
-		Use:   "update",
+		Use:   "update [module@version...]",
 		Short: "update project dependencies",
-		Long:  `update project dependencies to minor versions`,
+		Long:  `update project dependencies to minor versions, or only the given modules to the given versions`,
 		Args: func(cmd *cobra.Command, args []string) error {
-			return nil
+			o.Fill(cmd)
+			o.Targets = append(o.Targets, args...)
+			return o.validate()
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			o.Execute(updater)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return o.Execute(updater)
 		},
 	}
 
+	cmd.Flags().StringArray("exclude", nil, "glob pattern of module paths to ignore, can be repeated")
+	cmd.Flags().Bool("direct-only", false, "leave out modules required with an // indirect comment")
+	cmd.Flags().Bool("pre", false, "update to prereleases too, by default they are used only if there is no stable version")
+	cmd.Flags().Bool("unused", false, "also remove modules from go.mod which go mod tidy would remove")
+	cmd.Flags().BoolP("interactive", "i", false, "select modules to update interactively, disabled with --format json")
+	cmd.Flags().Bool("backup", false, "copy go.mod and go.sum to go.mod.bak and go.sum.bak before updating")
+	cmd.Flags().Bool("restore", false, "restore go.mod and go.sum from the backup created by --backup")
+	cmd.Flags().Bool("dry-run", false, "print planned go.mod edits without changing any file")
+	cmd.Flags().Bool("verify", false, "run go mod tidy and go build ./... after updating, restore go.mod and go.sum if they fail")
+	cmd.Flags().Bool("bisect", false, "leave out the updates which break go mod tidy or go build ./..., found by binary search")
+	cmd.Flags().String("go-version", "", "skip versions whose go.mod requires a newer Go, e.g. 1.21 (default is the version of the go command)")
+	cmd.Flags().Int("max-updates", 0, "apply at most the given number of updates, the rest are reported as deferred (default is no limit)")
+	cmd.Flags().String("prioritize", string(internal.PriorityType), "order of updates applied with --max-updates and printed: security, staleness, type or alpha")
+	cmd.Flags().StringArray("target", nil, "module@version to set the module to, nothing else is updated, can be repeated")
+	cmd.Flags().Bool("allow-downgrade", false, "allow module@version targets lower than the current versions")
+	cmd.Flags().Bool("fix-indirect", false, "only correct // indirect comments in go.mod like go mod tidy would, versions aren't changed")
+
 	return cmd
 }
 
 // Fill fills flags into options.
 func (o *Options) Fill(cmd *cobra.Command) {
-	o.JSON, _ = cmd.Flags().GetBool("json")
+	o.Format = printer.FormatOf(cmd.Flags())
+	o.JSON = o.Format == printer.FormatJSON
 	o.Path, _ = cmd.Flags().GetString("path")
+	o.Exclude, _ = cmd.Flags().GetStringArray("exclude")
+	o.DirectOnly, _ = cmd.Flags().GetBool("direct-only")
+	o.Unused, _ = cmd.Flags().GetBool("unused")
+	o.Pre, _ = cmd.Flags().GetBool("pre")
+	o.Interactive, _ = cmd.Flags().GetBool("interactive")
+	o.Backup, _ = cmd.Flags().GetBool("backup")
+	o.Restore, _ = cmd.Flags().GetBool("restore")
+	o.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	o.Verify, _ = cmd.Flags().GetBool("verify")
+	o.Bisect, _ = cmd.Flags().GetBool("bisect")
+	o.GoVersion, _ = cmd.Flags().GetString("go-version")
+	o.MaxUpdates, _ = cmd.Flags().GetInt("max-updates")
+	o.Prioritize, _ = cmd.Flags().GetString("prioritize")
+	o.Targets, _ = cmd.Flags().GetStringArray("target")
+	o.AllowDowngrade, _ = cmd.Flags().GetBool("allow-downgrade")
+	o.FixIndirect, _ = cmd.Flags().GetBool("fix-indirect")
+}
+
+func (o *Options) validate() error {
+	if o.Path == internal.StdinPath && !o.DryRun {
+		return errors.New("go.mod read from stdin can't be updated, use --dry-run")
+	}
+
+	if o.Bisect && (o.DryRun || o.Restore) {
+		return errors.New("--bisect can't be combined with --dry-run or --restore")
+	}
+
+	if o.MaxUpdates < 0 {
+		return errors.New("--max-updates can't be negative")
+	}
+
+	var err error
+	if o.priority, err = internal.ParsePriority(o.Prioritize); err != nil {
+		return fmt.Errorf("--prioritize: %w", err)
+	}
+
+	if len(o.Targets) > 0 && (o.Interactive || o.Bisect || o.Unused || o.Restore || o.MaxUpdates > 0) {
+		return errors.New("module@version targets can't be used with --interactive, --bisect, --unused, --restore or --max-updates")
+	}
+
+	if o.FixIndirect && (len(o.Targets) > 0 || o.Interactive || o.Bisect || o.Unused || o.Restore || o.MaxUpdates > 0) {
+		return errors.New("--fix-indirect can't be used with module@version targets, --interactive, --bisect, --unused, --restore or --max-updates")
+	}
+
+	if o.AllowDowngrade && len(o.Targets) == 0 {
+		return errors.New("--allow-downgrade requires module@version targets")
+	}
+
+	o.targets = nil
+	for _, target := range o.Targets {
+		i := strings.LastIndex(target, "@")
+		if i <= 0 || i == len(target)-1 {
+			return fmt.Errorf("invalid target %q, must be module@version", target)
+		}
+
+		o.targets = append(o.targets, internal.Requirement{Path: target[:i], Version: target[i+1:]})
+	}
+
+	return nil
 }
 
-// Execute is exported.
-func (o *Options) Execute(updater Updater) {
-	checkResults, err := updater.Update(o.Path)
+// Execute is exported, errors are returned as *internal.ExitError so update fails in CI.
+func (o *Options) Execute(updater Updater) error {
+	if o.Restore {
+		err := updater.Restore(o.Path)
+		if err != nil {
+			return &internal.ExitError{Code: 1, Err: err}
+		}
+
+		fmt.Println("go.mod and go.sum restored from backup")
+		return nil
+	}
+
+	options := internal.UpdateOptions{
+		CheckOptions: internal.CheckOptions{
+			Exclude:    o.Exclude,
+			DirectOnly: o.DirectOnly,
+			Prerelease: o.Pre,
+			GoVersion:  o.GoVersion,
+			Times:      o.priority == internal.PriorityStaleness,
+		},
+		Backup:         o.Backup,
+		DryRun:         o.DryRun,
+		Verify:         o.Verify,
+		AllowDowngrade: o.AllowDowngrade,
+	}
+
+	if o.FixIndirect {
+		return o.fixIndirect(updater, options)
+	}
+
+	if o.Unused {
+		unused, err := updater.Unused(o.Path)
+		if err != nil {
+			return &internal.ExitError{Code: 1, Err: err}
+		}
+
+		for _, m := range unused {
+			options.Remove = append(options.Remove, m.Path)
+		}
+
+		o.unused = unused
+	}
+
+	if o.Bisect {
+		return o.bisect(updater, options)
+	}
+
+	if len(o.targets) > 0 {
+		return o.pin(updater, options)
+	}
+
+	var checkResults, deferred map[string]internal.CheckResult
+	var err error
+
+	if (o.Interactive && !o.JSON) || o.MaxUpdates > 0 {
+		checkResults, deferred, err = o.applyPlan(updater, options)
+	} else {
+		checkResults, err = updater.Update(o.Path, options)
+	}
 	if err != nil {
-		fmt.Println(err)
-		return
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	o.printUnused()
+
+	order, err := o.prioritize(updater, checkResults)
+	if err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	if o.DryRun {
+		pp := NewPlanPrinter(checkResults)
+		pp.Order = order
+		printer.Print(pp, o.Format)
+		if !o.JSON {
+			printDeferred(deferred)
+		}
+		return nil
 	}
 
 	fmt.Println("Your dependencies updated to latest minor and go.mod.backup created")
+	if o.Verify {
+		fmt.Println("go mod tidy and go build ./... succeeded")
+	}
 
 	rp := NewResultPrinter(checkResults)
-	if o.JSON {
-		printer.PrintJSON(rp)
+	rp.Order = order
+	printer.Print(rp, o.Format)
+
+	if !o.JSON {
+		printDeferred(deferred)
+	}
+
+	return nil
+}
+
+// printUnused lists the modules of --unused once go.mod was written without them, or would be in dry run.
+func (o *Options) printUnused() {
+	if o.JSON || len(o.unused) == 0 {
+		return
+	}
+
+	if o.DryRun {
+		fmt.Println("Unused modules to be removed from go.mod:")
 	} else {
-		printer.PrintTable(rp)
+		fmt.Println("Unused modules removed from go.mod:")
+	}
+
+	for _, m := range o.unused {
+		fmt.Printf("  %s %s\n", m.Path, m.Version)
+	}
+}
+
+// fixIndirect corrects // indirect comments of go.mod, --dry-run only prints them.
+func (o *Options) fixIndirect(updater Updater, options internal.UpdateOptions) error {
+	fixes, err := updater.IndirectFixes(o.Path)
+	if err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
 	}
+
+	options.Indirect = make(map[string]bool, len(fixes))
+	for _, f := range fixes {
+		options.Indirect[f.Path] = f.Indirect
+	}
+
+	if err := updater.Apply(o.Path, nil, options); err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	if !o.JSON {
+		switch {
+		case len(fixes) == 0:
+			fmt.Println("// indirect comments of go.mod are up to date")
+			return nil
+		case o.DryRun:
+			fmt.Println("// indirect comments to be corrected in go.mod:")
+		default:
+			fmt.Println("// indirect comments corrected in go.mod:")
+		}
+	}
+
+	printer.Print(NewIndirectPrinter(fixes), o.Format)
+
+	return nil
+}
+
+// pin sets only the target modules to their versions.
+func (o *Options) pin(updater Updater, options internal.UpdateOptions) error {
+	pins, err := updater.Pin(o.Path, o.targets, options)
+	if err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	o.printUnused()
+
+	if o.DryRun {
+		pp := NewPlanPrinter(pins)
+		pp.Downgrades = o.AllowDowngrade
+		printer.Print(pp, o.Format)
+		return nil
+	}
+
+	if !o.JSON {
+		fmt.Println("Your dependencies set to the given versions, go mod tidy run and go.mod.backup created")
+	}
+
+	rp := NewResultPrinter(pins)
+	rp.Downgrades = o.AllowDowngrade
+	printer.Print(rp, o.Format)
+
+	return nil
+}
+
+// applyPlan applies only the updates selected by the user and not deferred by --max-updates, deferred ones are returned too.
+func (o *Options) applyPlan(updater Updater, options internal.UpdateOptions) (map[string]internal.CheckResult, map[string]internal.CheckResult, error) {
+	selection, deferred, err := o.plan(updater, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = updater.Apply(o.Path, selection, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return selection, deferred, nil
+}
+
+// plan returns update candidates, only the ones selected by the user in interactive mode,
+// and the ones deferred by --max-updates separately.
+func (o *Options) plan(updater Updater, options internal.UpdateOptions) (map[string]internal.CheckResult, map[string]internal.CheckResult, error) {
+	candidates, err := updater.Plan(o.Path, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if o.Interactive && !o.JSON {
+		candidates, err = selectModules(candidates)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return o.limit(updater, candidates)
+}
+
+// bisect applies updates except the ones breaking the build, update fails if there is any.
+func (o *Options) bisect(updater Updater, options internal.UpdateOptions) error {
+	updates, deferred, err := o.plan(updater, options)
+	if err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	result, err := updater.Bisect(o.Path, updates, options)
+	if err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	o.printUnused()
+
+	if !o.JSON {
+		fmt.Println("Your dependencies updated to latest minor except the ones breaking the build and go.mod.backup created")
+	}
+
+	order, err := o.prioritize(updater, result.Applied)
+	if err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	rp := NewResultPrinter(result.Applied)
+	rp.Order = order
+	printer.Print(rp, o.Format)
+
+	if !o.JSON {
+		printDeferred(deferred)
+	}
+
+	if len(result.Culprits) == 0 {
+		return nil
+	}
+
+	return &internal.ExitError{Code: 1, Err: culpritsError(result.Culprits)}
+}
+
+// culpritsError describes updates left out by bisect together with the build failure they cause.
+func culpritsError(culprits map[string]internal.CheckResult) error {
+	names := make([]string, 0, len(culprits))
+	for name := range culprits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+
+	for i, name := range names {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+
+		result := culprits[name]
+		fmt.Fprintf(&b, "%s %s -> %s breaks the build and was not updated: %v", name, result.LocalVersion.Original(), result.LatestVersion.Original(), result.Error)
+	}
+
+	return errors.New(b.String())
 }
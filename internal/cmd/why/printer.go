@@ -0,0 +1,70 @@
+package why
+
+import (
+	"strconv"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+)
+
+// JSONRequirement is the JSON representation of a module in the requirement chain.
+type JSONRequirement struct {
+	Path    string  `json:"path"`
+	Version *string `json:"version"`
+}
+
+// JSONResult is the JSON representation of the why result.
+type JSONResult struct {
+	Module string            `json:"module"`
+	Chain  []JSONRequirement `json:"chain"`
+}
+
+// ResultPrinter implements Printer interface for Why command.
+type ResultPrinter struct {
+	module string
+	chain  []internal.Requirement
+}
+
+// NewResultPrinter creates a new instance of ResultPrinter.
+func NewResultPrinter(module string, chain []internal.Requirement) *ResultPrinter {
+	return &ResultPrinter{module: module, chain: chain}
+}
+
+// TableData returns table friendly result, one row per module from the main module to the explained one.
+func (p *ResultPrinter) TableData() *printer.TableData {
+	data := make([][]string, 0, len(p.chain))
+
+	for i, r := range p.chain {
+		version := r.Version
+		if i == 0 {
+			version = "(main module)"
+		}
+
+		data = append(data, []string{strconv.Itoa(i), r.Path, version})
+	}
+
+	return &printer.TableData{
+		Header:       []string{"Depth", "Module", "Version"},
+		RowSeparator: "-",
+		ShowBorder:   false,
+		ShowRowLine:  false,
+		Data:         data,
+	}
+}
+
+// JSONData returns JSON friendly result.
+func (p *ResultPrinter) JSONData() interface{} {
+	chain := make([]JSONRequirement, 0, len(p.chain))
+
+	for _, r := range p.chain {
+		jr := JSONRequirement{Path: r.Path}
+		if r.Version != "" {
+			version := r.Version
+			jr.Version = &version
+		}
+
+		chain = append(chain, jr)
+	}
+
+	return JSONResult{Module: p.module, Chain: chain}
+}
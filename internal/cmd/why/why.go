@@ -0,0 +1,68 @@
+package why
+
+import (
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/printer"
+	"github.com/spf13/cobra"
+)
+
+// Explainer is exported.
+type Explainer interface {
+	Why(path, target string) ([]internal.Requirement, error)
+}
+
+// Options is exported.
+type Options struct {
+	Module string
+	Path   string
+	JSON   bool
+	Format string
+}
+
+// NewCmdWhy returns an instance of Why command.
+func NewCmdWhy(explainer Explainer) *cobra.Command {
+	o := Options{}
+
+	cmd := &cobra.Command{
+		Use:   "why <module[@version]>",
+		Short: "explain why a module is required",
+		Long: `print the shortest requirement chain from the main module to the given module in the module graph,
+it tells which direct dependency pulls in a transitive one.`,
+		Example: `  gomodctl why gopkg.in/yaml.v3`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return err
+			}
+
+			o.Module = args[0]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			o.Fill(cmd)
+			return o.Execute(explainer)
+		},
+	}
+
+	return cmd
+}
+
+// Fill fills flags into options.
+func (o *Options) Fill(cmd *cobra.Command) {
+	o.Format = printer.FormatOf(cmd.Flags())
+	o.JSON = o.Format == printer.FormatJSON
+	o.Path, _ = cmd.Flags().GetString("path")
+}
+
+// Execute is exported.
+func (o *Options) Execute(explainer Explainer) error {
+	chain, err := explainer.Why(o.Path, o.Module)
+	if err != nil {
+		return &internal.ExitError{Code: 1, Err: err}
+	}
+
+	printer.Print(NewResultPrinter(o.Module, chain), o.Format)
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDays parses a duration which may be given in days, e.g. 90d, other units of time.ParseDuration are accepted too.
+func ParseDays(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	return d, nil
+}
+
+// ParseSince parses a point in time given as a duration before now, e.g. 7d, or as a date, e.g. 2021-06-01 or RFC 3339.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	d, err := ParseDays(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid duration or date %q", s)
+	}
+
+	return now.Add(-d), nil
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDays(t *testing.T) {
+	d, err := ParseDays("90d")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*24*time.Hour, d)
+
+	d, err = ParseDays("36h")
+	assert.NoError(t, err)
+	assert.Equal(t, 36*time.Hour, d)
+
+	_, err = ParseDays("d")
+	assert.Error(t, err)
+
+	_, err = ParseDays("-1d")
+	assert.Error(t, err)
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2021, 6, 8, 12, 0, 0, 0, time.UTC)
+
+	since, err := ParseSince("7d", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), since)
+
+	since, err = ParseSince("2021-06-01", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), since)
+
+	since, err = ParseSince("2021-06-01T10:00:00+02:00", now)
+	assert.NoError(t, err)
+	assert.True(t, since.Equal(time.Date(2021, 6, 1, 8, 0, 0, 0, time.UTC)))
+
+	_, err = ParseSince("last week", now)
+	assert.Error(t, err)
+}
+
+func TestCheckResult_ReleasedSince(t *testing.T) {
+	since := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, CheckResult{LatestTime: since.Add(time.Hour)}.ReleasedSince(since))
+	assert.False(t, CheckResult{LatestTime: since.Add(-time.Hour)}.ReleasedSince(since))
+	assert.False(t, CheckResult{}.ReleasedSince(since))
+}
+
+func TestCheckResult_Stale(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	result := CheckResult{LocalTime: now.Add(-100 * 24 * time.Hour)}
+
+	assert.True(t, result.Stale(90*24*time.Hour, now))
+	assert.False(t, result.Stale(120*24*time.Hour, now))
+	assert.False(t, result.Stale(0, now))
+	assert.False(t, CheckResult{}.Stale(time.Hour, now))
+}
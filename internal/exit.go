@@ -0,0 +1,24 @@
+package internal
+
+import "fmt"
+
+// ExitError is returned by commands that need to exit with a specific status.
+// Err is printed before exiting if it is not nil.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error is exported.
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
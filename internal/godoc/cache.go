@@ -0,0 +1,111 @@
+package godoc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/beatlabs/gomodctl/internal/httpclient"
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"github.com/beatlabs/gomodctl/internal/module"
+	"github.com/spf13/viper"
+)
+
+// DefaultCacheTTL is used when registry_cache_ttl isn't configured, package documentation changes less often than versions.
+const DefaultCacheTTL = 24 * time.Hour
+
+// responseCache stores successful registry responses on disk, keyed by the request URL, which contains the registry and the query.
+type responseCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// responseEntry is the content of a cache file, a stale entry is revalidated by a conditional request with its validators.
+type responseEntry struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	URL       string    `json:"url"`
+	Body      string    `json:"body"`
+	httpclient.Validators
+}
+
+// newResponseCache creates the cache configured by no_cache and registry_cache_ttl, nil means caching is disabled.
+// It lives next to the version cache, see module.CacheDir.
+func newResponseCache() *responseCache {
+	if viper.GetBool("no_cache") {
+		return nil
+	}
+
+	dir, err := module.CacheDir()
+	if err != nil {
+		return nil
+	}
+
+	ttl := viper.GetDuration("registry_cache_ttl")
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &responseCache{dir: filepath.Join(dir, "registry"), ttl: ttl}
+}
+
+func (c *responseCache) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get returns the cached response, fresh is false if it is older than the TTL.
+func (c *responseCache) get(key string) (entry responseEntry, fresh bool, ok bool) {
+	content, err := ioutil.ReadFile(c.file(key))
+	if err != nil {
+		logger.Debugf("registry cache miss for %s", key)
+		return responseEntry{}, false, false
+	}
+
+	if err := json.Unmarshal(content, &entry); err != nil || entry.URL != key {
+		logger.Debugf("registry cache miss for %s, entry is invalid", key)
+		return responseEntry{}, false, false
+	}
+
+	fresh = time.Since(entry.FetchedAt) <= c.ttl
+	if fresh {
+		logger.Debugf("registry cache hit for %s", key)
+	}
+
+	return entry, fresh, true
+}
+
+// set writes the response and its validators into the cache.
+func (c *responseCache) set(key string, body []byte, validators httpclient.Validators) error {
+	content, err := json.Marshal(responseEntry{FetchedAt: time.Now(), URL: key, Body: string(body), Validators: validators})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	file := c.file(key)
+
+	tempFile, err := ioutil.TempFile(c.dir, filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), file)
+}
@@ -0,0 +1,129 @@
+package godoc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/gomodctl/internal/httpclient"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCache(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cache := &responseCache{dir: tempDir, ttl: time.Hour}
+
+	_, _, ok := cache.get("https://api.godoc.org/search?q=mock")
+	assert.False(t, ok)
+
+	validators := httpclient.Validators{ETag: `"v1"`}
+	assert.NoError(t, cache.set("https://api.godoc.org/search?q=mock", []byte(`{"results": []}`), validators))
+
+	entry, fresh, ok := cache.get("https://api.godoc.org/search?q=mock")
+	assert.True(t, ok)
+	assert.True(t, fresh)
+	assert.Equal(t, `{"results": []}`, entry.Body)
+	assert.Equal(t, validators, entry.Validators)
+
+	_, _, ok = cache.get("https://index.example.com/search?q=mock")
+	assert.False(t, ok, "responses of other registries aren't shared")
+
+	cache.ttl = -time.Second
+	entry, fresh, ok = cache.get("https://api.godoc.org/search?q=mock")
+	assert.True(t, ok)
+	assert.False(t, fresh)
+	assert.Equal(t, `{"results": []}`, entry.Body)
+}
+
+func TestClient_SearchCached(t *testing.T) {
+	cacheHome, err := ioutil.TempDir("", "test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheHome)
+
+	// os.UserCacheDir prefers XDG_CACHE_HOME on Linux, HOME is used on other systems.
+	for _, name := range []string{"XDG_CACHE_HOME", "HOME"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Setenv(name, cacheHome)
+	}
+
+	searched := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searched++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results": [{"name": "mock", "path": "github.com/stretchr/testify/mock"}]}`))
+	}))
+	defer server.Close()
+
+	viper.Set("registry", server.URL)
+	viper.Set("retries", 0)
+	defer viper.Set("registry", nil)
+	defer viper.Set("retries", nil)
+	defer viper.Set("registry_cache_ttl", nil)
+
+	client := NewClient(context.TODO())
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Search("mock")
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+	}
+	assert.Equal(t, 1, searched, "the second search is cached")
+
+	viper.Set("registry_cache_ttl", time.Nanosecond)
+	server.Close()
+
+	response, err := client.Search("mock")
+	assert.NoError(t, err, "a stale response is used if the registry can't be reached")
+	assert.Len(t, response, 1)
+}
+
+func TestClient_SearchRevalidated(t *testing.T) {
+	cacheHome, err := ioutil.TempDir("", "test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheHome)
+
+	for _, name := range []string{"XDG_CACHE_HOME", "HOME"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Setenv(name, cacheHome)
+	}
+
+	searched, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searched++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"results": [{"name": "mock", "path": "github.com/stretchr/testify/mock"}]}`))
+	}))
+	defer server.Close()
+
+	viper.Set("registry", server.URL)
+	viper.Set("retries", 0)
+	viper.Set("registry_cache_ttl", time.Nanosecond)
+	defer viper.Set("registry", nil)
+	defer viper.Set("retries", nil)
+	defer viper.Set("registry_cache_ttl", nil)
+
+	client := NewClient(context.TODO())
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Search("mock")
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+	}
+	assert.Equal(t, 2, searched)
+	assert.Equal(t, 1, notModified, "the stale response is revalidated")
+}
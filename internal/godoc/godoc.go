@@ -2,9 +2,19 @@ package godoc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/httpclient"
+	"github.com/beatlabs/gomodctl/internal/logger"
 	"github.com/go-resty/resty/v2"
 )
 
@@ -31,15 +41,71 @@ type importers struct {
 	} `json:"results"`
 }
 
+// DefaultAPIURL is the registry API used when --registry isn't set.
+const DefaultAPIURL = "https://api.godoc.org"
+
+// probeTimeout limits the health probe of every registry when fallback registries are configured.
+const probeTimeout = 3 * time.Second
+
 // Client is exported.
 type Client struct {
 	restClient *resty.Client
 	ctx        context.Context
+
+	// registry is the first responsive one of the configured registries, they are probed once by apiURL.
+	probeOnce   sync.Once
+	registry    string
+	registryErr error
 }
 
 // NewClient is exported.
 func NewClient(ctx context.Context) *Client {
-	return &Client{restClient: resty.New(), ctx: ctx}
+	return &Client{restClient: httpclient.New(), ctx: ctx}
+}
+
+// apiURL returns URL of the registry API, requests to the primary registry are authenticated, see httpclient.
+// If fallback registries are configured, the first one answering a health probe serves every query of the client.
+func (c *Client) apiURL() (string, error) {
+	registries := httpclient.Registries()
+
+	switch len(registries) {
+	case 0:
+		return DefaultAPIURL, nil
+	case 1:
+		return registries[0], nil
+	}
+
+	c.probeOnce.Do(func() {
+		c.registry, c.registryErr = c.probe(registries)
+	})
+
+	return c.registry, c.registryErr
+}
+
+// probe returns the first of the registries answering a request to its URL without a server error.
+func (c *Client) probe(registries []string) (string, error) {
+	for _, registry := range registries {
+		ctx, cancel := context.WithTimeout(c.ctx, probeTimeout)
+		resp, err := c.restClient.R().SetContext(ctx).Head(registry)
+		cancel()
+
+		if err == nil && resp.StatusCode() < http.StatusInternalServerError {
+			logger.Infof("registry %s answered", registry)
+			return registry, nil
+		}
+
+		if c.ctx.Err() != nil {
+			return "", c.ctx.Err()
+		}
+
+		if err == nil {
+			err = errors.New(resp.Status())
+		}
+
+		logger.Infof("registry %s isn't available: %v", registry, err)
+	}
+
+	return "", fmt.Errorf("none of the registries is available: %s", strings.Join(registries, ", "))
 }
 
 // Search is exported.
@@ -48,17 +114,14 @@ func (c *Client) Search(term string) ([]internal.SearchResult, error) {
 		return nil, errors.New("empty term")
 	}
 
-	resp := &response{}
+	apiURL, err := c.apiURL()
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := c.restClient.R().
-		SetContext(c.ctx).
-		SetQueryParams(map[string]string{
-			"q": term,
-		}).
-		SetHeader("Accept", "application/json").
-		SetResult(resp).
-		Get("https://api.godoc.org/search")
+	resp := &response{}
 
+	err = c.getJSON(apiURL+"/search?"+url.Values{"q": {term}}.Encode(), resp)
 	if err != nil {
 		return nil, err
 	}
@@ -85,16 +148,12 @@ func (c *Client) Info(path string) (string, error) {
 		return "", errors.New("path is empty")
 	}
 
-	resp, err := c.restClient.R().
-		SetContext(c.ctx).
-		SetHeader("Accept", "text/plain").
-		Get("https://godoc.org/" + path)
-
+	body, _, err := c.get("https://godoc.org/"+path, "text/plain")
 	if err != nil {
 		return "", err
 	}
 
-	response := resp.String()
+	response := string(body)
 
 	if response == "NOT FOUND" {
 		return "", errors.New("not found")
@@ -109,12 +168,13 @@ func (c *Client) Imports(path string) ([]string, error) {
 		return nil, errors.New("path is empty")
 	}
 
+	apiURL, err := c.apiURL()
+	if err != nil {
+		return nil, err
+	}
+
 	imps := &imports{}
-	_, err := c.restClient.R().
-		SetContext(c.ctx).
-		SetHeader("Accept", "application/json").
-		SetResult(imps).
-		Get("https://api.godoc.org/imports/" + path)
+	err = c.getJSON(apiURL+"/imports/"+path, imps)
 
 	if err != nil {
 		return nil, err
@@ -133,12 +193,13 @@ func (c *Client) Importers(path string) ([]string, error) {
 		return nil, errors.New("path is empty")
 	}
 
+	apiURL, err := c.apiURL()
+	if err != nil {
+		return nil, err
+	}
+
 	imps := &importers{}
-	_, err := c.restClient.R().
-		SetContext(c.ctx).
-		SetHeader("Accept", "application/json").
-		SetResult(imps).
-		Get("https://api.godoc.org/importers/" + path)
+	err = c.getJSON(apiURL+"/importers/"+path, imps)
 
 	if err != nil {
 		return nil, err
@@ -150,3 +211,149 @@ func (c *Client) Importers(path string) ([]string, error) {
 	}
 	return paths, nil
 }
+
+// ImporterCount returns number of known importers of a package.
+// It returns an error if the registry doesn't expose importers of the package.
+func (c *Client) ImporterCount(path string) (int, error) {
+	if path == "" {
+		return 0, errors.New("path is empty")
+	}
+
+	apiURL, err := c.apiURL()
+	if err != nil {
+		return 0, err
+	}
+
+	body, status, err := c.get(apiURL+"/importers/"+path, "application/json")
+	if err != nil {
+		return 0, err
+	}
+
+	if !isSuccess(status) {
+		return 0, fmt.Errorf("importers of %s aren't available: %d %s", path, status, http.StatusText(status))
+	}
+
+	imps := &importers{}
+	if err := json.Unmarshal(body, imps); err != nil {
+		return 0, err
+	}
+
+	return len(imps.Results), nil
+}
+
+// getJSON decodes a successful JSON response into result, other responses leave it empty.
+func (c *Client) getJSON(requestURL string, result interface{}) error {
+	body, status, err := c.get(requestURL, "application/json")
+	if err != nil || !isSuccess(status) {
+		return err
+	}
+
+	return json.Unmarshal(body, result)
+}
+
+// get returns body and status of the response, successful responses are cached, see responseCache.
+// A fresh cached response is returned without a request. A stale one is revalidated by a conditional request,
+// it is returned if the registry answers 304 Not Modified or can't be reached.
+func (c *Client) get(requestURL, accept string) ([]byte, int, error) {
+	cache := newResponseCache()
+
+	var cached *responseEntry
+	if cache != nil {
+		entry, fresh, ok := cache.get(requestURL)
+		if fresh {
+			return []byte(entry.Body), http.StatusOK, nil
+		}
+
+		if ok {
+			cached = &entry
+		}
+	}
+
+	request := c.restClient.R().
+		SetContext(c.ctx).
+		SetHeader("Accept", accept)
+	if cached != nil {
+		cached.Validators.Apply(request)
+	}
+
+	resp, err := request.Get(requestURL)
+	if err != nil {
+		if cached != nil && c.ctx.Err() == nil {
+			logger.Warnf("using stale cached response of %s: %v", requestURL, err)
+			return []byte(cached.Body), http.StatusOK, nil
+		}
+
+		return nil, 0, err
+	}
+
+	if cached != nil && httpclient.NotModified(resp) {
+		logger.Debugf("registry response of %s not modified", requestURL)
+
+		if err := cache.set(requestURL, []byte(cached.Body), cached.Validators); err != nil {
+			logger.Debugf("registry cache of %s not written: %v", requestURL, err)
+		}
+
+		return []byte(cached.Body), http.StatusOK, nil
+	}
+
+	if cache != nil && resp.IsSuccess() {
+		if err := cache.set(requestURL, resp.Body(), httpclient.ValidatorsOf(resp)); err != nil {
+			logger.Debugf("registry cache of %s not written: %v", requestURL, err)
+		}
+	}
+
+	return resp.Body(), resp.StatusCode(), nil
+}
+
+func isSuccess(status int) bool {
+	return status >= http.StatusOK && status < http.StatusMultipleChoices
+}
+
+// SubPackages returns packages in subdirectories of a package known by the registry.
+func (c *Client) SubPackages(path string) ([]string, error) {
+	if path == "" {
+		return nil, errors.New("path is empty")
+	}
+
+	results, err := c.Search(path)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, result := range results {
+		if strings.HasPrefix(result.Path, path+"/") {
+			paths = append(paths, result.Path)
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// Readme fetches README.md of the repository of a package, only GitHub repositories are supported.
+func (c *Client) Readme(path string) (string, error) {
+	return readme(c.ctx, c.restClient, path)
+}
+
+// readme fetches README.md of the GitHub repository of a package, it doesn't depend on the registry.
+func readme(ctx context.Context, restClient *resty.Client, path string) (string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 || parts[0] != "github.com" {
+		return "", fmt.Errorf("readme of %s isn't available, only GitHub repositories are supported", path)
+	}
+
+	resp, err := restClient.R().
+		SetContext(ctx).
+		Get(fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/HEAD/README.md", parts[1], parts[2]))
+	if err != nil {
+		return "", err
+	}
+
+	if !resp.IsSuccess() {
+		return "", fmt.Errorf("readme of %s isn't available: %s", path, resp.Status())
+	}
+
+	return resp.String(), nil
+}
@@ -3,8 +3,11 @@ package godoc
 import (
 	"context"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -56,3 +59,59 @@ func TestClient_Importers(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, response)
 }
+
+func TestClient_ImporterCount(t *testing.T) {
+	client := NewClient(context.TODO())
+
+	count, err := client.ImporterCount("github.com/stretchr/testify/mock")
+
+	assert.NoError(t, err)
+	assert.NotZero(t, count)
+}
+
+func TestClient_Readme(t *testing.T) {
+	client := NewClient(context.TODO())
+
+	_, err := client.Readme("golang.org/x/mod")
+
+	assert.Error(t, err)
+}
+
+func TestClient_SearchFallback(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	searched := 0
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search" {
+			searched++
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"results": [{"name": "mock", "path": "github.com/stretchr/testify/mock"}]}`))
+		}
+	}))
+	defer up.Close()
+
+	viper.Set("registry", down.URL+","+up.URL)
+	viper.Set("retries", 0)
+	viper.Set("no_cache", true)
+	defer viper.Set("registry", nil)
+	defer viper.Set("retries", nil)
+	defer viper.Set("no_cache", nil)
+
+	client := NewClient(context.TODO())
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Search("mock")
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+	}
+
+	assert.Equal(t, 2, searched, "both searches are served by the fallback registry")
+
+	up.Close()
+
+	_, err := NewClient(context.TODO()).Search("mock")
+	assert.EqualError(t, err, "none of the registries is available: "+down.URL+", "+up.URL)
+}
@@ -0,0 +1,251 @@
+package godoc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/httpclient"
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/viper"
+)
+
+// indexDocument is a static package index, e.g. generated from a company monorepo and served by any web server.
+type indexDocument struct {
+	Packages []indexPackage `json:"packages"`
+}
+
+type indexPackage struct {
+	Path     string `json:"path"`
+	Name     string `json:"name"`
+	Synopsis string `json:"synopsis,omitempty"`
+	Doc      string `json:"doc,omitempty"`
+	Stars    int    `json:"stars,omitempty"`
+	// ImportCount overrides the number of importers found in the index, e.g. if it covers only some of them.
+	ImportCount int      `json:"import_count,omitempty"`
+	Imports     []string `json:"imports,omitempty"`
+}
+
+// Index is a Source reading a static JSON index from the URL or file of the index config key.
+// The index is loaded once by the first query, importers are the packages of the index importing a package.
+type Index struct {
+	restClient *resty.Client
+	ctx        context.Context
+
+	loadOnce  sync.Once
+	packages  map[string]indexPackage
+	importers map[string][]string
+	loadErr   error
+}
+
+// NewIndex returns an Index, it is loaded on the first query.
+func NewIndex(ctx context.Context) *Index {
+	return &Index{restClient: httpclient.New(), ctx: ctx}
+}
+
+// load reads the index once, a location without http:// or https:// is a file.
+func (ix *Index) load() error {
+	ix.loadOnce.Do(func() {
+		location := viper.GetString("index")
+
+		content, err := ix.read(location)
+		if err != nil {
+			ix.loadErr = fmt.Errorf("index %s: %w", location, err)
+			return
+		}
+
+		document := indexDocument{}
+		if err := json.Unmarshal(content, &document); err != nil {
+			ix.loadErr = fmt.Errorf("index %s: %w", location, err)
+			return
+		}
+
+		ix.packages = make(map[string]indexPackage, len(document.Packages))
+		ix.importers = make(map[string][]string)
+
+		for _, p := range document.Packages {
+			if p.Path == "" {
+				ix.loadErr = fmt.Errorf("index %s: package without path", location)
+				return
+			}
+
+			ix.packages[p.Path] = p
+
+			for _, imp := range p.Imports {
+				ix.importers[imp] = append(ix.importers[imp], p.Path)
+			}
+		}
+
+		for _, importers := range ix.importers {
+			sort.Strings(importers)
+		}
+	})
+
+	return ix.loadErr
+}
+
+func (ix *Index) read(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return ioutil.ReadFile(location)
+	}
+
+	resp, err := ix.restClient.R().
+		SetContext(ix.ctx).
+		SetHeader("Accept", "application/json").
+		Get(location)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, errors.New(resp.Status())
+	}
+
+	return resp.Body(), nil
+}
+
+// lookup returns the package of the index.
+func (ix *Index) lookup(path string) (indexPackage, error) {
+	if path == "" {
+		return indexPackage{}, errors.New("path is empty")
+	}
+
+	if err := ix.load(); err != nil {
+		return indexPackage{}, err
+	}
+
+	p, ok := ix.packages[path]
+	if !ok {
+		return indexPackage{}, errors.New("not found")
+	}
+
+	return p, nil
+}
+
+func (ix *Index) importCount(p indexPackage) int {
+	if p.ImportCount > 0 {
+		return p.ImportCount
+	}
+
+	return len(ix.importers[p.Path])
+}
+
+// Search returns packages whose path, name or synopsis contains the term, ignoring case.
+// The package with the term as its path comes first, the others are ordered by the number of importers.
+func (ix *Index) Search(term string) ([]internal.SearchResult, error) {
+	if term == "" {
+		return nil, errors.New("empty term")
+	}
+
+	if err := ix.load(); err != nil {
+		return nil, err
+	}
+
+	lower := strings.ToLower(term)
+
+	var results []internal.SearchResult
+
+	for _, p := range ix.packages {
+		if !strings.Contains(strings.ToLower(p.Path), lower) && !strings.Contains(strings.ToLower(p.Name), lower) &&
+			!strings.Contains(strings.ToLower(p.Synopsis), lower) {
+			continue
+		}
+
+		results = append(results, internal.SearchResult{
+			Name:        p.Name,
+			Path:        p.Path,
+			ImportCount: ix.importCount(p),
+			Stars:       p.Stars,
+			Synopsis:    p.Synopsis,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if (results[i].Path == term) != (results[j].Path == term) {
+			return results[i].Path == term
+		}
+
+		if results[i].ImportCount != results[j].ImportCount {
+			return results[i].ImportCount > results[j].ImportCount
+		}
+
+		return results[i].Path < results[j].Path
+	})
+
+	return results, nil
+}
+
+// Info returns documentation of a package, its synopsis if the index has no documentation.
+func (ix *Index) Info(path string) (string, error) {
+	p, err := ix.lookup(path)
+	if err != nil {
+		return "", err
+	}
+
+	if p.Doc == "" {
+		return p.Synopsis, nil
+	}
+
+	return p.Doc, nil
+}
+
+// Imports returns imports of a package.
+func (ix *Index) Imports(path string) ([]string, error) {
+	p, err := ix.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]string{}, p.Imports...), nil
+}
+
+// Importers returns packages of the index importing a package.
+func (ix *Index) Importers(path string) ([]string, error) {
+	if _, err := ix.lookup(path); err != nil {
+		return nil, err
+	}
+
+	return append([]string{}, ix.importers[path]...), nil
+}
+
+// ImporterCount returns number of packages of the index importing a package.
+func (ix *Index) ImporterCount(path string) (int, error) {
+	if _, err := ix.lookup(path); err != nil {
+		return 0, err
+	}
+
+	return len(ix.importers[path]), nil
+}
+
+// SubPackages returns packages of the index in subdirectories of a package.
+func (ix *Index) SubPackages(path string) ([]string, error) {
+	if path == "" {
+		return nil, errors.New("path is empty")
+	}
+
+	if err := ix.load(); err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for p := range ix.packages {
+		if strings.HasPrefix(p, path+"/") {
+			paths = append(paths, p)
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// Readme fetches README.md of the repository of a package, only GitHub repositories are supported.
+func (ix *Index) Readme(path string) (string, error) {
+	return readme(ix.ctx, ix.restClient, path)
+}
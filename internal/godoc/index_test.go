@@ -0,0 +1,73 @@
+package godoc
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "index")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "index.json")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"packages": [
+		{"path": "example.com/kit/log", "name": "log", "synopsis": "Package log writes structured logs.", "doc": "Package log writes structured logs."},
+		{"path": "example.com/kit/log/json", "name": "json", "synopsis": "JSON encoding of logs.", "imports": ["example.com/kit/log"]},
+		{"path": "example.com/billing", "name": "billing", "synopsis": "Invoices.", "imports": ["example.com/kit/log", "example.com/kit/log/json"]}
+	]}`), 0666))
+
+	viper.Set("index", file)
+	defer viper.Set("index", nil)
+
+	var source Source = NewRegistry(context.TODO())
+
+	results, err := source.Search("log")
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "example.com/kit/log", results[0].Path, "most imported first")
+		assert.Equal(t, 2, results[0].ImportCount)
+		assert.Equal(t, "example.com/kit/log/json", results[1].Path)
+	}
+
+	results, err = source.Search("invoices")
+	assert.NoError(t, err)
+	assert.Len(t, results, 1, "synopsis matches ignoring case")
+
+	doc, err := source.Info("example.com/kit/log/json")
+	assert.NoError(t, err)
+	assert.Equal(t, "JSON encoding of logs.", doc, "synopsis without documentation")
+
+	_, err = source.Info("example.com/unknown")
+	assert.EqualError(t, err, "not found")
+
+	importers, err := source.Importers("example.com/kit/log")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"example.com/billing", "example.com/kit/log/json"}, importers)
+
+	count, err := source.ImporterCount("example.com/billing")
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+
+	imports, err := source.Imports("example.com/billing")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"example.com/kit/log", "example.com/kit/log/json"}, imports)
+
+	subPackages, err := source.SubPackages("example.com/kit")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"example.com/kit/log", "example.com/kit/log/json"}, subPackages)
+}
+
+func TestIndex_Missing(t *testing.T) {
+	viper.Set("index", filepath.Join(os.TempDir(), "missing-index.json"))
+	defer viper.Set("index", nil)
+
+	_, err := NewIndex(context.TODO()).Search("log")
+	assert.Error(t, err)
+}
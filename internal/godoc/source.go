@@ -0,0 +1,80 @@
+package godoc
+
+import (
+	"context"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
+)
+
+// Source is a package index queried by search and info, Client queries the godoc API and Index a static index.
+type Source interface {
+	Search(term string) ([]internal.SearchResult, error)
+	Info(path string) (string, error)
+	Imports(path string) ([]string, error)
+	Importers(path string) ([]string, error)
+	ImporterCount(path string) (int, error)
+	SubPackages(path string) ([]string, error)
+	Readme(path string) (string, error)
+}
+
+var (
+	_ Source = (*Client)(nil)
+	_ Source = (*Index)(nil)
+	_ Source = (*Registry)(nil)
+)
+
+// Registry is the Source configured by the index config key, it queries Index if it is set and Client otherwise.
+// The source is chosen on every query, since commands are created before flags and the config file are read.
+type Registry struct {
+	client *Client
+	index  *Index
+}
+
+// NewRegistry returns the configured source, both of them are created with the given context.
+func NewRegistry(ctx context.Context) *Registry {
+	return &Registry{client: NewClient(ctx), index: NewIndex(ctx)}
+}
+
+func (r *Registry) source() Source {
+	if viper.GetString("index") != "" {
+		return r.index
+	}
+
+	return r.client
+}
+
+// Search is exported.
+func (r *Registry) Search(term string) ([]internal.SearchResult, error) {
+	return r.source().Search(term)
+}
+
+// Info is exported.
+func (r *Registry) Info(path string) (string, error) {
+	return r.source().Info(path)
+}
+
+// Imports fetches imports of a package.
+func (r *Registry) Imports(path string) ([]string, error) {
+	return r.source().Imports(path)
+}
+
+// Importers fetches importers of a package.
+func (r *Registry) Importers(path string) ([]string, error) {
+	return r.source().Importers(path)
+}
+
+// ImporterCount returns number of known importers of a package.
+func (r *Registry) ImporterCount(path string) (int, error) {
+	return r.source().ImporterCount(path)
+}
+
+// SubPackages returns packages in subdirectories of a package known by the source.
+func (r *Registry) SubPackages(path string) ([]string, error) {
+	return r.source().SubPackages(path)
+}
+
+// Readme fetches README.md of the repository of a package, only GitHub repositories are supported.
+func (r *Registry) Readme(path string) (string, error) {
+	return r.source().Readme(path)
+}
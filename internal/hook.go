@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// shellSafeRegexp matches values which don't need quoting in a shell command.
+var shellSafeRegexp = regexp.MustCompile(`^[A-Za-z0-9@%+=:,./_-]+$`)
+
+// Hook is a command template run for every module with an available update, e.g. to open a ticket.
+type Hook struct {
+	tmpl *template.Template
+}
+
+// HookData is passed to the hook template, values are quoted for sh if needed.
+// They are also set as MODULE_PATH, MODULE_LOCAL and MODULE_LATEST environment variables of the command.
+type HookData struct {
+	Path   string
+	Local  string
+	Latest string
+}
+
+// NewHook parses the command template, it may use {{.Path}}, {{.Local}} and {{.Latest}}.
+func NewHook(command string) (*Hook, error) {
+	tmpl, err := template.New("hook").Option("missingkey=error").Parse(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hook command: %w", err)
+	}
+
+	return &Hook{tmpl: tmpl}, nil
+}
+
+func newHookData(path string, result CheckResult) HookData {
+	data := HookData{Path: path}
+	if result.LocalVersion != nil {
+		data.Local = result.LocalVersion.Original()
+	}
+	if result.LatestVersion != nil {
+		data.Latest = result.LatestVersion.Original()
+	}
+
+	return data
+}
+
+// Command returns the hook command of the module.
+func (h *Hook) Command(path string, result CheckResult) (string, error) {
+	data := newHookData(path, result)
+	quoted := HookData{Path: shellQuote(data.Path), Local: shellQuote(data.Local), Latest: shellQuote(data.Latest)}
+
+	var b strings.Builder
+	if err := h.tmpl.Execute(&b, quoted); err != nil {
+		return "", fmt.Errorf("invalid hook command: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// Run runs the hook command of the module by sh, output of the command is written to w.
+// The command is killed once ctx is done.
+func (h *Hook) Run(ctx context.Context, path string, result CheckResult, w io.Writer) error {
+	command, err := h.Command(path, result)
+	if err != nil {
+		return err
+	}
+
+	data := newHookData(path, result)
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), "MODULE_PATH="+data.Path, "MODULE_LOCAL="+data.Local, "MODULE_LATEST="+data.Latest)
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook of %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// shellQuote quotes the value for sh unless it only contains safe characters.
+func shellQuote(value string) string {
+	if shellSafeRegexp.MatchString(value) {
+		return value
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHook(t *testing.T) {
+	result := CheckResult{LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.2.0")}
+
+	hook, err := NewHook("echo {{.Path}} {{.Local}} {{.Latest}}")
+	require.NoError(t, err)
+
+	command, err := hook.Command("example.com/a", result)
+	require.NoError(t, err)
+	assert.Equal(t, "echo example.com/a v1.0.0 v1.2.0", command)
+
+	var out bytes.Buffer
+	require.NoError(t, hook.Run(context.Background(), "example.com/a", result, &out))
+	assert.Equal(t, "example.com/a v1.0.0 v1.2.0\n", out.String())
+
+	hook, err = NewHook("exit 3")
+	require.NoError(t, err)
+	assert.EqualError(t, hook.Run(context.Background(), "example.com/a", result, &out), "hook of example.com/a: exit status 3")
+
+	_, err = NewHook("echo {{.Path")
+	assert.Error(t, err)
+
+	hook, err = NewHook("echo {{.Name}}")
+	require.NoError(t, err)
+	_, err = hook.Command("example.com/a", result)
+	assert.Error(t, err)
+}
+
+func TestHook_Quoting(t *testing.T) {
+	result := CheckResult{LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.2.0")}
+
+	hook, err := NewHook("echo {{.Path}}; echo $MODULE_PATH $MODULE_LOCAL $MODULE_LATEST")
+	require.NoError(t, err)
+
+	command, err := hook.Command("example.com/a;touch x'y", result)
+	require.NoError(t, err)
+	assert.Equal(t, `echo 'example.com/a;touch x'\''y'; echo $MODULE_PATH $MODULE_LOCAL $MODULE_LATEST`, command)
+
+	var out bytes.Buffer
+	require.NoError(t, hook.Run(context.Background(), "example.com/a;touch x'y", result, &out))
+	assert.Equal(t, "example.com/a;touch x'y\nexample.com/a;touch x'y v1.0.0 v1.2.0\n", out.String())
+}
+
+func TestHook_Context(t *testing.T) {
+	hook, err := NewHook("exec sleep 10")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	assert.Error(t, hook.Run(ctx, "example.com/a", CheckResult{}, &bytes.Buffer{}))
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second), "the hook is killed once the context is done")
+}
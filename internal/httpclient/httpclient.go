@@ -0,0 +1,72 @@
+// Package httpclient provides the HTTP client shared by outbound requests of gomodctl.
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/viper"
+)
+
+// New returns a REST client, requests to the configured registry are authenticated with the configured token.
+// Every request attempt is limited by the configured timeout and failed requests are retried, see retryTransport.
+func New() *resty.Client {
+	return resty.New().SetTransport(newRetryTransport(&authTransport{base: http.DefaultTransport}))
+}
+
+// Registry returns URL of the configured registry without trailing slash, empty if there is none.
+// If there are fallback registries it is the primary one, the first of Registries, only it gets the token.
+func Registry() string {
+	if registries := Registries(); len(registries) > 0 {
+		return registries[0]
+	}
+
+	return ""
+}
+
+// Registries returns URLs of the configured registries without trailing slashes in the order they are tried.
+// They are separated by commas, like in GOPROXY, or given as a list in the config file.
+func Registries() []string {
+	var registries []string
+
+	for _, value := range viper.GetStringSlice("registry") {
+		for _, registry := range strings.Split(value, ",") {
+			if registry = strings.TrimSuffix(strings.TrimSpace(registry), "/"); registry != "" {
+				registries = append(registries, registry)
+			}
+		}
+	}
+
+	return registries
+}
+
+// authTransport adds the bearer token to requests to the host of the configured registry.
+// Both are read on every request, since clients are created before flags are parsed.
+type authTransport struct {
+	base http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := viper.GetString("token")
+	if token == "" || req.Header.Get("Authorization") != "" || !isRegistry(req.URL) {
+		return t.base.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify the request.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return t.base.RoundTrip(req)
+}
+
+// isRegistry reports whether the URL points to the host of the configured registry.
+func isRegistry(u *url.URL) bool {
+	registry, err := url.Parse(Registry())
+	if err != nil || registry.Host == "" {
+		return false
+	}
+
+	return strings.EqualFold(u.Scheme, registry.Scheme) && strings.EqualFold(u.Host, registry.Host)
+}
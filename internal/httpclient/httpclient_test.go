@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_Authorization(t *testing.T) {
+	var authorization string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer other.Close()
+
+	viper.Set("registry", server.URL+"/")
+	viper.Set("token", "secret")
+	defer viper.Set("registry", nil)
+	defer viper.Set("token", nil)
+
+	client := New()
+
+	_, err := client.R().Get(server.URL + "/search")
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer secret", authorization)
+
+	_, err = client.R().Get(other.URL + "/search")
+	assert.NoError(t, err)
+	assert.Empty(t, authorization)
+
+	viper.Set("token", nil)
+
+	_, err = client.R().Get(server.URL + "/search")
+	assert.NoError(t, err)
+	assert.Empty(t, authorization)
+}
+
+func TestRegistries(t *testing.T) {
+	viper.Set("registry", "https://index.example.com/, https://api.godoc.org")
+	defer viper.Set("registry", nil)
+
+	assert.Equal(t, []string{"https://index.example.com", "https://api.godoc.org"}, Registries())
+	assert.Equal(t, "https://index.example.com", Registry())
+
+	viper.Set("registry", []string{"https://index.example.com", "https://api.godoc.org/"})
+	assert.Equal(t, []string{"https://index.example.com", "https://api.godoc.org"}, Registries())
+
+	viper.Set("registry", nil)
+	assert.Empty(t, Registries())
+	assert.Empty(t, Registry())
+}
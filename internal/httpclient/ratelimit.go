@@ -0,0 +1,121 @@
+package httpclient
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// maxRetryAfter is the longest Retry-After which is waited for, longer ones aren't retried.
+const maxRetryAfter = time.Minute
+
+// shared limits requests of all clients created by New.
+var shared = &limiter{now: time.Now}
+
+// limiter is a token bucket limiting requests per second to the configured rate, its burst is the rate rounded up.
+// It can also be paused, e.g. until the Retry-After of a throttled response.
+type limiter struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	tokens float64
+	last   time.Time
+	paused time.Time
+}
+
+// wait blocks until a request may be sent, it returns early with the error of a done context.
+func (l *limiter) wait(ctx context.Context) error {
+	d := l.reserve(Rate())
+	if d <= 0 {
+		return nil
+	}
+
+	return sleep(ctx, d)
+}
+
+// reserve takes a token and returns how long to wait for it, rate zero means no limit.
+func (l *limiter) reserve(rate float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	var d time.Duration
+	if l.paused.After(now) {
+		d = l.paused.Sub(now)
+	}
+
+	if rate <= 0 {
+		return d
+	}
+
+	burst := math.Ceil(rate)
+
+	if l.last.IsZero() {
+		l.tokens = burst
+	} else {
+		l.tokens = math.Min(burst, l.tokens+now.Sub(l.last).Seconds()*rate)
+	}
+	l.last = now
+
+	// tokens may go negative, so waiting requests are queued behind each other.
+	l.tokens--
+	if l.tokens < 0 {
+		if wait := time.Duration(-l.tokens / rate * float64(time.Second)); wait > d {
+			d = wait
+		}
+	}
+
+	return d
+}
+
+// pause delays all requests for the given duration.
+func (l *limiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := l.now().Add(d); until.After(l.paused) {
+		l.paused = until
+	}
+}
+
+// retryAfter returns the wait requested by the Retry-After header of a 429 response, false if there is none.
+// Both delay seconds and HTTP dates are supported.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	if d := date.Sub(now); d > 0 {
+		return d, true
+	}
+
+	return 0, true
+}
+
+// Rate returns the configured number of requests per second, zero means no limit.
+func Rate() float64 {
+	return viper.GetFloat64("rate")
+}
@@ -0,0 +1,85 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Reserve(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := &limiter{now: func() time.Time { return now }}
+
+	assert.Zero(t, l.reserve(0))
+
+	// burst of 2 requests, then one every 500ms.
+	assert.Zero(t, l.reserve(2))
+	assert.Zero(t, l.reserve(2))
+	assert.Equal(t, 500*time.Millisecond, l.reserve(2))
+	assert.Equal(t, time.Second, l.reserve(2))
+
+	now = now.Add(2 * time.Second)
+	assert.Zero(t, l.reserve(2))
+
+	l.pause(3 * time.Second)
+	assert.Equal(t, 3*time.Second, l.reserve(0))
+	assert.Equal(t, 3*time.Second, l.reserve(2))
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+
+	_, ok := retryAfter(resp, now)
+	assert.False(t, ok)
+
+	resp.Header.Set("Retry-After", "7")
+	d, ok := retryAfter(resp, now)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, d)
+
+	resp.Header.Set("Retry-After", now.Add(time.Minute).Format(http.TimeFormat))
+	d, ok = retryAfter(resp, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, d)
+
+	resp.StatusCode = http.StatusServiceUnavailable
+	_, ok = retryAfter(resp, now)
+	assert.False(t, ok)
+}
+
+func TestRetryTransport_TooManyRequests(t *testing.T) {
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if attempts == 2 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+	}))
+	defer server.Close()
+
+	transport := newRetryTransport(http.DefaultTransport)
+	transport.limiter = &limiter{now: time.Now}
+
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	// the second Retry-After is too long to wait for.
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 2, attempts)
+}
@@ -0,0 +1,187 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultTimeout is the per-request timeout used when timeout isn't configured.
+	DefaultTimeout = 30 * time.Second
+	// DefaultRetries is the number of retries used when retries isn't configured.
+	DefaultRetries = 2
+
+	// minBackoff is the wait before the first retry, it doubles with every retry up to maxBackoff.
+	minBackoff = 500 * time.Millisecond
+	maxBackoff = 10 * time.Second
+)
+
+// retryTransport times out every attempt of a request and retries failed ones with exponential backoff.
+// Network errors, 5xx and 429 responses are retried, the context of the request takes precedence over both.
+// Every attempt waits for the rate limiter, a 429 response pauses it for its Retry-After.
+type retryTransport struct {
+	base    http.RoundTripper
+	limiter *limiter
+	// sleep waits between retries, it returns early with the error of a done context.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(base http.RoundTripper) *retryTransport {
+	return &retryTransport{base: base, limiter: shared, sleep: sleep}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retries := Retries()
+	// a request body can be sent again only if it can be recreated.
+	if req.Body != nil && req.GetBody == nil {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.attempt(req, attempt)
+
+		wait := backoff(attempt)
+		if d, ok := retryAfter(resp, time.Now()); ok {
+			if d > maxRetryAfter {
+				return resp, err
+			}
+
+			// the limiter delays the retry as well as other requests.
+			t.limiter.pause(d)
+			wait = 0
+		}
+
+		if attempt >= retries || !retryable(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			// drain the body so the connection can be reused.
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if wait > 0 {
+			if err := t.sleep(req.Context(), wait); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// attempt sends the request once within the configured timeout.
+func (t *retryTransport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+
+	if timeout := Timeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+
+	r := req.Clone(ctx)
+
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		r.Body = body
+	}
+
+	logger.Debugf("http %s %s, attempt %d", r.Method, r.URL.Redacted(), attempt+1)
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		logger.Debugf("http %s %s failed: %v", r.Method, r.URL.Redacted(), err)
+		cancel()
+		return nil, err
+	}
+
+	logger.Debugf("http %s %s: %s", r.Method, r.URL.Redacted(), resp.Status)
+
+	// the timeout covers reading the body, so the context is canceled when it is closed.
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// retryable reports whether the attempt failed in a way a retry may fix.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// backoff returns the wait before the given retry.
+func backoff(attempt int) time.Duration {
+	d := minBackoff << uint(attempt)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+
+	return d
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Timeout returns the configured timeout of a single request attempt, zero means no timeout.
+func Timeout() time.Duration {
+	if !viper.IsSet("timeout") {
+		return DefaultTimeout
+	}
+
+	return viper.GetDuration("timeout")
+}
+
+// Retries returns the configured number of retries of failed requests.
+func Retries() int {
+	if !viper.IsSet("retries") {
+		return DefaultRetries
+	}
+
+	if retries := viper.GetInt("retries"); retries > 0 {
+		return retries
+	}
+
+	return 0
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
@@ -0,0 +1,41 @@
+package httpclient
+
+import (
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Validators are the ETag and Last-Modified headers of a response, cached with it to revalidate it by a conditional request.
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// ValidatorsOf returns the validators of a response, they are empty if the server sends none.
+func ValidatorsOf(resp *resty.Response) Validators {
+	return Validators{ETag: resp.Header().Get("ETag"), LastModified: resp.Header().Get("Last-Modified")}
+}
+
+// IsZero reports whether there is no validator, a conditional request isn't possible then.
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// Apply makes the request conditional, the server answers 304 Not Modified if the cached response is still current.
+func (v Validators) Apply(req *resty.Request) *resty.Request {
+	if v.ETag != "" {
+		req.SetHeader("If-None-Match", v.ETag)
+	}
+
+	if v.LastModified != "" {
+		req.SetHeader("If-Modified-Since", v.LastModified)
+	}
+
+	return req
+}
+
+// NotModified reports whether the response to a conditional request confirms the cached response.
+func NotModified(resp *resty.Response) bool {
+	return resp.StatusCode() == http.StatusNotModified
+}
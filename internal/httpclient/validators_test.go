@@ -0,0 +1,40 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidators(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Mon, 01 Feb 2021 00:00:00 GMT" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Feb 2021 00:00:00 GMT")
+		w.Write([]byte("v1.0.0\n"))
+	}))
+	defer server.Close()
+
+	client := New()
+
+	resp, err := Validators{}.Apply(client.R()).Get(server.URL)
+	assert.NoError(t, err)
+	assert.False(t, NotModified(resp))
+
+	validators := ValidatorsOf(resp)
+	assert.Equal(t, Validators{ETag: `"v1"`, LastModified: "Mon, 01 Feb 2021 00:00:00 GMT"}, validators)
+	assert.False(t, validators.IsZero())
+
+	resp, err = validators.Apply(client.R()).Get(server.URL)
+	assert.NoError(t, err)
+	assert.True(t, NotModified(resp))
+	assert.Empty(t, resp.Body())
+
+	assert.True(t, Validators{}.IsZero())
+}
@@ -3,26 +3,55 @@ package license
 //go:generate go run license_embedder.go
 
 import (
+	"archive/zip"
 	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/httpclient"
+	"github.com/beatlabs/gomodctl/internal/logger"
 	"github.com/beatlabs/gomodctl/internal/module"
 	"github.com/go-resty/resty/v2"
 	"github.com/google/licenseclassifier"
 	"github.com/mholt/archiver/v3"
+	"github.com/spf13/viper"
 )
 
-const invalidLicense = "Can't find license"
-const licenseFilename = "LICENSE"
+// licenseFilenames are prefixes of license file names, e.g. LICENSE.md, LICENSE-MIT or COPYING.
+var licenseFilenames = []string{"LICENSE", "LICENCE", "COPYING"}
+
+// snippetLength limits the matched text kept as LicenseFile.Snippet.
+const snippetLength = 80
+
+var spdxIdentifierRegexp = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\r\n*/]+)`)
+
+// ErrNotInModuleCache is returned in offline mode when the module version isn't in the local module cache.
+var ErrNotInModuleCache = errors.New("not in the module cache")
+
+// detection is a detected license with the confidence of the match and the files it was detected in,
+// see internal.LicenseResult.
+type detection struct {
+	license    string
+	confidence float64
+	files      []internal.LicenseFile
+}
+
+// licenseFile is a license file read from the module root.
+type licenseFile struct {
+	path    string
+	content []byte
+}
 
 // Checker checks for license type using license classifier.
 type Checker struct {
@@ -30,6 +59,9 @@ type Checker struct {
 	restClient    *resty.Client
 	ctx           context.Context
 	versionParser *module.ModParser
+
+	modCacheOnce sync.Once
+	modCache     string
 }
 
 // NewChecker creates a new instance of Checker.
@@ -41,7 +73,7 @@ func NewChecker(ctx context.Context) (*Checker, error) {
 
 	return &Checker{
 		classifier:    license,
-		restClient:    resty.New(),
+		restClient:    httpclient.New(),
 		ctx:           ctx,
 		versionParser: module.NewModParser(ctx),
 	}, nil
@@ -54,67 +86,277 @@ func (f *Checker) Type(moduleName, version string) (string, error) {
 		return "", err
 	}
 
-	return f.getLicense(moduleName, v)
+	if override, ok := licenseOverride(moduleName, v); ok {
+		return override, nil
+	}
+
+	detected, err := f.getLicense(moduleName, v)
+
+	return detected.license, err
+}
+
+// licenseOverride returns the license of the module set by the license_overrides config key, which maps module paths
+// or module@version to SPDX identifiers. A version specific override takes precedence, paths are compared ignoring case
+// since config keys are case insensitive.
+func licenseOverride(moduleName string, version *semver.Version) (string, bool) {
+	overrides := viper.GetStringMapString("license_overrides")
+	if len(overrides) == 0 {
+		return "", false
+	}
+
+	moduleName = strings.ToLower(moduleName)
+
+	if version != nil {
+		if license, ok := overrides[moduleName+"@"+strings.ToLower(version.Original())]; ok {
+			return license, true
+		}
+	}
+
+	license, ok := overrides[moduleName]
+
+	return license, ok
 }
 
-func (f *Checker) getLicense(moduleName string, version *semver.Version) (string, error) {
-	localModulePath := createLocalModulePath(moduleName, version)
-	if _, err := os.Stat(localModulePath); !os.IsNotExist(err) {
-		return f.getTypeFromLocalFile(localModulePath)
+// getLicense reads the module source from the local module cache, either extracted or as the downloaded zip,
+// and fetches it from the proxy only if it isn't there. The offline config key, set by --offline, disables fetching.
+func (f *Checker) getLicense(moduleName string, version *semver.Version) (detection, error) {
+	if dir, zipFile, ok := f.cachedModule(moduleName, version); ok {
+		if dir != "" {
+			return f.getTypeFromLocalFile(dir)
+		}
+
+		return f.getTypeFromZip(zipFile)
+	}
+
+	if viper.GetBool("offline") {
+		return detection{}, fmt.Errorf("%s@%s: %w", moduleName, version.Original(), ErrNotInModuleCache)
 	}
 
 	return f.getTypeFromProxy(moduleName, version)
 }
 
+// cachedModule returns the extracted directory or, if only the download is cached, the zip of the module version.
+func (f *Checker) cachedModule(moduleName string, version *semver.Version) (dir, zipFile string, ok bool) {
+	modCache := f.moduleCache()
+	if modCache == "" {
+		return "", "", false
+	}
+
+	dir = createLocalModulePath(modCache, moduleName, version)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir, "", true
+	}
+
+	zipFile = createCachedZipPath(modCache, moduleName, version)
+	if _, err := os.Stat(zipFile); err == nil {
+		return "", zipFile, true
+	}
+
+	return "", "", false
+}
+
+// moduleCache returns the module cache directory of the go command, GOMODCACHE, resolved once.
+// go env is used since it may be set in the go env file too, GOPATH/pkg/mod is used if the go command fails.
+func (f *Checker) moduleCache() string {
+	f.modCacheOnce.Do(func() {
+		cmd := exec.CommandContext(f.ctx, "go", "env", "GOMODCACHE")
+
+		if out, err := cmd.Output(); err == nil {
+			f.modCache = strings.TrimSpace(string(out))
+		}
+
+		if f.modCache == "" && os.Getenv("GOPATH") != "" {
+			f.modCache = filepath.Join(filepath.SplitList(os.Getenv("GOPATH"))[0], "pkg", "mod")
+		}
+	})
+
+	return f.modCache
+}
+
 // getTypeFromProxy fetches source code from proxy and tries to detect license.
-func (f *Checker) getTypeFromProxy(moduleName string, v *semver.Version) (string, error) {
+func (f *Checker) getTypeFromProxy(moduleName string, v *semver.Version) (detection, error) {
+	url, err := createGoProxyURLForVersion(moduleName, v)
+	if err != nil {
+		return detection{}, err
+	}
+
 	response, err := f.restClient.R().
 		SetContext(f.ctx).
-		Get(createGoProxyURLForVersion(moduleName, v))
+		Get(url)
 	if err != nil {
-		return "", err
+		return detection{}, err
 	}
 
 	if !response.IsSuccess() {
-		return "", errors.New(response.String())
+		return detection{}, errors.New(response.String())
 	}
 
 	tempZipName := fmt.Sprintf("%s-%s.*.zip", strings.ReplaceAll(moduleName, "/", ""), v.Original())
 
 	tempFile, err := ioutil.TempFile("", tempZipName)
 	if err != nil {
-		return "", err
+		return detection{}, err
 	}
 	defer os.Remove(tempFile.Name())
 
 	_, err = tempFile.Write(response.Body())
 	if err != nil {
-		return "", err
+		return detection{}, err
 	}
 
 	err = tempFile.Close()
 	if err != nil {
-		return "", err
+		return detection{}, err
 	}
 
-	match := invalidLicense
-	err = archiver.Walk(tempFile.Name(), func(file archiver.File) error {
-		if strings.HasPrefix(file.Name(), licenseFilename) {
-			b, err := ioutil.ReadAll(file)
-			if err != nil {
-				return err
-			}
+	return f.getTypeFromZip(tempFile.Name())
+}
+
+// getTypeFromZip detects license of the license files at the root of the module zip.
+func (f *Checker) getTypeFromZip(zipFile string) (detection, error) {
+	var licenseFiles []licenseFile
+	err := archiver.Walk(zipFile, func(file archiver.File) error {
+		// files of a module zip are prefixed by module@version/.
+		header, ok := file.Header.(zip.FileHeader)
+		if !ok {
+			return nil
+		}
+
+		at := strings.Index(header.Name, "@")
+		if at < 0 {
+			return nil
+		}
+
+		slash := strings.Index(header.Name[at:], "/")
+		if slash < 0 {
+			return nil
+		}
 
-			match = f.classifier.NearestMatch(string(b)).Name
+		name := header.Name[at+slash+1:]
+		if strings.Contains(name, "/") || !isLicenseFile(name) {
+			return nil
 		}
 
+		b, err := ioutil.ReadAll(file)
+		if err != nil {
+			return err
+		}
+
+		licenseFiles = append(licenseFiles, licenseFile{path: name, content: b})
+
 		return nil
 	})
 	if err != nil {
-		return "", err
+		return detection{}, err
+	}
+
+	return f.detect(licenseFiles), err
+}
+
+// isLicenseFile reports whether the file name is one of licenseFilenames.
+func isLicenseFile(name string) bool {
+	for _, prefix := range licenseFilenames {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
 	}
 
-	return match, err
+	return false
+}
+
+// detect returns license of the given license files.
+// Every license file applies, so distinct licenses of the files, either an SPDX-License-Identifier or
+// the classifier match, are combined with AND. A choice is only offered by an OR in an SPDX expression.
+// Matches scored below the min_confidence config key, set by --min-confidence, are left out.
+// Every file is reported with the license detected in it.
+func (f *Checker) detect(licenseFiles []licenseFile) detection {
+	minConfidence := viper.GetFloat64("min_confidence")
+
+	var licenses []string
+	seen := make(map[string]bool)
+	confidence, rejected := 1.0, 0.0
+	files := make([]internal.LicenseFile, 0, len(licenseFiles))
+
+	add := func(license string) {
+		if !seen[license] {
+			seen[license] = true
+			licenses = append(licenses, license)
+		}
+	}
+
+	for _, lf := range licenseFiles {
+		file := internal.LicenseFile{Path: lf.path, License: internal.UnknownLicense}
+
+		if m := spdxIdentifierRegexp.FindSubmatchIndex(lf.content); m != nil {
+			file.License, file.Confidence = strings.TrimSpace(string(lf.content[m[2]:m[3]])), 1
+			file.Offset, file.Snippet = m[0], snippet(lf.content, m[0], m[1]-m[0])
+			files = append(files, file)
+			add(file.License)
+
+			continue
+		}
+
+		match := f.classifier.NearestMatch(string(lf.content))
+		if match != nil {
+			file.Confidence, file.Offset, file.Snippet = match.Confidence, match.Offset, snippet(lf.content, match.Offset, match.Extent)
+		}
+		files = append(files, file)
+
+		if match == nil {
+			continue
+		}
+
+		if match.Confidence < minConfidence {
+			logger.Debugf("license %s matched in %s with confidence %.2f below %.2f, ignored", match.Name, lf.path, match.Confidence, minConfidence)
+			rejected = math.Max(rejected, match.Confidence)
+			continue
+		}
+
+		files[len(files)-1].License = match.Name
+		confidence = math.Min(confidence, match.Confidence)
+		add(match.Name)
+	}
+
+	switch len(licenses) {
+	case 0:
+		return detection{license: internal.UnknownLicense, confidence: rejected, files: files}
+	case 1:
+		return detection{license: licenses[0], confidence: confidence, files: files}
+	}
+
+	operands := make([]string, 0, len(licenses))
+	for _, license := range licenses {
+		// compound SPDX expressions keep their own precedence inside the AND.
+		if e, err := internal.ParseLicenseExpression(license); err == nil && e.IsCompound() {
+			license = "(" + license + ")"
+		}
+		operands = append(operands, license)
+	}
+
+	return detection{license: strings.Join(operands, " AND "), confidence: confidence, files: files}
+}
+
+// snippet returns the first line of the matched text, shortened to snippetLength.
+func snippet(content []byte, offset, extent int) string {
+	if offset < 0 || offset >= len(content) {
+		return ""
+	}
+
+	end := offset + extent
+	if extent <= 0 || end > len(content) {
+		end = len(content)
+	}
+
+	text := strings.TrimSpace(string(content[offset:end]))
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = strings.TrimSpace(text[:i])
+	}
+
+	if len(text) > snippetLength {
+		text = text[:snippetLength] + "..."
+	}
+
+	return text
 }
 
 // Types finds licenses of all dependencies.
@@ -127,51 +369,76 @@ func (f *Checker) Types(path string) (map[string]internal.LicenseResult, error)
 	m := make(map[string]internal.LicenseResult)
 
 	for _, result := range parse {
-		licenseResult := internal.LicenseResult{
-			LocalVersion: result.LocalVersion,
-		}
+		m[result.Path] = f.License(result.Path, result.LocalVersion)
+	}
 
-		licenseType, err := f.getLicense(result.Path, licenseResult.LocalVersion)
-		if err != nil {
-			licenseResult.Error = err
-		} else {
-			licenseResult.Type = licenseType
-		}
+	return m, nil
+}
+
+// License finds license of the given module version.
+func (f *Checker) License(moduleName string, version *semver.Version) internal.LicenseResult {
+	if override, ok := licenseOverride(moduleName, version); ok {
+		licenseResult := internal.NewLicenseResult(version, override)
+		licenseResult.Confidence = 1
+		licenseResult.Overridden = true
 
-		m[result.Path] = licenseResult
+		return licenseResult
 	}
 
-	return m, nil
+	detected, err := f.getLicense(moduleName, version)
+
+	licenseResult := internal.NewLicenseResult(version, detected.license)
+	licenseResult.Confidence = detected.confidence
+	licenseResult.Files = detected.files
+	licenseResult.Error = err
+
+	return licenseResult
+}
+
+// LocalLicense finds license of the module in the given directory.
+func (f *Checker) LocalLicense(dir string) internal.LicenseResult {
+	detected, err := f.getTypeFromLocalFile(dir)
+
+	licenseResult := internal.NewLicenseResult(nil, detected.license)
+	licenseResult.Confidence = detected.confidence
+	licenseResult.Files = detected.files
+	licenseResult.Error = err
+
+	return licenseResult
 }
 
 // getTypeFromLocalFile fetches type from the local modules directory.
-func (f *Checker) getTypeFromLocalFile(path string) (string, error) {
-	match := invalidLicense
+func (f *Checker) getTypeFromLocalFile(path string) (detection, error) {
+	match := detection{license: internal.UnknownLicense}
 
 	dir, err := ioutil.ReadDir(path)
 	if err != nil {
 		return match, err
 	}
 
+	var licenseFiles []licenseFile
+
 	for _, info := range dir {
-		if strings.HasPrefix(info.Name(), licenseFilename) {
+		if !info.IsDir() && isLicenseFile(info.Name()) {
 			b, err := ioutil.ReadFile(filepath.Join(path, info.Name()))
 			if err != nil {
 				return match, err
 			}
 
-			match = f.classifier.NearestMatch(string(b)).Name
-
-			return match, err
+			licenseFiles = append(licenseFiles, licenseFile{path: info.Name(), content: b})
 		}
 	}
 
-	return match, err
+	return f.detect(licenseFiles), err
 }
 
 // getVersion parses version if version provided, else it will fetch the latest version from proxy.
 func (f *Checker) getVersion(moduleName, version string) (*semver.Version, error) {
 	if version == "" {
+		if viper.GetBool("offline") {
+			return nil, errors.New("version is required offline, the latest version can't be resolved")
+		}
+
 		v, err := f.getLatestVersion(moduleName)
 		if err != nil {
 			return nil, err
@@ -192,11 +459,16 @@ func (f *Checker) getVersion(moduleName, version string) (*semver.Version, error
 func (f *Checker) getLatestVersion(moduleName string) (*semver.Version, error) {
 	resp := &response{}
 
+	url, err := createGoProxyURLForLatestVersion(moduleName)
+	if err != nil {
+		return nil, err
+	}
+
 	response, err := f.restClient.R().
 		SetContext(f.ctx).
 		SetHeader("Accept", "application/json").
 		SetResult(resp).
-		Get(createGoProxyURLForLatestVersion(moduleName))
+		Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -212,16 +484,30 @@ func (f *Checker) getLatestVersion(moduleName string) (*semver.Version, error) {
 	return semver.NewVersion(resp.Version)
 }
 
-func createGoProxyURLForLatestVersion(moduleName string) string {
-	return fmt.Sprintf("%s/%s/@latest", getGoProxy(), moduleName)
+func createGoProxyURLForLatestVersion(moduleName string) (string, error) {
+	proxy, err := getGoProxy()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s/@latest", proxy, moduleName), nil
+}
+
+func createLocalModulePath(modCache, moduleName string, version *semver.Version) string {
+	return filepath.Join(modCache, filepath.FromSlash(encodeModuleName(moduleName))+"@"+version.Original())
 }
 
-func createLocalModulePath(moduleName string, version *semver.Version) string {
-	return fmt.Sprintf("%s/pkg/mod/%s@%s", os.Getenv("GOPATH"), encodeModuleName(moduleName), version.Original())
+func createCachedZipPath(modCache, moduleName string, version *semver.Version) string {
+	return filepath.Join(modCache, "cache", "download", filepath.FromSlash(encodeModuleName(moduleName)), "@v", version.Original()+".zip")
 }
 
-func createGoProxyURLForVersion(moduleName string, version *semver.Version) string {
-	return fmt.Sprintf("%s/%s/@v/%s.zip", getGoProxy(), encodeModuleName(moduleName), version.Original())
+func createGoProxyURLForVersion(moduleName string, version *semver.Version) (string, error) {
+	proxy, err := getGoProxy()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s/@v/%s.zip", proxy, encodeModuleName(moduleName), version.Original()), nil
 }
 
 // encodeModuleName encodes module name to follow module path conventions.
@@ -234,26 +520,18 @@ func encodeModuleName(moduleName string) string {
 	})
 }
 
-// getGoProxy returns first Go proxy, if not set returns default proxy.
-func getGoProxy() string {
-	goProxyEnv := os.Getenv("GOPROXY")
-
-	goProxies := strings.Split(goProxyEnv, ",")
-
-	n := 0
-	for _, x := range goProxies {
-		if x != "direct" && strings.TrimSpace(x) != "" {
-			goProxies[n] = x
-			n++
-		}
+// getGoProxy returns the first Go proxy, sources aren't downloaded if the proxy list has none, e.g. for direct.
+func getGoProxy() (string, error) {
+	proxies, err := module.GoProxies()
+	if err != nil {
+		return "", err
 	}
-	goProxies = goProxies[:n]
 
-	if len(goProxies) == 0 {
-		return "https://proxy.golang.org"
+	if len(proxies) == 0 {
+		return "", fmt.Errorf("no proxy in the proxy list %q to download sources from", module.GoProxy())
 	}
 
-	return goProxies[0]
+	return proxies[0].URL, nil
 }
 
 // response is model of proxy version resource.
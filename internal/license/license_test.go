@@ -1,12 +1,16 @@
 package license
 
 import (
+	"archive/zip"
 	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -132,3 +136,171 @@ func (s *LicenseTestSuite) Test_AllDependenciesLicense() {
 		s.NoError(result.Error, name)
 	}
 }
+
+func (s *LicenseTestSuite) Test_OfflineModuleCache() {
+	modCache := filepath.Join(s.tempDir, "mod")
+
+	defer os.Setenv("GOMODCACHE", os.Getenv("GOMODCACHE"))
+	s.NoError(os.Setenv("GOMODCACHE", modCache))
+
+	viper.Set("offline", true)
+	defer viper.Set("offline", nil)
+
+	// extracted module
+	dir := filepath.Join(modCache, "example.com", "!foo@v1.0.0")
+	s.NoError(os.MkdirAll(dir, 0755))
+	s.NoError(ioutil.WriteFile(filepath.Join(dir, "LICENSE"), []byte("SPDX-License-Identifier: MIT\n"), 0644))
+
+	// downloaded zip only
+	download := filepath.Join(modCache, "cache", "download", "example.com", "bar", "@v")
+	s.NoError(os.MkdirAll(download, 0755))
+
+	file, err := os.Create(filepath.Join(download, "v1.2.0.zip"))
+	s.NoError(err)
+
+	w := zip.NewWriter(file)
+	f, err := w.Create("example.com/bar@v1.2.0/LICENSE")
+	s.NoError(err)
+	_, err = f.Write([]byte("SPDX-License-Identifier: BSD-3-Clause\n"))
+	s.NoError(err)
+	s.NoError(w.Close())
+	s.NoError(file.Close())
+
+	checker, err := NewChecker(context.TODO())
+	s.NoError(err)
+
+	licenseType, err := checker.Type("example.com/Foo", "v1.0.0")
+	s.NoError(err)
+	s.Equal("MIT", licenseType)
+
+	licenseType, err = checker.Type("example.com/bar", "v1.2.0")
+	s.NoError(err)
+	s.Equal("BSD-3-Clause", licenseType)
+
+	result := checker.License("example.com/bar", semver.MustParse("v1.2.0"))
+	s.NoError(result.Error)
+	s.Equal("LICENSE", result.Files[0].Path, "the path is relative to the module root")
+
+	_, err = checker.Type("example.com/bar", "v1.3.0")
+	s.ErrorIs(err, ErrNotInModuleCache)
+
+	_, err = checker.Type("example.com/bar", "")
+	s.Error(err, "the latest version can't be resolved offline")
+}
+
+func (s *LicenseTestSuite) Test_DetectConfidence() {
+	checker, err := NewChecker(context.TODO())
+	s.NoError(err)
+
+	mit := []byte(`MIT License
+
+Copyright (c) 2020 Example Authors
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`)
+
+	detected := checker.detect([]licenseFile{{path: "LICENSE", content: mit}})
+	s.Equal("MIT", detected.license)
+	s.Greater(detected.confidence, 0.9)
+	s.LessOrEqual(detected.confidence, 1.0)
+
+	header := []byte("// SPDX-License-Identifier: Apache-2.0\n")
+	s.Equal(detection{license: "Apache-2.0", confidence: 1, files: []internal.LicenseFile{
+		{Path: "LICENSE.go", License: "Apache-2.0", Confidence: 1, Offset: 3, Snippet: "SPDX-License-Identifier: Apache-2.0"},
+	}}, checker.detect([]licenseFile{{path: "LICENSE.go", content: header}}))
+
+	viper.Set("min_confidence", detected.confidence+0.01)
+	defer viper.Set("min_confidence", nil)
+
+	rejected := checker.detect([]licenseFile{{path: "LICENSE", content: mit}})
+	s.Equal(internal.UnknownLicense, rejected.license)
+	s.Equal(detected.confidence, rejected.confidence)
+	s.Equal(internal.UnknownLicense, rejected.files[0].License, "the file is reported even if it isn't recognized")
+}
+
+func (s *LicenseTestSuite) Test_DetectMultipleFiles() {
+	checker, err := NewChecker(context.TODO())
+	s.NoError(err)
+
+	detected := checker.detect([]licenseFile{
+		{path: "LICENSE-MIT", content: []byte("SPDX-License-Identifier: MIT\n")},
+		{path: "LICENSE-GPL", content: []byte("SPDX-License-Identifier: GPL-3.0-only\n")},
+		{path: "LICENSE-DUAL", content: []byte("SPDX-License-Identifier: MIT OR Apache-2.0\n")},
+	})
+	s.Equal("MIT AND GPL-3.0-only AND (MIT OR Apache-2.0)", detected.license, "every license file applies")
+	s.Len(detected.files, 3)
+
+	result := internal.NewLicenseResult(nil, detected.license)
+	expression, err := internal.ParseLicenseExpression(result.Expression)
+	s.NoError(err)
+	s.False(expression.Satisfies(func(license string) bool { return license == "MIT" }), "GPL file fails an MIT allow-list")
+	s.True(expression.Satisfies(func(license string) bool { return license == "MIT" || license == "GPL-3.0-only" }))
+}
+
+func (s *LicenseTestSuite) Test_LicenseFiles() {
+	checker, err := NewChecker(context.TODO())
+	s.NoError(err)
+
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tempDir, "COPYING"), []byte("SPDX-License-Identifier: MIT\n"), 0644))
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tempDir, "README.md"), []byte("SPDX-License-Identifier: GPL-3.0-only\n"), 0644))
+
+	result := checker.LocalLicense(s.tempDir)
+	s.NoError(result.Error)
+	s.Equal("MIT", result.SPDXID)
+	s.False(result.NoLicenseFile())
+	s.Equal([]internal.LicenseFile{{Path: "COPYING", License: "MIT", Confidence: 1, Snippet: "SPDX-License-Identifier: MIT"}}, result.Files)
+
+	s.NoError(os.Remove(filepath.Join(s.tempDir, "COPYING")))
+
+	result = checker.LocalLicense(s.tempDir)
+	s.NoError(result.Error)
+	s.Equal(internal.UnknownLicense, result.Type)
+	s.True(result.NoLicenseFile())
+}
+
+func (s *LicenseTestSuite) Test_LicenseOverrides() {
+	viper.Set("offline", true)
+	viper.Set("license_overrides", map[string]string{
+		"example.com/foo":        "MIT",
+		"example.com/foo@v2.0.0": "Apache-2.0",
+	})
+	defer viper.Set("offline", nil)
+	defer viper.Set("license_overrides", nil)
+
+	checker, err := NewChecker(context.TODO())
+	s.NoError(err)
+
+	result := checker.License("example.com/Foo", semver.MustParse("v1.0.0"))
+	s.NoError(result.Error)
+	s.True(result.Overridden)
+	s.Equal("MIT", result.SPDXID)
+	s.False(result.NoLicenseFile())
+
+	result = checker.License("example.com/foo", semver.MustParse("v2.0.0"))
+	s.True(result.Overridden)
+	s.Equal("Apache-2.0", result.SPDXID, "version specific overrides take precedence")
+
+	licenseType, err := checker.Type("example.com/foo", "v1.0.0")
+	s.NoError(err)
+	s.Equal("MIT", licenseType)
+
+	result = checker.License("example.com/bar", semver.MustParse("v1.0.0"))
+	s.False(result.Overridden)
+	s.ErrorIs(result.Error, ErrNotInModuleCache, "modules without an override are detected")
+}
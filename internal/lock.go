@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// LockVersion is the version of the lock file format.
+const LockVersion = 1
+
+// Lock records latest versions resolved by check, so later checks can report only what changed since.
+type Lock struct {
+	Version int `json:"version"`
+	// Modules are keyed by module path, encoding/json writes them sorted.
+	Modules map[string]LockedModule `json:"modules"`
+}
+
+// LockedModule is a module version and its latest version at the time the lock was written.
+type LockedModule struct {
+	Current    string `json:"current"`
+	Latest     string `json:"latest"`
+	LatestPath string `json:"latestPath,omitempty"`
+}
+
+// NewLock returns a lock of the given results, results with an error are left out.
+func NewLock(results map[string]CheckResult) Lock {
+	lock := Lock{Version: LockVersion, Modules: make(map[string]LockedModule)}
+
+	for name, result := range results {
+		if result.Error != nil || result.LocalVersion == nil || result.LatestVersion == nil {
+			continue
+		}
+
+		lock.Modules[name] = LockedModule{
+			Current:    result.LocalVersion.Original(),
+			Latest:     result.LatestVersion.Original(),
+			LatestPath: result.LatestPath,
+		}
+	}
+
+	return lock
+}
+
+// ReadLock reads a lock file written by WriteLock.
+func ReadLock(file string) (Lock, error) {
+	var lock Lock
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return lock, err
+	}
+
+	err = json.Unmarshal(content, &lock)
+	if err != nil {
+		return lock, fmt.Errorf("lock file %s: %w", file, err)
+	}
+
+	if lock.Version != LockVersion {
+		return lock, fmt.Errorf("lock file %s: unsupported version %d", file, lock.Version)
+	}
+
+	return lock, nil
+}
+
+// WriteLock writes the lock as indented JSON.
+func WriteLock(file string, lock Lock) error {
+	content, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, append(content, '\n'), 0666)
+}
+
+// Changed reports whether the latest version of the module differs from the locked one.
+// Modules missing from the lock and results with an error are reported as changed.
+func (l Lock) Changed(name string, result CheckResult) bool {
+	locked, ok := l.Modules[name]
+	if !ok || result.Error != nil || result.LatestVersion == nil {
+		return true
+	}
+
+	return locked.Latest != result.LatestVersion.Original() || locked.LatestPath != result.LatestPath
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lock")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	results := map[string]CheckResult{
+		"example.com/b": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.2.0")},
+		"example.com/a": {LocalVersion: semver.MustParse("v0.1.0"), LatestVersion: semver.MustParse("v0.1.0")},
+		"example.com/c": {Error: errors.New("not found")},
+	}
+
+	file := filepath.Join(dir, "gomodctl.lock")
+	require.NoError(t, WriteLock(file, NewLock(results)))
+
+	content, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "version": 1,
+  "modules": {
+    "example.com/a": {
+      "current": "v0.1.0",
+      "latest": "v0.1.0"
+    },
+    "example.com/b": {
+      "current": "v1.0.0",
+      "latest": "v1.2.0"
+    }
+  }
+}
+`, string(content))
+
+	lock, err := ReadLock(file)
+	require.NoError(t, err)
+
+	assert.False(t, lock.Changed("example.com/a", results["example.com/a"]))
+	assert.False(t, lock.Changed("example.com/b", CheckResult{LocalVersion: semver.MustParse("v1.1.0"), LatestVersion: semver.MustParse("v1.2.0")}))
+	assert.True(t, lock.Changed("example.com/b", CheckResult{LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.3.0")}))
+	assert.True(t, lock.Changed("example.com/c", results["example.com/c"]))
+	assert.True(t, lock.Changed("example.com/d", CheckResult{LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.0.0")}))
+
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"version": 2}`), 0666))
+	_, err = ReadLock(file)
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync/atomic"
+)
+
+// Level is the verbosity of logging, messages of a higher level than the configured one are dropped.
+type Level int32
+
+const (
+	// LevelError logs only failures, set by --quiet.
+	LevelError Level = iota
+	// LevelWarn logs problems gomodctl recovers from, it is the default.
+	LevelWarn
+	// LevelInfo logs what gomodctl does, set by --verbose.
+	LevelInfo
+	// LevelDebug logs every HTTP request and cache lookup, set by -vv.
+	LevelDebug
+)
+
+var names = map[Level]string{
+	LevelError: "error",
+	LevelWarn:  "warn",
+	LevelInfo:  "info",
+	LevelDebug: "debug",
+}
+
+func (l Level) String() string {
+	return names[l]
+}
+
+var (
+	level  = int32(LevelWarn)
+	output = log.New(os.Stderr, "", log.LstdFlags)
+)
+
+// LevelOf returns the level for the number of --verbose flags, --quiet takes precedence.
+func LevelOf(verbose int, quiet bool) Level {
+	if quiet {
+		return LevelError
+	}
+
+	l := LevelWarn + Level(verbose)
+	if l > LevelDebug {
+		return LevelDebug
+	}
+
+	return l
+}
+
+// SetLevel sets the level of messages to be logged.
+func SetLevel(l Level) {
+	atomic.StoreInt32(&level, int32(l))
+}
+
+// SetOutput sets the destination of log messages, stderr by default so stdout keeps only results.
+func SetOutput(w io.Writer) {
+	output.SetOutput(w)
+}
+
+// Enabled reports whether messages of the given level are logged.
+func Enabled(l Level) bool {
+	return int32(l) <= atomic.LoadInt32(&level)
+}
+
+// Errorf logs a failure.
+func Errorf(format string, args ...interface{}) {
+	logf(LevelError, format, args...)
+}
+
+// Warnf logs a problem gomodctl recovers from.
+func Warnf(format string, args ...interface{}) {
+	logf(LevelWarn, format, args...)
+}
+
+// Infof logs what gomodctl does.
+func Infof(format string, args ...interface{}) {
+	logf(LevelInfo, format, args...)
+}
+
+// Debugf logs details useful for troubleshooting.
+func Debugf(format string, args ...interface{}) {
+	logf(LevelDebug, format, args...)
+}
+
+func logf(l Level, format string, args ...interface{}) {
+	if !Enabled(l) {
+		return
+	}
+
+	output.Printf("%s: %s", l, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelOf(t *testing.T) {
+	assert.Equal(t, LevelWarn, LevelOf(0, false))
+	assert.Equal(t, LevelInfo, LevelOf(1, false))
+	assert.Equal(t, LevelDebug, LevelOf(2, false))
+	assert.Equal(t, LevelDebug, LevelOf(5, false))
+	assert.Equal(t, LevelError, LevelOf(2, true))
+}
+
+func TestLogf(t *testing.T) {
+	var buf bytes.Buffer
+
+	SetOutput(&buf)
+	defer SetOutput(os.Stderr)
+	defer SetLevel(LevelWarn)
+
+	SetLevel(LevelInfo)
+	Debugf("dropped %d", 1)
+	Infof("kept %d", 2)
+	Errorf("kept %d", 3)
+
+	assert.NotContains(t, buf.String(), "dropped")
+	assert.Contains(t, buf.String(), "info: kept 2\n")
+	assert.Contains(t, buf.String(), "error: kept 3\n")
+}
@@ -1,21 +1,391 @@
 package internal
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/Masterminds/semver"
 )
 
+// StdinPath given as path makes go.mod be read from stdin.
+const StdinPath = "-"
+
+// ErrVendorMismatch is the error of a check result whose version in vendor/modules.txt differs from go.mod.
+var ErrVendorMismatch = errors.New("vendor/modules.txt doesn't match go.mod")
+
+// ErrModuleIgnored is the error of a check result of a module excluded from version check.
+var ErrModuleIgnored = errors.New("module ignored")
+
+// ErrModuleTimeout is the error of a check result of a module which wasn't resolved within CheckOptions.ModuleTimeout.
+var ErrModuleTimeout = errors.New("module timed out")
+
+// UpdateScope limits the versions considered as an update candidate.
+type UpdateScope int
+
+const (
+	// ScopeLatest considers every available version.
+	ScopeLatest UpdateScope = iota
+	// ScopeMajor considers only versions with a higher major than the local version.
+	ScopeMajor
+	// ScopeMinor considers only versions within the major of the local version.
+	ScopeMinor
+	// ScopePatch considers only versions within the major.minor of the local version.
+	ScopePatch
+)
+
+// CheckOptions contains options for version check.
+type CheckOptions struct {
+	Scope UpdateScope
+	// Exclude contains glob patterns of module paths to be ignored, see path.Match.
+	Exclude []string
+	// DirectOnly leaves out modules required with an // indirect comment.
+	DirectOnly bool
+	// Prerelease considers prereleases as update candidates, by default they are suggested only if there is no stable version.
+	Prerelease bool
+	// Progress is called with the number of resolved and all modules whenever a module is done, it may be nil.
+	Progress func(done, total int)
+	// GoVersion skips update candidates whose go.mod requires a newer Go, e.g. 1.21. The version of the go command is used if it is empty.
+	GoVersion string
+	// Changed checks only modules whose require lines in go.mod changed since git HEAD.
+	Changed bool
+	// Base is a git revision, e.g. origin/main, only modules added or required with another version than by go.mod
+	// of it are checked, see CheckResult.Base.
+	Base string
+	// Times resolves publish times of the local and latest versions, check always resolves them.
+	Times bool
+	// Vendor compares versions in vendor/modules.txt with go.mod, modules which differ fail with ErrVendorMismatch.
+	Vendor bool
+	// Secure looks up advisories of the local versions in the OSV database, see CheckResult.SecureVersion.
+	Secure bool
+	// Modules are checked instead of the requirements of go.mod, which isn't read then, so Changed and Vendor don't apply.
+	// A version is optional unless Scope limits the update, like for Checker.CheckModule.
+	Modules []Requirement
+	// Retries is how often versions of a module are looked up again after they failed with ErrNetwork, with exponential
+	// backoff. Other errors, e.g. ErrNotFound, fail the module at once.
+	Retries int
+	// ModuleTimeout limits the time resolving a module of go.mod may take including its retries, a module which takes
+	// longer fails with ErrModuleTimeout and the others are still checked. Zero means no limit.
+	ModuleTimeout time.Duration
+	// NoCache looks up versions again instead of reading them from the cache, like the no_cache config.
+	NoCache bool
+	// Wanted resolves CheckResult.WantedVersion from the same versions as LatestVersion, so both need one lookup.
+	Wanted bool
+}
+
+// UpdateOptions contains options for update.
+type UpdateOptions struct {
+	CheckOptions
+	// Backup copies go.mod and go.sum to go.mod.bak and go.sum.bak before go.mod is changed.
+	Backup bool
+	// DryRun resolves updates without changing any file.
+	DryRun bool
+	// Verify runs go mod tidy and go build ./... after the update and restores go.mod and go.sum if they fail.
+	Verify bool
+	// Remove contains modules to be dropped from go.mod, e.g. unused modules.
+	Remove []string
+	// Indirect sets the // indirect comments of the given modules, their versions aren't changed.
+	Indirect map[string]bool
+	// AllowDowngrade lets pinned versions be lower than the current ones.
+	AllowDowngrade bool
+}
+
+// BisectResult is the outcome of an update which isolates updates breaking the build.
+type BisectResult struct {
+	// Applied are updates left in go.mod, the module builds with them.
+	Applied map[string]CheckResult
+	// Culprits are updates left out of go.mod, Error of each is the build failure caused by it.
+	Culprits map[string]CheckResult
+}
+
+// Requirement is a module version in a requirement chain, the main module has no version.
+type Requirement struct {
+	Path    string
+	Version string
+}
+
+// Position is a position of a directive in go.mod, lines and columns start at 1.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// IsValid reports whether the position is known.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns line:column of the position, messages prefix it with the file name, e.g. go.mod:42:2.
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// String returns path@version of the requirement, only the path of the main module.
+func (r Requirement) String() string {
+	if r.Version == "" {
+		return r.Path
+	}
+
+	return r.Path + "@" + r.Version
+}
+
+// GraphOptions contains options for graph.
+type GraphOptions struct {
+	// Root limits the graph to modules required by the given module, with an optional @version. The main module is the root if it is empty.
+	Root string
+	// Depth limits how many requirement levels below the root are included, zero means no limit.
+	Depth int
+}
+
+// Edge is a requirement of one module by another in the module graph.
+type Edge struct {
+	From Requirement
+	To   Requirement
+}
+
+// Graph is the module graph below its root, edges are in breadth first order from the root.
+type Graph struct {
+	Root  Requirement
+	Edges []Edge
+}
+
+// UnusedModule is a module required by go.mod which go mod tidy would remove.
+type UnusedModule struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+// IndirectFix is a requirement whose // indirect comment in go.mod differs from the one go mod tidy would write.
+type IndirectFix struct {
+	Path    string
+	Version string
+	// Indirect is the corrected marker, true if the module isn't imported by the main module.
+	Indirect bool
+}
+
+// ScanOptions contains options for vulnerability scan.
+type ScanOptions struct {
+	// MinSeverity leaves out advisories rated lower, advisories without rating are always reported.
+	MinSeverity Severity
+	// Progress is called with the number of scanned and all modules whenever a module is done, it may be nil.
+	Progress func(done, total int)
+	// Changed scans only modules whose require lines in go.mod changed since git HEAD.
+	Changed bool
+	// DB is a local copy of the OSV database, a directory or a file of OSV JSON records or a zip of them.
+	// The OSV API isn't queried if it is set.
+	DB string
+	// DBURL is the URL of an OSV API mirror, osv_url config or the public API are used if it is empty.
+	DBURL string
+	// Upgrades resolves direct dependency upgrades fixing vulnerable transitive modules, see VulnerabilityResult.Upgrades.
+	Upgrades bool
+	// ProdOnly scans only modules providing packages imported by non-test packages of the main module,
+	// modules used only by tests or providing no imported package are left out.
+	ProdOnly bool
+	// Trust contains module path prefixes which aren't scanned, they are added to the trusted_prefixes config.
+	// Like GOPRIVATE they are comma separated glob patterns matching path prefixes.
+	Trust []string
+	// Stream is called with every module as soon as it is scanned, calls are serialized. The results aren't collected
+	// then, so Scan returns none of them, and Upgrades can't be set as they need all of them.
+	Stream func(path string, result VulnerabilityResult)
+}
+
+// UpdateType is kind of the update between two versions.
+type UpdateType string
+
+const (
+	// UpdateNone means there is no newer version.
+	UpdateNone UpdateType = "none"
+	// UpdateMajor means major version is increased.
+	UpdateMajor UpdateType = "major"
+	// UpdateMinor means minor version is increased.
+	UpdateMinor UpdateType = "minor"
+	// UpdatePatch means patch version or pre-release is increased.
+	UpdatePatch UpdateType = "patch"
+	// UpdateDowngrade means the version is lowered, GetUpdateType never returns it, only pins may lower versions.
+	UpdateDowngrade UpdateType = "downgrade"
+)
+
 // CheckResult is exported.
 type CheckResult struct {
 	LocalVersion  *semver.Version
 	LatestVersion *semver.Version
 	Error         error
+	Replaced      bool
+	// Indirect is true if go.mod requires the module with an // indirect comment.
+	Indirect bool
+	// Retracted is true if the local version is retracted by the module author.
+	Retracted bool
+	// Pseudo is true if the local version is a pseudo-version of an untagged commit, see IsPseudoVersion.
+	Pseudo bool
+	// Untagged is true if the local version is a pseudo-version and the module has no tagged releases,
+	// LatestVersion is the local version then.
+	Untagged bool
+	// Base is the change of the requirement since go.mod of CheckOptions.Base, nil without it.
+	Base *DiffResult
+	// Deprecated is the deprecation message of go.mod of the latest version, empty if the module isn't deprecated.
+	Deprecated string
+	// LatestPath is set if LatestVersion belongs to a module with a higher major version suffix,
+	// e.g. example.com/foo/v2 for example.com/foo, updating to it requires changing import paths.
+	LatestPath string
+	// LocalVersions are the distinct versions required by several modules of a workspace, sorted from the oldest.
+	// It is empty if every module requires LocalVersion, which is the oldest one otherwise.
+	LocalVersions []*semver.Version
+	// SkippedVersion is the newest candidate skipped as its go.mod requires a newer Go than CheckOptions.GoVersion,
+	// SkippedGo is the Go version it requires.
+	SkippedVersion *semver.Version
+	SkippedGo      string
+	// WantedVersion is the newest version within the local major, or within the local minor for v0 versions,
+	// like a caret range of npm. It is only resolved with CheckOptions.Wanted.
+	WantedVersion *semver.Version
+	// LocalTime and LatestTime are publish times of the versions, zero if they are unknown.
+	LocalTime  time.Time
+	LatestTime time.Time
+	// Advisories are IDs of the advisories affecting the local version, they are only resolved with CheckOptions.Secure.
+	Advisories []string
+	// SecureVersion is the lowest available version fixing all Advisories, nil if any of them isn't fixed.
+	// Unlike LatestVersion it is the smallest safe update.
+	SecureVersion *semver.Version
+}
+
+// Stale reports whether the local version was published longer than the given duration ago.
+func (r CheckResult) Stale(after time.Duration, now time.Time) bool {
+	return after > 0 && !r.LocalTime.IsZero() && now.Sub(r.LocalTime) > after
+}
+
+// ReleasedSince reports whether the latest version was published at or after the given time, false if it is unknown.
+func (r CheckResult) ReleasedSince(since time.Time) bool {
+	return !r.LatestTime.IsZero() && !r.LatestTime.Before(since)
 }
 
+// TagAvailable reports whether the local version is a pseudo-version although the module has tagged releases,
+// LatestVersion is the tag to move to.
+func (r CheckResult) TagAvailable() bool {
+	return r.Error == nil && r.Pseudo && !r.Untagged && r.LatestVersion != nil
+}
+
+// Updatable reports whether there is a newer version to update to.
+// Replaced modules are pinned by the replace directive, so they are never updatable.
+func (r CheckResult) Updatable() bool {
+	return r.Error == nil && !r.Replaced && r.LocalVersion != nil && r.LatestVersion != nil && r.LatestVersion.GreaterThan(r.LocalVersion)
+}
+
+// BaseDowngrade reports whether the local version is lower than the one required by go.mod of CheckOptions.Base.
+func (r CheckResult) BaseDowngrade() bool {
+	return r.Base != nil && r.Base.Downgrade()
+}
+
+// Downgrade reports whether the latest version is lower than the local one, e.g. if the local version is retracted
+// or outside of the constraints config. Only versions pinned with UpdateOptions.AllowDowngrade are written as such.
+func (r CheckResult) Downgrade() bool {
+	return r.Error == nil && !r.Replaced && r.LocalVersion != nil && r.LatestVersion != nil && r.LatestVersion.LessThan(r.LocalVersion)
+}
+
+// TimedOut reports whether the module wasn't resolved within CheckOptions.ModuleTimeout.
+func (r CheckResult) TimedOut() bool {
+	return errors.Is(r.Error, ErrModuleTimeout)
+}
+
+// Failed reports whether the versions of the module couldn't be resolved, ignored modules didn't fail.
+func (r CheckResult) Failed() bool {
+	return r.Error != nil && !errors.Is(r.Error, ErrModuleIgnored)
+}
+
+// UpdateType compares local and latest versions.
+func (r CheckResult) UpdateType() UpdateType {
+	return GetUpdateType(r.LocalVersion, r.LatestVersion)
+}
+
+// GetUpdateType returns kind of the update from one version to the other.
+func GetUpdateType(from, to *semver.Version) UpdateType {
+	if from == nil || to == nil || !to.GreaterThan(from) {
+		return UpdateNone
+	}
+
+	switch {
+	case to.Major() != from.Major():
+		return UpdateMajor
+	case to.Minor() != from.Minor():
+		return UpdateMinor
+	default:
+		return UpdatePatch
+	}
+}
+
+// DiffChange is kind of change of a module between two go.mod files.
+type DiffChange string
+
+const (
+	// DiffAdded means the module is required only by the new go.mod.
+	DiffAdded DiffChange = "added"
+	// DiffRemoved means the module is required only by the old go.mod.
+	DiffRemoved DiffChange = "removed"
+	// DiffChanged means the module is required by both with different versions.
+	DiffChanged DiffChange = "changed"
+)
+
+// DiffResult is the change of a module between two go.mod files.
+// OldVersion is nil for added modules, NewVersion is nil for removed ones,
+// both are nil for modules replaced by a local path.
+type DiffResult struct {
+	Change     DiffChange
+	OldVersion *semver.Version
+	NewVersion *semver.Version
+	Indirect   bool
+}
+
+// Downgrade reports whether the new version is lower than the old one.
+func (r DiffResult) Downgrade() bool {
+	return r.OldVersion != nil && r.NewVersion != nil && r.NewVersion.LessThan(r.OldVersion)
+}
+
+// UpdateType classifies the version change, downgrades are classified the same way as updates.
+func (r DiffResult) UpdateType() UpdateType {
+	if r.Downgrade() {
+		return GetUpdateType(r.NewVersion, r.OldVersion)
+	}
+
+	return GetUpdateType(r.OldVersion, r.NewVersion)
+}
+
+// UnknownLicense is the license type of modules without a detected license.
+const UnknownLicense = "Can't find license"
+
 // LicenseResult is result for license check.
 type LicenseResult struct {
 	LocalVersion *semver.Version
-	Type         string
-	Error        error
+	// Type is the license name as detected.
+	Type string
+	// SPDXID is Type normalized to an SPDX identifier, NOASSERTION if it can't be mapped.
+	SPDXID string
+	// Expression and Licenses are set when the module has more than one license, e.g. `MIT OR Apache-2.0`.
+	Expression string
+	Licenses   []string
+	// Confidence is the score of the license text match from 0 to 1, 1 for an SPDX-License-Identifier.
+	// For UnknownLicense it is the score of the best match rejected by the confidence threshold, if any.
+	Confidence float64
+	// Files are the license files found at the module root, empty if the module has none, see NoLicenseFile.
+	Files []LicenseFile
+	// Overridden is true if the license is set by the license_overrides config key, it isn't detected then.
+	Overridden bool
+	Error      error
+}
+
+// NoLicenseFile reports whether the module has no license file, unlike a license file which isn't recognized.
+func (r LicenseResult) NoLicenseFile() bool {
+	return r.Error == nil && !r.Overridden && len(r.Files) == 0
+}
+
+// LicenseFile is a license file of a module and the license detected in it.
+type LicenseFile struct {
+	// Path is relative to the module root, e.g. LICENSE, LICENSE.md or COPYING.
+	Path string
+	// License is UnknownLicense if the file isn't recognized or the match is below the confidence threshold.
+	License    string
+	Confidence float64
+	// Offset is the byte offset of the matched text in the file, Snippet is its first line.
+	Offset  int
+	Snippet string
 }
 
 // SearchResult is exported.
@@ -28,25 +398,81 @@ type SearchResult struct {
 	Synopsis    string
 }
 
-// VulnerabilityResult is exported
+// VulnerabilityResult contains known vulnerabilities of the local version of a module.
 type VulnerabilityResult struct {
-	Issues []struct {
-		Code       string `json:"code"`
-		File       string `json:"file"`
-		Line       string `json:"line"`
-		Column     string `json:"column"`
-		Details    string `json:"details"`
-		RuleID     string `json:"rule_id"`
-		Severity   string `json:"severity"`
-		Confidence string `json:"confidence"`
-		Cwe        struct {
-			ID  string `json:"id"`
-			URL string `json:"url"`
-		} `json:"cwe"`
-	} `json:"issues"`
-	Stats struct {
+	LocalVersion    *semver.Version
+	Vulnerabilities []Vulnerability
+	Error           error
+	// Position is where go.mod requires the module, zero if it is only required transitively.
+	Position Position
+	// Upgrades are direct dependency versions pulling in a version of the module which fixes all its vulnerabilities,
+	// each of them alone fixes it. They are only resolved with ScanOptions.Upgrades for modules which aren't direct dependencies.
+	Upgrades []Requirement
+	// Trusted is true if the module matches a trusted prefix, it isn't scanned then.
+	Trusted bool
+}
+
+// GosecResult contains issues found by gosec in the sources of a module, see scan --gosec.
+type GosecResult struct {
+	Issues []GosecIssue `json:"issues"`
+	Stats  struct {
 		Files int `json:"files"`
 		Found int `json:"found"`
 		Lines int `json:"lines"`
 	} `json:"stats"`
 }
+
+// GosecIssue is an issue reported by gosec.
+type GosecIssue struct {
+	Code       string `json:"code"`
+	File       string `json:"file"`
+	Line       string `json:"line"`
+	Column     string `json:"column"`
+	Details    string `json:"details"`
+	RuleID     string `json:"rule_id"`
+	Severity   string `json:"severity"`
+	Confidence string `json:"confidence"`
+	Cwe        struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	} `json:"cwe"`
+}
+
+// Vulnerability is an advisory of the OSV database affecting a module version.
+type Vulnerability struct {
+	// ID is the OSV identifier, e.g. GO-2021-0001 or GHSA-xxxx-xxxx-xxxx.
+	ID string `json:"id"`
+	// Aliases contains other identifiers of the advisory such as CVE IDs.
+	Aliases []string `json:"aliases"`
+	Summary string   `json:"summary"`
+	// Severity is the CVSS vector or the severity rating of the advisory, empty if it isn't rated.
+	Severity string `json:"severity"`
+	// Score is the CVSS v3 base score, zero if the advisory has no CVSS v3 vector.
+	Score  float64  `json:"score"`
+	Rating Severity `json:"rating"`
+	// Fixed is the first version fixing the vulnerability, empty if there is no fix.
+	Fixed string `json:"fixed"`
+}
+
+// BOM is the software bill of materials of a module.
+type BOM struct {
+	Main       Component
+	Components []Component
+}
+
+// Component is a module listed in a BOM.
+type Component struct {
+	// Path is the module which provides the code, the replacement if the module is replaced.
+	Path string
+	// Version is empty for the main module.
+	Version  string
+	Indirect bool
+	License  LicenseResult
+	// Dependencies are paths of the components required by the component.
+	Dependencies []string
+}
+
+// PackageURL returns package URL of the component.
+func (c Component) PackageURL() string {
+	return PackageURL(c.Path, c.Version)
+}
@@ -0,0 +1,108 @@
+package module
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	goSum          = "go.sum"
+	backupSuffix   = ".bak"
+	backupFileMode = 0666
+)
+
+// ErrNoBackup is returned when there is no backup to restore.
+var ErrNoBackup = errors.New("no backup found")
+
+// backup copies go.mod and go.sum in the given directory to go.mod.bak and go.sum.bak.
+// go.sum is optional, a missing go.sum.bak records that there was none, so a stale one is removed.
+// Every copy is written to a temporary file first and renamed.
+func backup(dir string) error {
+	for _, name := range []string{goMod, goSum} {
+		file := filepath.Join(dir, name)
+
+		content, err := ioutil.ReadFile(file)
+		if os.IsNotExist(err) && name == goSum {
+			if err := os.Remove(file + backupSuffix); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = writeFileAtomic(file+backupSuffix, content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Restore restores go.mod and go.sum from the backup created by update.
+// go.sum is removed if there was none when the backup was created, e.g. when go mod tidy created it afterwards.
+func (u *Updater) Restore(path string) error {
+	if err := checkWritable(path); err != nil {
+		return err
+	}
+
+	absolutePath, err := absDir(path)
+	if err != nil {
+		return err
+	}
+
+	modBackup := filepath.Join(absolutePath, goMod+backupSuffix)
+	if _, err := os.Stat(modBackup); os.IsNotExist(err) {
+		return ErrNoBackup
+	}
+
+	for _, name := range []string{goMod, goSum} {
+		file := filepath.Join(absolutePath, name)
+
+		err := os.Rename(file+backupSuffix, file)
+		if os.IsNotExist(err) && name == goSum {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes content to a temporary file in the same directory and renames it to file.
+func writeFileAtomic(file string, content []byte) error {
+	tempFile, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(content)
+	if err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tempFile.Name(), backupFileMode)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), file)
+}
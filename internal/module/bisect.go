@@ -0,0 +1,200 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// Bisect applies the given updates and isolates the ones which break go mod tidy or go build ./...
+// Failing sets are binary searched for the first update which breaks the build, it is left out and the rest is tried again.
+// go.mod ends up with every update except the culprits, go.mod.bak, go.sum.bak and go.mod.backup keep the original files.
+func (u *Updater) Bisect(path string, updates map[string]internal.CheckResult, options internal.UpdateOptions) (internal.BisectResult, error) {
+	result := internal.BisectResult{
+		Applied:  make(map[string]internal.CheckResult),
+		Culprits: make(map[string]internal.CheckResult),
+	}
+
+	if err := checkWritable(path); err != nil {
+		return result, err
+	}
+
+	dir, err := moduleDir(path)
+	if err != nil {
+		return result, err
+	}
+
+	b, err := newBisector(u.Ctx, dir, updates, options.Remove)
+	if err != nil {
+		return result, err
+	}
+
+	err = backup(dir)
+	if err != nil {
+		return result, err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, goModBackup), b.mod, 0666)
+	if err != nil {
+		return result, err
+	}
+
+	var names []string
+	for name, update := range updates {
+		if update.Updatable() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	baseline := false
+
+	for {
+		verifyErr, err := b.try(names)
+		if err != nil {
+			return result, b.abort(err)
+		}
+		if verifyErr == nil {
+			break
+		}
+
+		if !baseline {
+			baselineErr, err := b.try(nil)
+			if err != nil {
+				return result, b.abort(err)
+			}
+			if baselineErr != nil {
+				return result, b.abort(fmt.Errorf("build fails without updates: %w", baselineErr))
+			}
+
+			baseline = true
+		}
+
+		i, verifyErr, err := b.search(names, verifyErr)
+		if err != nil {
+			return result, b.abort(err)
+		}
+
+		culprit := updates[names[i]]
+		culprit.Error = verifyErr
+		result.Culprits[names[i]] = culprit
+
+		names = append(names[:i], names[i+1:]...)
+	}
+
+	for _, name := range names {
+		result.Applied[name] = updates[name]
+	}
+
+	return result, nil
+}
+
+// bisector tries subsets of updates on top of the original go.mod and go.sum.
+type bisector struct {
+	ctx     context.Context
+	dir     string
+	mod     []byte
+	sum     []byte
+	updates map[string]internal.CheckResult
+	remove  []string
+}
+
+func newBisector(ctx context.Context, dir string, updates map[string]internal.CheckResult, remove []string) (*bisector, error) {
+	b := &bisector{ctx: ctx, dir: dir, updates: updates, remove: remove}
+
+	var err error
+
+	b.mod, err = ioutil.ReadFile(filepath.Join(dir, goMod))
+	if err != nil {
+		return nil, err
+	}
+
+	b.sum, err = ioutil.ReadFile(filepath.Join(dir, goSum))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// try writes go.mod with the named updates and verifies it, a nil *VerifyError means the module builds.
+func (b *bisector) try(names []string) (*VerifyError, error) {
+	subset := make(map[string]internal.CheckResult, len(names))
+	for _, name := range names {
+		subset[name] = b.updates[name]
+	}
+
+	content, _, err := editGoMod(b.mod, subset, b.remove, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.reset()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(b.dir, goMod), content, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	return verify(b.ctx, b.dir), nil
+}
+
+// search returns index of the first update in names which breaks the build together with the updates before it.
+// names is known to fail with failure, the module is known to build without updates.
+func (b *bisector) search(names []string, failure *VerifyError) (int, *VerifyError, error) {
+	lo, hi := 0, len(names)
+
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+
+		verifyErr, err := b.try(names[:mid])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if verifyErr == nil {
+			lo = mid
+		} else {
+			hi, failure = mid, verifyErr
+		}
+	}
+
+	return hi - 1, failure, nil
+}
+
+// reset writes back the original go.mod and go.sum.
+func (b *bisector) reset() error {
+	err := ioutil.WriteFile(filepath.Join(b.dir, goMod), b.mod, 0666)
+	if err != nil {
+		return err
+	}
+
+	sumFile := filepath.Join(b.dir, goSum)
+	if b.sum == nil {
+		err = os.Remove(sumFile)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return ioutil.WriteFile(sumFile, b.sum, 0666)
+}
+
+// abort restores the original files and returns err.
+func (b *bisector) abort(err error) error {
+	resetErr := b.reset()
+	if resetErr != nil {
+		return fmt.Errorf("%v, restoring go.mod and go.sum failed: %w", err, resetErr)
+	}
+
+	return err
+}
@@ -0,0 +1,116 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/zip"
+)
+
+// writeProxyModule adds a module version with a single package to a file:// proxy in dir.
+func writeProxyModule(t *testing.T, dir, modulePath, version, source string) {
+	src, err := ioutil.TempDir("", "src")
+	require.NoError(t, err)
+	defer os.RemoveAll(src)
+
+	mod := []byte(fmt.Sprintf("module %s\n\ngo 1.15\n", modulePath))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, goMod), mod, 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "lib.go"), []byte(source), 0666))
+
+	versions := filepath.Join(dir, modulePath, "@v")
+	require.NoError(t, os.MkdirAll(versions, 0777))
+
+	list, err := os.OpenFile(filepath.Join(versions, "list"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	require.NoError(t, err)
+	fmt.Fprintln(list, version)
+	require.NoError(t, list.Close())
+
+	info := []byte(fmt.Sprintf(`{"Version":%q,"Time":"2020-01-01T00:00:00Z"}`, version))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(versions, version+".info"), info, 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(versions, version+".mod"), mod, 0666))
+
+	z, err := os.Create(filepath.Join(versions, version+".zip"))
+	require.NoError(t, err)
+	require.NoError(t, zip.CreateFromDir(z, module.Version{Path: modulePath, Version: version}, src))
+	require.NoError(t, z.Close())
+}
+
+// setGoEnv sets environment variables for the test and returns a func restoring them.
+func setGoEnv(t *testing.T, env map[string]string) func() {
+	old := make(map[string]string)
+
+	for key, value := range env {
+		old[key] = os.Getenv(key)
+		require.NoError(t, os.Setenv(key, value))
+	}
+
+	return func() {
+		for key, value := range old {
+			os.Setenv(key, value)
+		}
+	}
+}
+
+func TestUpdater_Bisect(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "bisect")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	proxy := filepath.Join(tmp, "proxy")
+	writeProxyModule(t, proxy, "example.com/good", "v1.0.0", "package good\n\nfunc Good() {}\n")
+	writeProxyModule(t, proxy, "example.com/good", "v1.1.0", "package good\n\nfunc Good() {}\n")
+	writeProxyModule(t, proxy, "example.com/bad", "v1.0.0", "package bad\n\nfunc Bad() {}\n")
+	writeProxyModule(t, proxy, "example.com/bad", "v1.1.0", "package bad\n\nfunc Renamed() {}\n")
+
+	viper.Set("proxy", "file://"+filepath.ToSlash(proxy))
+	defer viper.Set("proxy", "")
+
+	defer setGoEnv(t, map[string]string{
+		"GOSUMDB":    "off",
+		"GOFLAGS":    "-modcacherw",
+		"GOMODCACHE": filepath.Join(tmp, "cache"),
+	})()
+
+	project := filepath.Join(tmp, "project")
+	require.NoError(t, os.MkdirAll(project, 0777))
+
+	mod := []byte("module example.com/project\n\ngo 1.15\n\nrequire (\n\texample.com/bad v1.0.0\n\texample.com/good v1.0.0\n)\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(project, goMod), mod, 0666))
+
+	main := "package main\n\nimport (\n\t\"example.com/bad\"\n\t\"example.com/good\"\n)\n\nfunc main() {\n\tbad.Bad()\n\tgood.Good()\n}\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(project, "main.go"), []byte(main), 0666))
+
+	updates := map[string]internal.CheckResult{
+		"example.com/bad":  {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.1.0")},
+		"example.com/good": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.1.0")},
+	}
+
+	updater := Updater{Ctx: context.Background()}
+
+	result, err := updater.Bisect(project, updates, internal.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Contains(t, result.Applied, "example.com/good")
+	assert.NotContains(t, result.Applied, "example.com/bad")
+	require.Contains(t, result.Culprits, "example.com/bad")
+	assert.Error(t, result.Culprits["example.com/bad"].Error)
+
+	content, err := ioutil.ReadFile(filepath.Join(project, goMod))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "example.com/good v1.1.0")
+	assert.Contains(t, string(content), "example.com/bad v1.0.0")
+
+	backup, err := ioutil.ReadFile(filepath.Join(project, goMod+backupSuffix))
+	require.NoError(t, err)
+	assert.Equal(t, mod, backup)
+}
@@ -0,0 +1,172 @@
+package module
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/beatlabs/gomodctl/internal/httpclient"
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"github.com/spf13/viper"
+	"golang.org/x/mod/module"
+)
+
+// DefaultCacheTTL is used when cache_ttl isn't configured.
+const DefaultCacheTTL = time.Hour
+
+// versionCache stores available versions of modules on disk.
+type versionCache struct {
+	name string
+	dir  string
+	ttl  time.Duration
+	// proxy is the proxy list the entries are resolved with, entries of other proxy lists are kept apart.
+	proxy string
+}
+
+// cacheEntry is the content of a cache file.
+// Version lists fetched from a proxy keep its URL and validators, a stale entry is revalidated by a conditional request.
+type cacheEntry struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Versions  []string  `json:"versions"`
+	URL       string    `json:"url,omitempty"`
+	httpclient.Validators
+}
+
+// newVersionCache creates the cache configured by no_cache and cache_ttl, nil means caching is disabled.
+func newVersionCache() *versionCache {
+	return newCache("versions")
+}
+
+// newRetractionCache creates the cache of retracted versions, see newVersionCache.
+func newRetractionCache() *versionCache {
+	return newCache("retractions")
+}
+
+// newDeprecationCache creates the cache of deprecation messages of modules, see newVersionCache.
+func newDeprecationCache() *versionCache {
+	return newCache("deprecations")
+}
+
+// newTimeCache creates the cache of publish times of versions, see newVersionCache.
+func newTimeCache() *versionCache {
+	return newCache("times")
+}
+
+// newGoVersionCache creates the cache of go directives of versions, see newVersionCache.
+func newGoVersionCache() *versionCache {
+	return newCache("go")
+}
+
+func newCache(name string) *versionCache {
+	if viper.GetBool("no_cache") {
+		return nil
+	}
+
+	dir, err := CacheDir()
+	if err != nil {
+		return nil
+	}
+
+	ttl := viper.GetDuration("cache_ttl")
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &versionCache{name: name, dir: filepath.Join(dir, name), ttl: ttl, proxy: GoProxy()}
+}
+
+// CacheDir returns the cache directory of gomodctl, $XDG_CACHE_HOME/gomodctl or $HOME/.cache/gomodctl.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "gomodctl"), nil
+}
+
+// file returns the cache file of the module, a directory per proxy list keeps a module resolved by another
+// proxy, e.g. a private mirror, from being a hit.
+func (c *versionCache) file(modulePath string) (string, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+
+	dir := c.dir
+	if c.proxy != "" {
+		sum := sha256.Sum256([]byte(c.proxy))
+		dir = filepath.Join(dir, hex.EncodeToString(sum[:8]))
+	}
+
+	return filepath.Join(dir, filepath.FromSlash(escaped)+".json"), nil
+}
+
+// get returns cached versions of the module, false if there is no fresh entry.
+func (c *versionCache) get(modulePath string) ([]string, bool) {
+	entry, fresh, _ := c.lookup(modulePath)
+	if !fresh {
+		return nil, false
+	}
+
+	return entry.Versions, true
+}
+
+// lookup returns the cached entry of the module, fresh is false if it is older than the TTL.
+func (c *versionCache) lookup(modulePath string) (entry cacheEntry, fresh bool, ok bool) {
+	file, err := c.file(modulePath)
+	if err != nil {
+		return cacheEntry{}, false, false
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		logger.Debugf("%s cache miss for %s", c.name, modulePath)
+		return cacheEntry{}, false, false
+	}
+
+	if err := json.Unmarshal(content, &entry); err != nil {
+		logger.Debugf("%s cache miss for %s, entry is invalid", c.name, modulePath)
+		return cacheEntry{}, false, false
+	}
+
+	if time.Since(entry.FetchedAt) > c.ttl {
+		logger.Debugf("%s cache miss for %s, entry is stale", c.name, modulePath)
+		return entry, false, true
+	}
+
+	logger.Debugf("%s cache hit for %s", c.name, modulePath)
+
+	return entry, true, true
+}
+
+// set writes versions of the module into the cache.
+func (c *versionCache) set(modulePath string, versions []string) error {
+	return c.setEntry(modulePath, cacheEntry{Versions: versions})
+}
+
+// setEntry writes the entry of the module into the cache, it is fetched now.
+func (c *versionCache) setEntry(modulePath string, entry cacheEntry) error {
+	file, err := c.file(modulePath)
+	if err != nil {
+		return err
+	}
+
+	entry.FetchedAt = time.Now()
+
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(file, content)
+}
@@ -0,0 +1,96 @@
+package module
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionCache(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cache := &versionCache{dir: tempDir, ttl: time.Hour}
+
+	_, ok := cache.get("github.com/Masterminds/semver")
+	assert.False(t, ok)
+
+	assert.NoError(t, cache.set("github.com/Masterminds/semver", []string{"v1.4.0", "v1.5.0"}))
+
+	versions, ok := cache.get("github.com/Masterminds/semver")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"v1.4.0", "v1.5.0"}, versions)
+
+	cache.ttl = -time.Second
+	_, ok = cache.get("github.com/Masterminds/semver")
+	assert.False(t, ok)
+}
+
+func TestVersionCache_Proxy(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	public := &versionCache{dir: tempDir, ttl: time.Hour, proxy: "https://proxy.golang.org"}
+	mirror := &versionCache{dir: tempDir, ttl: time.Hour, proxy: "https://goproxy.example.com,direct"}
+
+	assert.NoError(t, public.set("example.com/a", []string{"v1.0.0"}))
+	assert.NoError(t, mirror.set("example.com/a", []string{"v1.0.0", "v1.1.0"}))
+
+	versions, ok := public.get("example.com/a")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"v1.0.0"}, versions, "entries of another proxy list aren't a hit")
+
+	versions, ok = mirror.get("example.com/a")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"v1.0.0", "v1.1.0"}, versions)
+}
+
+func TestVersionResolver_Revalidate(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Mon, 01 Feb 2021 00:00:00 GMT" {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Feb 2021 00:00:00 GMT")
+		w.Write([]byte("v1.0.0\nv1.1.0\n"))
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	defer viper.Set("proxy", nil)
+
+	resolver := newVersionResolver(context.Background(), "")
+	resolver.cache = &versionCache{name: "versions", dir: tempDir, ttl: -time.Second}
+
+	for i := 0; i < 2; i++ {
+		versions, err := resolver.rawVersions("example.com/a")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1.0.0", "v1.1.0"}, versions)
+	}
+
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, notModified, "the stale entry is revalidated")
+
+	entry, _, ok := resolver.cache.lookup("example.com/a")
+	assert.True(t, ok)
+	assert.Equal(t, server.URL+"/example.com/a/@v/list", entry.URL)
+	assert.Equal(t, `"v1"`, entry.ETag)
+}
@@ -0,0 +1,60 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// changedModules returns changes of modules whose require lines in go.mod of the module in the given path differ
+// from go.mod of the git revision, e.g. HEAD, added modules included. Removed modules aren't required anymore and are left out.
+func changedModules(ctx context.Context, path, revision string) (map[string]internal.DiffResult, error) {
+	dir, err := moduleDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := ModParser{ctx: ctx}
+
+	current, err := parser.ParseFile(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", "show", revision+":./"+goMod)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading go.mod of git revision %s: %w: %s", revision, err, strings.TrimSpace(stderr.String()))
+	}
+
+	previous, err := parseRequirements(revision+":"+goMod, out)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]internal.DiffResult)
+
+	for modulePath, result := range diffModules(previous, current) {
+		if result.Change != internal.DiffRemoved {
+			changed[modulePath] = result
+		}
+	}
+
+	return changed, nil
+}
+
+// inDiff reports whether the module is one of the changed modules.
+func inDiff(changed map[string]internal.DiffResult, modulePath string) bool {
+	_, ok := changed[modulePath]
+
+	return ok
+}
@@ -0,0 +1,64 @@
+package module
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedModules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "changed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	writeGoMod := func(requires string) {
+		content := "module example.com/changed\n\ngo 1.15\n\nrequire (\n" + requires + ")\n"
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, goMod), []byte(content), 0666))
+	}
+
+	writeGoMod("\texample.com/a v1.0.0\n\texample.com/b v1.0.0\n\texample.com/c v1.0.0\n")
+
+	git("init", "-q")
+	git("add", goMod)
+	git("commit", "-q", "-m", "init")
+
+	changed, err := changedModules(context.Background(), dir, "HEAD")
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	writeGoMod("\texample.com/a v1.1.0\n\texample.com/c v1.0.0 // indirect\n\texample.com/d v1.0.0\n")
+
+	changed, err = changedModules(context.Background(), dir, "HEAD")
+	require.NoError(t, err)
+	assert.Len(t, changed, 2)
+	assert.Equal(t, internal.DiffChanged, changed["example.com/a"].Change)
+	assert.Equal(t, "v1.0.0", changed["example.com/a"].OldVersion.Original())
+	assert.Equal(t, internal.DiffAdded, changed["example.com/d"].Change)
+
+	git("branch", "base")
+	git("checkout", "-q", "-b", "feature")
+	git("commit", "-q", "-a", "-m", "update")
+	writeGoMod("\texample.com/a v0.9.0\n\texample.com/c v1.0.0 // indirect\n\texample.com/d v1.0.0\n")
+
+	changed, err = changedModules(context.Background(), dir, "base")
+	require.NoError(t, err)
+	assert.Len(t, changed, 2)
+	assert.True(t, changed["example.com/a"].Downgrade(), "v0.9.0 is lower than v1.0.0 of the base")
+
+	_, err = changedModules(context.Background(), dir, "does-not-exist")
+	assert.Error(t, err)
+}
@@ -3,18 +3,34 @@ package module
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/logger"
 	"github.com/spf13/viper"
+	"golang.org/x/mod/module"
 )
 
 // ErrNoVersionAvailable is returned when no version found for a module.
 var ErrNoVersionAvailable = errors.New("no version available")
 
 // ErrModuleIgnored is returned when a module is ignored for version check.
-var ErrModuleIgnored = errors.New("module ignored")
+var ErrModuleIgnored = internal.ErrModuleIgnored
+
+// ErrModuleTimeout is returned when a module isn't resolved within CheckOptions.ModuleTimeout.
+var ErrModuleTimeout = internal.ErrModuleTimeout
+
+// ErrPrivateModule is returned when versions of a module matched by GOPRIVATE or GONOSUMDB can't be resolved.
+var ErrPrivateModule = errors.New("private module")
 
 // Checker is exported
 type Checker struct {
@@ -22,8 +38,384 @@ type Checker struct {
 }
 
 // Check is exported.
-func (c *Checker) Check(path string) (map[string]internal.CheckResult, error) {
-	return getModAndFilter(c.Ctx, path, getLatestVersion)
+// If path contains a go.work, every module used by the workspace is checked and results of a dependency
+// required by several of them are merged, see mergeResults.
+// If path is a glob pattern, e.g. ./services/*, every module in a matching directory is checked, see checkGlob.
+// If options.Modules is set, path is ignored and only they are checked.
+// If the context is done before every module is resolved, the results resolved so far are returned along with the error.
+func (c *Checker) Check(path string, options internal.CheckOptions) (map[string]internal.CheckResult, error) {
+	filter := getFilter(options)
+
+	if options.Modules != nil {
+		return getModAndFilter(c.Ctx, "", options, filter, true)
+	}
+
+	if isGlob(path) {
+		return c.checkGlob(path, options, filter)
+	}
+
+	path, err := absDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	uses, err := parseWorkspace(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if uses == nil {
+		return getModAndFilter(c.Ctx, path, options, filter, true)
+	}
+
+	checkResults := make(map[string]internal.CheckResult)
+
+	for _, use := range uses {
+		results, err := getModAndFilter(c.Ctx, filepath.Join(path, use), options, filter, true)
+		if err != nil && results == nil {
+			return nil, fmt.Errorf("%s: %w", use, err)
+		}
+
+		for name, result := range results {
+			if merged, ok := checkResults[name]; ok {
+				result = mergeResults(merged, result)
+			}
+
+			checkResults[name] = result
+		}
+
+		// an aborted check returns what was resolved so far, modules of the remaining uses aren't listed.
+		if err != nil {
+			return checkResults, fmt.Errorf("%s: %w", use, err)
+		}
+	}
+
+	return checkResults, nil
+}
+
+// checkGlob checks the modules in directories matching the pattern and merges their results like those of a workspace.
+// A module which can't be checked is skipped with a warning, so the others are still reported.
+func (c *Checker) checkGlob(pattern string, options internal.CheckOptions, filter versionFilter) (map[string]internal.CheckResult, error) {
+	dirs, err := globModules(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	checkResults := make(map[string]internal.CheckResult)
+	failed := 0
+
+	for _, dir := range dirs {
+		results, err := getModAndFilter(c.Ctx, dir, options, filter, true)
+		if err != nil && results == nil {
+			if c.Ctx.Err() != nil {
+				return nil, err
+			}
+
+			logger.Warnf("%s: %v", dir, err)
+			failed++
+			continue
+		}
+
+		for name, result := range results {
+			if merged, ok := checkResults[name]; ok {
+				result = mergeResults(merged, result)
+			}
+
+			checkResults[name] = result
+		}
+
+		if err != nil {
+			return checkResults, fmt.Errorf("%s: %w", dir, err)
+		}
+	}
+
+	if failed == len(dirs) {
+		return nil, fmt.Errorf("none of the %d modules matching %s can be checked", len(dirs), pattern)
+	}
+
+	return checkResults, nil
+}
+
+// isGlob reports whether the path contains glob metacharacters, see filepath.Match.
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// globModules returns directories of modules matching the pattern, a match is either a directory containing go.mod
+// or a go.mod file itself. Other matches are left out.
+func globModules(pattern string) ([]string, error) {
+	pattern, err := expandHome(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := filepath.Glob(strings.TrimRight(pattern, `/\`))
+	if err != nil {
+		return nil, fmt.Errorf("invalid path pattern %s: %w", pattern, err)
+	}
+
+	var dirs []string
+
+	for _, match := range matches {
+		if filepath.Base(match) == goMod {
+			match = filepath.Dir(match)
+		}
+
+		if _, err := os.Stat(filepath.Join(match, goMod)); err == nil {
+			dirs = append(dirs, match)
+		}
+	}
+
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no go.mod found in directories matching %s", pattern)
+	}
+
+	return dirs, nil
+}
+
+// mergeResults merges results of a dependency required by several modules of a workspace.
+// The oldest local version is the one updates and advisories are reported against, every distinct one is kept in LocalVersions.
+// The newest latest version of both is kept, so is an error only if both failed.
+func mergeResults(a, b internal.CheckResult) internal.CheckResult {
+	merged := a
+	if a.LocalVersion == nil || (b.LocalVersion != nil && b.LocalVersion.LessThan(a.LocalVersion)) {
+		merged.LocalVersion, merged.LocalTime, merged.Pseudo = b.LocalVersion, b.LocalTime, b.Pseudo
+		merged.Advisories, merged.SecureVersion = b.Advisories, b.SecureVersion
+	}
+
+	latest := a
+	if a.Error != nil || (b.Error == nil && b.LatestVersion != nil && (a.LatestVersion == nil || b.LatestVersion.GreaterThan(a.LatestVersion))) {
+		latest = b
+	}
+	merged.LatestVersion, merged.LatestPath, merged.LatestTime, merged.Error = latest.LatestVersion, latest.LatestPath, latest.LatestTime, latest.Error
+	merged.SkippedVersion, merged.SkippedGo = latest.SkippedVersion, latest.SkippedGo
+	merged.Untagged = a.Untagged && b.Untagged
+
+	merged.Indirect = a.Indirect && b.Indirect
+	merged.Replaced = a.Replaced || b.Replaced
+	merged.Retracted = a.Retracted || b.Retracted
+	if merged.Deprecated == "" {
+		merged.Deprecated = b.Deprecated
+	}
+
+	merged.LocalVersions = nil
+	seen := make(map[string]bool)
+
+	for _, result := range []internal.CheckResult{a, b} {
+		versions := result.LocalVersions
+		if len(versions) == 0 && result.LocalVersion != nil {
+			versions = []*semver.Version{result.LocalVersion}
+		}
+
+		for _, v := range versions {
+			if !seen[v.Original()] {
+				seen[v.Original()] = true
+				merged.LocalVersions = append(merged.LocalVersions, v)
+			}
+		}
+	}
+
+	sort.Sort(semver.Collection(merged.LocalVersions))
+
+	if len(merged.LocalVersions) < 2 {
+		merged.LocalVersions = nil
+	}
+
+	return merged
+}
+
+// CheckModule resolves the latest version of a single module without reading go.mod.
+// Version is optional, if it is set the update is computed relative to it.
+func (c *Checker) CheckModule(modulePath, version string, options internal.CheckOptions) (internal.CheckResult, error) {
+	result := PackageResult{Path: modulePath}
+
+	if version != "" {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			return internal.CheckResult{}, fmt.Errorf("invalid version %q: %w", version, err)
+		}
+
+		result.LocalVersion = v
+	} else if options.Scope != internal.ScopeLatest {
+		return internal.CheckResult{}, errors.New("a version is required to limit the update scope, use module@version")
+	}
+
+	privatePatterns, err := getPrivatePatterns(c.Ctx)
+	if err != nil {
+		return internal.CheckResult{}, err
+	}
+
+	policy, err := getVersionPolicy()
+	if err != nil {
+		return internal.CheckResult{}, err
+	}
+
+	resolver := newVersionResolver(c.Ctx, privatePatterns)
+	resolver.successors = true
+	resolver.goVersion = getGoVersion(c.Ctx, options.GoVersion)
+	resolver.retries = options.Retries
+
+	if options.NoCache {
+		resolver.disableCache()
+	}
+
+	checkResult := internal.CheckResult{LocalVersion: result.LocalVersion}
+	resolveLatest(resolver, result, privatePatterns, policy.filter(modulePath, getFilter(options)), policy.filter(modulePath, getWantedFilter(options)), &checkResult)
+	resolveTimes(resolver, result, &checkResult)
+
+	if options.Secure && result.LocalVersion != nil {
+		checkResults := map[string]internal.CheckResult{modulePath: checkResult}
+		if err := resolveSecure(resolver, []PackageResult{result}, checkResults); err != nil {
+			return internal.CheckResult{}, err
+		}
+
+		checkResult = checkResults[modulePath]
+	}
+
+	return checkResult, nil
+}
+
+// listedModules returns the modules of CheckOptions.Modules as direct requirements, each of them can be listed once.
+func listedModules(modules []internal.Requirement, scope internal.UpdateScope) ([]PackageResult, error) {
+	results := make([]PackageResult, 0, len(modules))
+	seen := make(map[string]bool, len(modules))
+
+	for _, m := range modules {
+		if seen[m.Path] {
+			return nil, fmt.Errorf("%s is listed more than once", m.Path)
+		}
+
+		seen[m.Path] = true
+		result := PackageResult{Path: m.Path}
+
+		if m.Version != "" {
+			v, err := semver.NewVersion(m.Version)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version %q of %s: %w", m.Version, m.Path, err)
+			}
+
+			result.LocalVersion = v
+		} else if scope != internal.ScopeLatest {
+			return nil, fmt.Errorf("a version of %s is required to limit the update scope, use module@version", m.Path)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Versions returns available versions of the module sorted from the newest, retracted versions are left out.
+func (c *Checker) Versions(modulePath string) ([]*semver.Version, error) {
+	privatePatterns, err := getPrivatePatterns(c.Ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := newVersionResolver(c.Ctx, privatePatterns)
+
+	versions, err := resolver.Versions(modulePath)
+	if err != nil {
+		if module.MatchPrefixPatterns(privatePatterns, modulePath) {
+			return nil, ErrPrivateModule
+		}
+
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, ErrNoVersionAvailable
+	}
+
+	versions = withoutRetracted(versions, resolver.Retracted(modulePath, versions))
+
+	sort.Sort(sort.Reverse(semver.Collection(versions)))
+
+	return versions, nil
+}
+
+// Severities returns the highest rating of advisories affecting the local version of each given module,
+// modules without known vulnerabilities are left out. Replaced modules aren't looked up.
+func (c *Checker) Severities(results map[string]internal.CheckResult) (map[string]internal.Severity, error) {
+	return severities(c.Ctx, results)
+}
+
+// Required returns sorted paths of modules required by go.mod in the given path, only the file is read.
+func (c *Checker) Required(path string) ([]string, error) {
+	parser := ModParser{ctx: c.Ctx}
+
+	results, err := parser.ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(results))
+	for _, result := range results {
+		paths = append(paths, result.Path)
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// versionFilter picks the update candidate of the local version from available versions.
+type versionFilter func(*semver.Version, []*semver.Version) (*semver.Version, error)
+
+// getFilter returns version filter for given check options, see preferStable.
+func getFilter(options internal.CheckOptions) versionFilter {
+	var filter versionFilter
+
+	switch options.Scope {
+	case internal.ScopeMajor:
+		filter = getLatestMajorVersion
+	case internal.ScopeMinor:
+		filter = getLatestMinorVersion
+	case internal.ScopePatch:
+		filter = getLatestPatchVersion
+	default:
+		filter = getLatestVersion
+	}
+
+	if options.Prerelease {
+		return filter
+	}
+
+	return preferStable(filter)
+}
+
+// getWantedFilter returns the filter of CheckResult.WantedVersion, nil unless options.Wanted is set.
+func getWantedFilter(options internal.CheckOptions) versionFilter {
+	if !options.Wanted {
+		return nil
+	}
+
+	if options.Prerelease {
+		return getWantedVersion
+	}
+
+	return preferStable(getWantedVersion)
+}
+
+// preferStable applies the filter to stable versions only,
+// prereleases are considered only if no stable version matches the filter
+// or the local prerelease is newer than the latest stable version.
+func preferStable(filter versionFilter) versionFilter {
+	return func(localVersion *semver.Version, versions []*semver.Version) (*semver.Version, error) {
+		stable := make([]*semver.Version, 0, len(versions))
+		for _, v := range versions {
+			if v.Prerelease() == "" {
+				stable = append(stable, v)
+			}
+		}
+
+		latest, err := filter(localVersion, stable)
+		if err == nil && (localVersion == nil || localVersion.Prerelease() == "" || !latest.LessThan(localVersion)) {
+			return latest, nil
+		}
+
+		return filter(localVersion, versions)
+	}
 }
 
 func getLatestVersion(_ *semver.Version, versions []*semver.Version) (*semver.Version, error) {
@@ -38,49 +430,484 @@ func getLatestVersion(_ *semver.Version, versions []*semver.Version) (*semver.Ve
 	return lastVersion, nil
 }
 
-func getModAndFilter(ctx context.Context, path string, filter func(*semver.Version, []*semver.Version) (*semver.Version, error)) (map[string]internal.CheckResult, error) {
+// getLatestMajorVersion returns the latest version only if its major is higher than the local one.
+func getLatestMajorVersion(localVersion *semver.Version, versions []*semver.Version) (*semver.Version, error) {
+	return getLatestVersionWithin(versions, func(v *semver.Version) bool {
+		return v.Major() > localVersion.Major()
+	})
+}
+
+// getLatestMinorVersion returns the latest version within the major of the local version.
+func getLatestMinorVersion(localVersion *semver.Version, versions []*semver.Version) (*semver.Version, error) {
+	return getLatestVersionWithin(versions, func(v *semver.Version) bool {
+		return v.Major() == localVersion.Major()
+	})
+}
+
+// getLatestPatchVersion returns the latest version within the major.minor of the local version.
+func getLatestPatchVersion(localVersion *semver.Version, versions []*semver.Version) (*semver.Version, error) {
+	return getLatestVersionWithin(versions, func(v *semver.Version) bool {
+		return v.Major() == localVersion.Major() && v.Minor() == localVersion.Minor()
+	})
+}
+
+// getWantedVersion returns the latest version within the major of the local version,
+// or within its major.minor for v0 versions whose minor releases may break compatibility.
+func getWantedVersion(localVersion *semver.Version, versions []*semver.Version) (*semver.Version, error) {
+	if localVersion.Major() == 0 {
+		return getLatestPatchVersion(localVersion, versions)
+	}
+
+	return getLatestMinorVersion(localVersion, versions)
+}
+
+func getLatestVersionWithin(versions []*semver.Version, within func(*semver.Version) bool) (*semver.Version, error) {
+	var candidates []*semver.Version
+
+	for _, version := range versions {
+		if within(version) {
+			candidates = append(candidates, version)
+		}
+	}
+
+	return getLatestVersion(nil, candidates)
+}
+
+// getModAndFilter resolves the latest versions of modules required by go.mod in path.
+// report enables what only check reports: major version suffix successors as candidates, see resolveLatest, and publish times.
+func getModAndFilter(ctx context.Context, path string, options internal.CheckOptions, filter versionFilter, report bool) (map[string]internal.CheckResult, error) {
+	ignoredModules, err := getIgnoredModules(options.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := getVersionPolicy()
+	if err != nil {
+		return nil, err
+	}
+
 	parser := ModParser{ctx: ctx}
 
-	results, err := parser.Parse(path)
+	var results []PackageResult
+	if options.Modules != nil {
+		results, err = listedModules(options.Modules, options.Scope)
+	} else {
+		results, err = parseRequired(&parser, path)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	ignoredModules := getIgnoredModules()
+	privatePatterns, err := getPrivatePatterns(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	checkResults := make(map[string]internal.CheckResult)
+	resolver := newVersionResolver(ctx, privatePatterns)
+	resolver.successors = report
+	resolver.goVersion = getGoVersion(ctx, options.GoVersion)
+	resolver.retries = options.Retries
+
+	if options.NoCache {
+		resolver.disableCache()
+	}
+
+	wanted := getWantedFilter(options)
+
+	var vendored map[string]PackageResult
+	if options.Vendor {
+		vendored, err = parser.ParseVendor(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var changed map[string]internal.DiffResult
+	if options.Changed || options.Base != "" {
+		revision := "HEAD"
+		if options.Base != "" {
+			revision = options.Base
+		}
+
+		changed, err = changedModules(ctx, path, revision)
+		if err != nil {
+			return nil, err
+		}
+	}
 
+	queue := make([]PackageResult, 0, len(results))
 	for _, result := range results {
-		checkResult := internal.CheckResult{
-			LocalVersion: result.LocalVersion,
+		if (!options.DirectOnly || !result.Indirect) && (changed == nil || inDiff(changed, result.Path)) {
+			queue = append(queue, result)
 		}
+	}
 
-		_, isIgnored := ignoredModules[result.Path]
-		if isIgnored {
-			checkResult.Error = ErrModuleIgnored
-		} else {
-			latestVersion, err := filter(result.LocalVersion, result.AvailableVersions)
+	progress := options.Progress
+	if progress == nil {
+		progress = func(int, int) {}
+	}
 
-			if err != nil {
-				checkResult.Error = err
-			}
+	checkResults := make(map[string]internal.CheckResult)
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	jobs := make(chan PackageResult)
+
+	for i := 0; i < getConcurrency(); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for result := range jobs {
+				checkResult := internal.CheckResult{
+					LocalVersion: result.LocalVersion,
+					Replaced:     result.Replaced,
+					Indirect:     result.Indirect,
+				}
 
-			if latestVersion != nil {
-				checkResult.LatestVersion = latestVersion
+				if diff, ok := changed[result.Path]; ok && options.Base != "" {
+					checkResult.Base = &diff
+				}
+
+				if err := vendorMismatch(vendored, result); err != nil {
+					checkResult.Error = err
+				} else if ignoredModules.isIgnored(result.Path) {
+					checkResult.Error = ErrModuleIgnored
+				} else {
+					resolveModule(ctx, resolver, options.ModuleTimeout, func(resolver *versionResolver) {
+						resolveLatest(resolver, result, privatePatterns, policy.filter(result.Path, filter), policy.filter(result.Path, wanted), &checkResult)
+
+						if report || options.Times {
+							resolveTimes(resolver, result, &checkResult)
+						}
+					}, &checkResult)
+				}
+
+				mu.Lock()
+				checkResults[result.Path] = checkResult
+				progress(len(checkResults), len(queue))
+				mu.Unlock()
 			}
+		}()
+	}
+
+	progress(0, len(queue))
+
+loop:
+	for _, result := range queue {
+		select {
+		case jobs <- result:
+		case <-ctx.Done():
+			break loop
 		}
+	}
 
-		checkResults[result.Path] = checkResult
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return partialResults(queue, checkResults, err)
+	}
+
+	if options.Secure {
+		if err := resolveSecure(resolver, queue, checkResults); err != nil {
+			return nil, err
+		}
 	}
 
 	return checkResults, nil
 }
 
+// resolveModule calls resolve with a resolver whose context ends after the timeout, if resolving fails because the
+// timeout elapsed, the check result fails with ErrModuleTimeout. The context of the whole check isn't affected,
+// if it ends first the error of the result stays as it is. Zero timeout means no limit.
+func resolveModule(ctx context.Context, resolver *versionResolver, timeout time.Duration, resolve func(*versionResolver), checkResult *internal.CheckResult) {
+	if timeout <= 0 {
+		resolve(resolver)
+		return
+	}
+
+	moduleCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resolve(resolver.withContext(moduleCtx))
+
+	if checkResult.Error != nil && moduleCtx.Err() != nil && ctx.Err() == nil {
+		checkResult.Error = fmt.Errorf("%w after %s", internal.ErrModuleTimeout, timeout)
+	}
+}
+
+// parseRequired returns modules required by go.mod in path, see ModParser.Parse.
+// go list fails as a whole if a single module of the graph can't be loaded, e.g. a version which doesn't exist,
+// then requirements are read from go.mod itself, so the others are still checked and the failing one reports its error.
+func parseRequired(parser *ModParser, path string) ([]PackageResult, error) {
+	results, err := parser.Parse(path)
+	if err == nil || parser.ctx.Err() != nil {
+		return results, err
+	}
+
+	required, fileErr := parser.ParseFile(path)
+	if fileErr != nil {
+		return nil, err
+	}
+
+	logger.Warnf("module graph not loaded, checking requirements of go.mod only: %v", err)
+
+	results = required[:0]
+	for _, result := range required {
+		// like Parse, local path replacements can't be checked against a registry.
+		if result.LocalVersion != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// partialResults completes results of an aborted check, modules which weren't resolved fail with the context error.
+// The results are returned along with the error, so callers can still report what was resolved.
+func partialResults(queue []PackageResult, checkResults map[string]internal.CheckResult, err error) (map[string]internal.CheckResult, error) {
+	resolved := 0
+
+	for _, result := range queue {
+		checkResult, ok := checkResults[result.Path]
+		if ok && !errors.Is(checkResult.Error, err) {
+			resolved++
+			continue
+		}
+
+		checkResults[result.Path] = internal.CheckResult{
+			LocalVersion: result.LocalVersion,
+			Replaced:     result.Replaced,
+			Indirect:     result.Indirect,
+			Error:        err,
+		}
+	}
+
+	return checkResults, fmt.Errorf("%w, %d of %d modules resolved", err, resolved, len(queue))
+}
+
+// resolveLatest resolves available versions of the module and filters them into the check result, retracted versions are never the latest.
+// If the resolver resolves successors, versions of modules with higher major version suffixes are candidates too,
+// LatestPath is the module path of the latest version if it belongs to one of them.
+// It also reports whether the local version is retracted.
+// If the resolver has a Go version, candidates whose go.mod requires a newer Go are skipped, the newest of them is
+// reported as SkippedVersion, and if every update is skipped the local version is the latest.
+// A non-nil wanted filter resolves WantedVersion from the same versions, a failure of it leaves WantedVersion nil.
+func resolveLatest(resolver *versionResolver, result PackageResult, privatePatterns string, filter, wanted versionFilter, checkResult *internal.CheckResult) {
+	if err := resolver.ctx.Err(); err != nil {
+		checkResult.Error = err
+		return
+	}
+
+	isPrivate := module.MatchPrefixPatterns(privatePatterns, result.ResolvePath())
+
+	versions, err := resolver.RetriedVersions(result.ResolvePath())
+	if err != nil {
+		checkResult.Error = err
+		if isPrivate {
+			checkResult.Error = ErrPrivateModule
+		}
+
+		return
+	}
+
+	if len(versions) == 0 && isPrivate {
+		checkResult.Error = ErrPrivateModule
+		return
+	}
+
+	retracted := resolver.Retracted(result.ResolvePath(), versions)
+	versions = withoutRetracted(versions, retracted)
+
+	checkResult.Retracted = result.LocalVersion != nil && retracted[result.LocalVersion.Original()]
+	checkResult.Deprecated = resolver.Deprecated(result.ResolvePath(), versions)
+	checkResult.Pseudo = result.LocalVersion != nil && internal.IsPseudoVersion(result.LocalVersion.Original())
+
+	paths := make(map[*semver.Version]string)
+
+	if resolver.successors && !result.Replaced {
+		known := versions
+		if result.LocalVersion != nil {
+			known = append([]*semver.Version{result.LocalVersion}, versions...)
+		}
+
+		versions = withSuccessors(versions, resolver.Successors(result.Path, known), paths)
+	}
+
+	// The proxy lists tagged versions only, a module without any is reported as untagged rather than without a version.
+	if len(versions) == 0 && checkResult.Pseudo {
+		checkResult.Untagged = true
+		checkResult.LatestVersion = result.LocalVersion
+		return
+	}
+
+	latest, skipped, skippedGo, err := filterSupported(resolver, result, filter, versions, paths)
+	checkResult.SkippedVersion, checkResult.SkippedGo = skipped, skippedGo
+
+	if err != nil {
+		checkResult.Error = err
+		return
+	}
+
+	checkResult.LatestVersion, checkResult.LatestPath = latest, paths[latest]
+
+	if wanted != nil && result.LocalVersion != nil && !result.Replaced {
+		checkResult.WantedVersion, _, _, _ = filterSupported(resolver, result, wanted, versions, paths)
+	}
+}
+
+// filterSupported applies the filter to the versions, candidates whose go.mod requires a newer Go than the resolver
+// are skipped, see resolveLatest. The newest skipped candidate and the Go version it requires are returned too.
+func filterSupported(resolver *versionResolver, result PackageResult, filter versionFilter, versions []*semver.Version, paths map[*semver.Version]string) (latest, skipped *semver.Version, skippedGo string, err error) {
+	latest, err = filter(result.LocalVersion, versions)
+
+	for err == nil && resolver.goVersion != "" && (result.LocalVersion == nil || latest.GreaterThan(result.LocalVersion)) {
+		latestPath := paths[latest]
+		if latestPath == "" {
+			latestPath = result.ResolvePath()
+		}
+
+		goVersion := resolver.RequiredGo(latestPath, latest.Original())
+		if compareGoVersions(goVersion, resolver.goVersion) <= 0 {
+			break
+		}
+
+		if skipped == nil {
+			skipped, skippedGo = latest, goVersion
+		}
+
+		versions = without(versions, latest)
+		latest, err = filter(result.LocalVersion, versions)
+
+		if err != nil && result.LocalVersion != nil {
+			latest, err = result.LocalVersion, nil
+		}
+	}
+
+	return latest, skipped, skippedGo, err
+}
+
+// resolveTimes sets publish times of the local and latest versions of the check result.
+// Nothing is resolved for failed results, e.g. a private module which can't be reached.
+func resolveTimes(resolver *versionResolver, result PackageResult, checkResult *internal.CheckResult) {
+	if checkResult.Error != nil {
+		return
+	}
+
+	if checkResult.LatestPath != "" {
+		checkResult.LocalTime = resolver.Times(result.ResolvePath(), checkResult.LocalVersion)[versionString(checkResult.LocalVersion)]
+		checkResult.LatestTime = resolver.Times(checkResult.LatestPath, checkResult.LatestVersion)[versionString(checkResult.LatestVersion)]
+
+		return
+	}
+
+	times := resolver.Times(result.ResolvePath(), checkResult.LocalVersion, checkResult.LatestVersion)
+	checkResult.LocalTime = times[versionString(checkResult.LocalVersion)]
+	checkResult.LatestTime = times[versionString(checkResult.LatestVersion)]
+}
+
+// versionString returns the original version, empty for nil.
+func versionString(v *semver.Version) string {
+	if v == nil {
+		return ""
+	}
+
+	return v.Original()
+}
+
+// withSuccessors adds versions of the successor modules to versions and records their module paths.
+// +incompatible versions of majors which are published as a successor module are left out, they are superseded by it.
+func withSuccessors(versions []*semver.Version, successors map[string][]*semver.Version, paths map[*semver.Version]string) []*semver.Version {
+	if len(successors) == 0 {
+		return versions
+	}
+
+	var lowest int64 = -1
+
+	for path, successorVersions := range successors {
+		for _, v := range successorVersions {
+			if lowest < 0 || v.Major() < lowest {
+				lowest = v.Major()
+			}
+
+			paths[v] = path
+		}
+	}
+
+	result := make([]*semver.Version, 0, len(versions))
+
+	for _, v := range versions {
+		if v.Metadata() == "incompatible" && v.Major() >= lowest {
+			continue
+		}
+
+		result = append(result, v)
+	}
+
+	for v := range paths {
+		result = append(result, v)
+	}
+
+	return result
+}
+
+// without returns versions except the given one.
+func without(versions []*semver.Version, version *semver.Version) []*semver.Version {
+	result := make([]*semver.Version, 0, len(versions))
+
+	for _, v := range versions {
+		if v != version {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// withoutRetracted returns versions which aren't retracted.
+func withoutRetracted(versions []*semver.Version, retracted map[string]bool) []*semver.Version {
+	if len(retracted) == 0 {
+		return versions
+	}
+
+	result := make([]*semver.Version, 0, len(versions))
+
+	for _, v := range versions {
+		if !retracted[v.Original()] {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// getConcurrency returns number of modules resolved concurrently, GOMAXPROCS*4 by default.
+func getConcurrency() int {
+	concurrency := viper.GetInt("concurrency")
+	if concurrency <= 0 {
+		return runtime.GOMAXPROCS(0) * 4
+	}
+
+	return concurrency
+}
+
 type void struct{}
 
 var member void
 
-func getIgnoredModules() map[string]void {
+// ignoreList contains modules ignored by exact path or by glob pattern.
+type ignoreList struct {
+	modules  map[string]void
+	patterns []string
+}
+
+// getIgnoredModules merges ignored_modules and exclude_patterns from config with the given patterns.
+func getIgnoredModules(patterns []string) (*ignoreList, error) {
 	s := make(map[string]void)
 
 	im := viper.GetStringSlice("ignored_modules")
@@ -88,5 +915,122 @@ func getIgnoredModules() map[string]void {
 		s[m] = member
 	}
 
-	return s
+	patterns = append(viper.GetStringSlice("exclude_patterns"), patterns...)
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	return &ignoreList{modules: s, patterns: patterns}, nil
+}
+
+// isIgnored reports whether module path is ignored, patterns are matched against the full module path.
+func (l *ignoreList) isIgnored(modulePath string) bool {
+	if _, ok := l.modules[modulePath]; ok {
+		return true
+	}
+
+	for _, pattern := range l.patterns {
+		if matched, _ := path.Match(pattern, modulePath); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// versionPolicy restricts update candidates per module.
+// Module paths are lower cased, since config keys are, so they are matched case insensitively.
+type versionPolicy struct {
+	// ignored versions are never update candidates.
+	ignored map[string][]*semver.Constraints
+	// constraints limit update candidates to the versions satisfying them.
+	constraints map[string]*semver.Constraints
+}
+
+// getVersionPolicy parses ignored_versions and constraints from config.
+// ignored_versions maps module paths to versions or constraints, e.g. v1.5.3 or ">=1.5.0 <1.6.0",
+// constraints maps module paths to a single constraint, e.g. "<2.0.0".
+func getVersionPolicy() (*versionPolicy, error) {
+	policy := &versionPolicy{
+		ignored:     make(map[string][]*semver.Constraints),
+		constraints: make(map[string]*semver.Constraints),
+	}
+
+	for modulePath, constraints := range viper.GetStringMapStringSlice("ignored_versions") {
+		for _, constraint := range constraints {
+			c, err := parseConstraint(constraint)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ignored version %q of %s: %w", constraint, modulePath, err)
+			}
+
+			policy.ignored[strings.ToLower(modulePath)] = append(policy.ignored[strings.ToLower(modulePath)], c)
+		}
+	}
+
+	for modulePath, constraint := range viper.GetStringMapString("constraints") {
+		c, err := parseConstraint(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q of %s: %w", constraint, modulePath, err)
+		}
+
+		policy.constraints[strings.ToLower(modulePath)] = c
+	}
+
+	return policy, nil
+}
+
+// parseConstraint parses a semver constraint, space separated comparisons are combined like comma separated ones.
+func parseConstraint(constraint string) (*semver.Constraints, error) {
+	var alternatives []string
+
+	for _, alternative := range strings.Split(constraint, "||") {
+		var comparisons []string
+
+		for _, field := range strings.Fields(alternative) {
+			// an operator separated from its version by a space, e.g. ">= 1.5.0".
+			if n := len(comparisons); n > 0 && strings.Trim(comparisons[n-1], "<>=!~^") == "" {
+				comparisons[n-1] += field
+				continue
+			}
+
+			comparisons = append(comparisons, strings.TrimSuffix(field, ","))
+		}
+
+		alternatives = append(alternatives, strings.Join(comparisons, ", "))
+	}
+
+	return semver.NewConstraint(strings.Join(alternatives, " || "))
+}
+
+// filter returns the filter applied to versions of the module which the policy allows, nil if the filter is nil.
+func (p *versionPolicy) filter(modulePath string, filter versionFilter) versionFilter {
+	ignored := p.ignored[strings.ToLower(modulePath)]
+	constraint := p.constraints[strings.ToLower(modulePath)]
+
+	if filter == nil || len(ignored) == 0 && constraint == nil {
+		return filter
+	}
+
+	return func(localVersion *semver.Version, versions []*semver.Version) (*semver.Version, error) {
+		candidates := make([]*semver.Version, 0, len(versions))
+
+	next:
+		for _, v := range versions {
+			if constraint != nil && !constraint.Check(v) {
+				continue
+			}
+
+			for _, c := range ignored {
+				if c.Check(v) {
+					continue next
+				}
+			}
+
+			candidates = append(candidates, v)
+		}
+
+		return filter(localVersion, candidates)
+	}
 }
@@ -3,10 +3,17 @@ package module
 import (
 	"context"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -31,7 +38,7 @@ func (s *CheckTestSuite) TearDownTest() {
 func (s *CheckTestSuite) Test_SelfCheck() {
 	checker := Checker{Ctx: s.ctx}
 
-	result, err := checker.Check("../..")
+	result, err := checker.Check("../..", internal.CheckOptions{})
 	s.NoError(err)
 	s.NotEmpty(result)
 }
@@ -40,10 +47,158 @@ func (s *CheckTestSuite) Test_SelfCheck_CancelContextBefore() {
 	checker := Checker{Ctx: s.ctx}
 	s.cnl()
 
-	result, err := checker.Check("../..")
+	result, err := checker.Check("../..", internal.CheckOptions{})
 	s.EqualError(err, "context canceled")
 	s.Empty(result)
 }
+
+func (s *CheckTestSuite) Test_PrivateModule() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	goPrivate := os.Getenv("GOPRIVATE")
+	defer os.Setenv("GOPRIVATE", goPrivate)
+	os.Setenv("GOPRIVATE", "private.invalid")
+
+	dir, err := ioutil.TempDir("", "private")
+	s.Require().NoError(err)
+	defer os.RemoveAll(dir)
+
+	s.Require().NoError(ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.15\n\nrequire private.invalid/a v1.0.0\n"), 0644))
+
+	checker := Checker{Ctx: s.ctx}
+
+	results, err := checker.Check(dir, internal.CheckOptions{})
+	s.NoError(err)
+	s.ErrorIs(results["private.invalid/a"].Error, ErrPrivateModule)
+}
+
+func (s *CheckTestSuite) Test_PrivatePatternsWithoutGo() {
+	goPrivate, path := os.Getenv("GOPRIVATE"), os.Getenv("PATH")
+	defer os.Setenv("GOPRIVATE", goPrivate)
+	defer os.Setenv("PATH", path)
+
+	os.Setenv("GOPRIVATE", "private.invalid,*.corp.example.com")
+	os.Setenv("PATH", "")
+
+	patterns, err := getPrivatePatterns(s.ctx)
+	s.NoError(err, "a failing go command falls back to the environment")
+	s.Equal("private.invalid,*.corp.example.com", patterns)
+
+	s.cnl()
+
+	_, err = getPrivatePatterns(s.ctx)
+	s.Error(err)
+}
+
+func (s *CheckTestSuite) Test_ScopeFilters() {
+	local := semver.MustParse("v1.2.3")
+	versions := func() []*semver.Version {
+		return []*semver.Version{
+			semver.MustParse("v1.2.3"),
+			semver.MustParse("v1.2.5"),
+			semver.MustParse("v1.3.0"),
+			semver.MustParse("v2.0.1"),
+		}
+	}
+
+	latest, err := getLatestPatchVersion(local, versions())
+	s.NoError(err)
+	s.Equal("v1.2.5", latest.Original())
+
+	latest, err = getLatestMinorVersion(local, versions())
+	s.NoError(err)
+	s.Equal("v1.3.0", latest.Original())
+
+	latest, err = getLatestMajorVersion(local, versions())
+	s.NoError(err)
+	s.Equal("v2.0.1", latest.Original())
+
+	latest, err = getLatestMajorVersion(semver.MustParse("v2.0.0"), versions())
+	s.Equal(ErrNoVersionAvailable, err)
+	s.Nil(latest)
+}
+
+func (s *CheckTestSuite) Test_WantedFilter() {
+	versions := func() []*semver.Version {
+		return []*semver.Version{
+			semver.MustParse("v0.2.1"),
+			semver.MustParse("v0.2.4"),
+			semver.MustParse("v0.3.0"),
+			semver.MustParse("v1.1.0"),
+			semver.MustParse("v1.2.0"),
+			semver.MustParse("v1.3.0-rc.1"),
+			semver.MustParse("v2.0.0"),
+		}
+	}
+
+	s.Nil(getWantedFilter(internal.CheckOptions{}))
+
+	wanted, err := getWantedFilter(internal.CheckOptions{Wanted: true})(semver.MustParse("v0.2.1"), versions())
+	s.NoError(err)
+	s.Equal("v0.2.4", wanted.Original(), "v0 versions stay within the minor")
+
+	wanted, err = getWantedFilter(internal.CheckOptions{Wanted: true})(semver.MustParse("v1.1.0"), versions())
+	s.NoError(err)
+	s.Equal("v1.2.0", wanted.Original())
+
+	wanted, err = getWantedFilter(internal.CheckOptions{Wanted: true, Prerelease: true})(semver.MustParse("v1.1.0"), versions())
+	s.NoError(err)
+	s.Equal("v1.3.0-rc.1", wanted.Original())
+}
+
+func (s *CheckTestSuite) Test_PreferStable() {
+	versions := func() []*semver.Version {
+		return []*semver.Version{
+			semver.MustParse("v1.2.3"),
+			semver.MustParse("v1.3.0"),
+			semver.MustParse("v1.4.0-rc.1"),
+			semver.MustParse("v2.0.0-beta.1"),
+			semver.MustParse("v2.0.0-beta.2"),
+		}
+	}
+
+	latest, err := getFilter(internal.CheckOptions{})(semver.MustParse("v1.2.3"), versions())
+	s.NoError(err)
+	s.Equal("v1.3.0", latest.Original())
+
+	latest, err = getFilter(internal.CheckOptions{Prerelease: true})(semver.MustParse("v1.2.3"), versions())
+	s.NoError(err)
+	s.Equal("v2.0.0-beta.2", latest.Original())
+
+	latest, err = getFilter(internal.CheckOptions{})(semver.MustParse("v2.0.0-beta.1"), versions())
+	s.NoError(err)
+	s.Equal("v2.0.0-beta.2", latest.Original())
+
+	latest, err = getFilter(internal.CheckOptions{Scope: internal.ScopeMajor})(semver.MustParse("v1.2.3"), versions())
+	s.NoError(err)
+	s.Equal("v2.0.0-beta.2", latest.Original())
+}
+
+func (s *CheckTestSuite) Test_IgnoredModules() {
+	viper.Set("ignored_modules", []string{"github.com/x/y"})
+	defer viper.Set("ignored_modules", nil)
+
+	ignored, err := getIgnoredModules([]string{"google.golang.org/*", "*/internal/*"})
+	s.NoError(err)
+
+	s.True(ignored.isIgnored("github.com/x/y"))
+	s.True(ignored.isIgnored("google.golang.org/grpc"))
+	s.True(ignored.isIgnored("example.com/internal/pkg"))
+	s.False(ignored.isIgnored("google.golang.org/grpc/examples"))
+	s.False(ignored.isIgnored("github.com/x/z"))
+
+	_, err = getIgnoredModules([]string{"github.com/["})
+	s.Error(err)
+}
+
 func (s *CheckTestSuite) Test_CustomModFile() {
 	gomodContent := []byte(`module test-project
 
@@ -94,10 +249,310 @@ require (
 
 	checker := Checker{Ctx: s.ctx}
 
-	result, err := checker.Check(tempDir)
+	result, err := checker.Check(tempDir, internal.CheckOptions{})
 	s.NoError(err)
 	s.NotEmpty(result)
 
 	s.NoError(os.Remove(tempFile))
 	s.NoError(os.RemoveAll(tempDir))
 }
+
+func (s *CheckTestSuite) Test_IgnoredVersions() {
+	viper.Set("ignored_versions", map[string][]string{
+		"github.com/x/Y": {"v1.5.3", ">=1.6.0 <1.7.0"},
+	})
+	defer viper.Set("ignored_versions", nil)
+
+	policy, err := getVersionPolicy()
+	s.NoError(err)
+
+	versions := []*semver.Version{
+		semver.MustParse("v1.5.2"),
+		semver.MustParse("v1.5.3"),
+		semver.MustParse("v1.6.0"),
+		semver.MustParse("v1.6.4"),
+	}
+
+	latest, err := policy.filter("github.com/x/y", getLatestVersion)(semver.MustParse("v1.5.0"), versions)
+	s.NoError(err)
+	s.Equal("v1.5.2", latest.Original())
+
+	latest, err = policy.filter("github.com/x/z", getLatestVersion)(semver.MustParse("v1.5.0"), versions)
+	s.NoError(err)
+	s.Equal("v1.6.4", latest.Original())
+
+	viper.Set("ignored_versions", map[string][]string{"github.com/x/y": {">= 1.x.y"}})
+
+	_, err = getVersionPolicy()
+	s.Error(err)
+}
+
+func (s *CheckTestSuite) Test_MergeResults() {
+	a := internal.CheckResult{
+		LocalVersion:  semver.MustParse("v1.3.0"),
+		LatestVersion: semver.MustParse("v1.5.0"),
+		Indirect:      true,
+	}
+	b := internal.CheckResult{
+		LocalVersion:  semver.MustParse("v1.2.0"),
+		LatestVersion: semver.MustParse("v1.5.0"),
+	}
+
+	merged := mergeResults(a, b)
+	s.Equal("v1.2.0", merged.LocalVersion.Original())
+	s.Equal("v1.5.0", merged.LatestVersion.Original())
+	s.False(merged.Indirect)
+	s.Len(merged.LocalVersions, 2)
+	s.Equal("v1.2.0", merged.LocalVersions[0].Original())
+	s.Equal("v1.3.0", merged.LocalVersions[1].Original())
+
+	merged = mergeResults(merged, internal.CheckResult{LocalVersion: semver.MustParse("v1.3.0"), Error: os.ErrNotExist})
+	s.NoError(merged.Error)
+	s.Len(merged.LocalVersions, 2)
+
+	merged = mergeResults(b, b)
+	s.Nil(merged.LocalVersions)
+}
+
+func (s *CheckTestSuite) Test_Constraints() {
+	viper.Set("constraints", map[string]string{"github.com/x/y": "<2.0.0"})
+	viper.Set("ignored_versions", map[string][]string{"github.com/x/y": {"v1.9.0"}})
+	defer viper.Set("constraints", nil)
+	defer viper.Set("ignored_versions", nil)
+
+	policy, err := getVersionPolicy()
+	s.NoError(err)
+
+	versions := []*semver.Version{
+		semver.MustParse("v1.8.0"),
+		semver.MustParse("v1.9.0"),
+		semver.MustParse("v2.0.0"),
+	}
+
+	latest, err := policy.filter("github.com/x/y", getLatestVersion)(semver.MustParse("v1.5.0"), versions)
+	s.NoError(err)
+	s.Equal("v1.8.0", latest.Original())
+
+	_, err = policy.filter("github.com/x/y", getLatestMajorVersion)(semver.MustParse("v1.5.0"), versions)
+	s.Equal(ErrNoVersionAvailable, err)
+
+	viper.Set("constraints", map[string]string{"github.com/x/y": "<= one"})
+
+	_, err = getVersionPolicy()
+	s.Error(err)
+}
+
+func (s *CheckTestSuite) Test_ConfigFormats() {
+	configs := map[string]string{
+		"yaml": `
+ignored_modules:
+  - github.com/x/y
+exclude_patterns:
+  - google.golang.org/*
+ignored_versions:
+  github.com/a/b:
+    - v1.9.0
+constraints:
+  github.com/a/b: "<2.0.0"
+`,
+		"json": `{
+  "ignored_modules": ["github.com/x/y"],
+  "exclude_patterns": ["google.golang.org/*"],
+  "ignored_versions": {"github.com/a/b": ["v1.9.0"]},
+  "constraints": {"github.com/a/b": "<2.0.0"}
+}`,
+		"toml": `
+ignored_modules = ["github.com/x/y"]
+exclude_patterns = ["google.golang.org/*"]
+
+[ignored_versions]
+"github.com/a/b" = ["v1.9.0"]
+
+[constraints]
+"github.com/a/b" = "<2.0.0"
+`,
+	}
+
+	versions := []*semver.Version{
+		semver.MustParse("v1.8.0"),
+		semver.MustParse("v1.9.0"),
+		semver.MustParse("v2.0.0"),
+	}
+
+	for format, config := range configs {
+		viper.Reset()
+		viper.SetConfigType(format)
+		s.NoError(viper.ReadConfig(strings.NewReader(config)), format)
+
+		ignored, err := getIgnoredModules(nil)
+		s.NoError(err, format)
+		s.True(ignored.isIgnored("github.com/x/y"), format)
+		s.True(ignored.isIgnored("google.golang.org/grpc"), format)
+
+		policy, err := getVersionPolicy()
+		s.NoError(err, format)
+
+		latest, err := policy.filter("github.com/a/b", getLatestVersion)(semver.MustParse("v1.5.0"), versions)
+		s.NoError(err, format)
+		s.Equal("v1.8.0", latest.Original(), format)
+	}
+
+	viper.Reset()
+}
+
+func (s *CheckTestSuite) Test_PartialResults() {
+	queue := []PackageResult{
+		{Path: "example.com/a", LocalVersion: semver.MustParse("v1.0.0")},
+		{Path: "example.com/b", LocalVersion: semver.MustParse("v1.1.0"), Indirect: true},
+		{Path: "example.com/c", LocalVersion: semver.MustParse("v1.2.0")},
+	}
+
+	checkResults := map[string]internal.CheckResult{
+		"example.com/a": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.3.0")},
+		"example.com/c": {LocalVersion: semver.MustParse("v1.2.0"), Error: context.DeadlineExceeded},
+	}
+
+	results, err := partialResults(queue, checkResults, context.DeadlineExceeded)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.EqualError(err, "context deadline exceeded, 1 of 3 modules resolved")
+
+	s.Len(results, 3)
+	s.NoError(results["example.com/a"].Error)
+	s.ErrorIs(results["example.com/b"].Error, context.DeadlineExceeded)
+	s.True(results["example.com/b"].Indirect)
+	s.ErrorIs(results["example.com/c"].Error, context.DeadlineExceeded)
+}
+
+func (s *CheckTestSuite) Test_ParseRequiredFallback() {
+	gomodContent := []byte(`module test-project
+
+go 1.15
+
+require (
+	example.com/missing v1.0.0
+	example.com/local v1.0.0 // indirect
+)
+
+replace example.com/local => ./local
+`)
+
+	tempDir, err := ioutil.TempDir("", "test")
+	s.NoError(err)
+	defer os.RemoveAll(tempDir)
+
+	s.NoError(ioutil.WriteFile(filepath.Join(tempDir, "go.mod"), gomodContent, 0666))
+
+	// go list can't load example.com/missing without a proxy.
+	viper.Set("proxy", "off")
+	defer viper.Set("proxy", nil)
+
+	parser := ModParser{ctx: s.ctx}
+
+	results, err := parseRequired(&parser, tempDir)
+	s.NoError(err)
+	s.Len(results, 1, "local path replacements are left out")
+	s.Equal("example.com/missing", results[0].Path)
+	s.Equal("v1.0.0", results[0].LocalVersion.Original())
+}
+
+func (s *CheckTestSuite) Test_CheckGlob() {
+	tempDir, err := ioutil.TempDir("", "test")
+	s.NoError(err)
+	defer os.RemoveAll(tempDir)
+
+	for name, content := range map[string]string{
+		"a/go.mod":      "module example.com/a\n\ngo 1.15\n",
+		"b/go.mod":      "module example.com/b\n\nrequire (\n",
+		"docs/index.md": "# docs\n",
+	} {
+		file := filepath.Join(tempDir, "services", filepath.FromSlash(name))
+		s.NoError(os.MkdirAll(filepath.Dir(file), 0755))
+		s.NoError(ioutil.WriteFile(file, []byte(content), 0666))
+	}
+
+	dirs, err := globModules(filepath.Join(tempDir, "services", "*") + "/")
+	s.NoError(err)
+	s.Equal([]string{filepath.Join(tempDir, "services", "a"), filepath.Join(tempDir, "services", "b")}, dirs, "directories without go.mod are left out")
+
+	checker := Checker{Ctx: s.ctx}
+
+	result, err := checker.Check(filepath.Join(tempDir, "services", "*"), internal.CheckOptions{})
+	s.NoError(err, "the broken module doesn't abort the others")
+	s.Empty(result)
+
+	_, err = checker.Check(filepath.Join(tempDir, "services", "b*"), internal.CheckOptions{})
+	s.Error(err)
+
+	_, err = globModules(filepath.Join(tempDir, "services", "docs*"))
+	s.Error(err)
+}
+
+func (s *CheckTestSuite) Test_CheckModules() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.0.0\nv1.1.0\nv1.2.0\n"))
+		case "/example.com/b/@v/list":
+			w.Write([]byte("v0.1.0\nv0.2.0\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	checker := Checker{Ctx: s.ctx}
+
+	modules := []internal.Requirement{{Path: "example.com/a", Version: "v1.1.0"}, {Path: "example.com/b"}}
+
+	result, err := checker.Check("does-not-exist", internal.CheckOptions{Modules: modules})
+	s.NoError(err, "go.mod isn't read")
+	s.Len(result, 2)
+	s.Equal("v1.1.0", result["example.com/a"].LocalVersion.Original())
+	s.Equal("v1.2.0", result["example.com/a"].LatestVersion.Original())
+	s.Equal(internal.UpdateMinor, result["example.com/a"].UpdateType())
+	s.Nil(result["example.com/b"].LocalVersion)
+	s.Equal("v0.2.0", result["example.com/b"].LatestVersion.Original())
+
+	_, err = checker.Check("", internal.CheckOptions{Modules: modules, Scope: internal.ScopePatch})
+	s.EqualError(err, "a version of example.com/b is required to limit the update scope, use module@version")
+
+	_, err = checker.Check("", internal.CheckOptions{Modules: append(modules, internal.Requirement{Path: "example.com/a"})})
+	s.EqualError(err, "example.com/a is listed more than once")
+}
+
+func (s *CheckTestSuite) Test_CheckModuleTimeout() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.0.0\nv1.1.0\n"))
+		case "/example.com/slow/@v/list":
+			// the proxy hangs until the request is canceled.
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	checker := Checker{Ctx: s.ctx}
+
+	modules := []internal.Requirement{{Path: "example.com/a", Version: "v1.0.0"}, {Path: "example.com/slow", Version: "v1.0.0"}}
+
+	result, err := checker.Check("", internal.CheckOptions{Modules: modules, ModuleTimeout: 100 * time.Millisecond})
+	s.NoError(err)
+	s.Equal("v1.1.0", result["example.com/a"].LatestVersion.Original())
+	s.False(result["example.com/a"].TimedOut())
+	s.True(result["example.com/slow"].TimedOut())
+	s.ErrorIs(result["example.com/slow"].Error, ErrModuleTimeout)
+	s.EqualError(result["example.com/slow"].Error, "module timed out after 100ms")
+}
@@ -0,0 +1,76 @@
+package module
+
+import (
+	"context"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// Differ compares requirements of two go.mod files.
+type Differ struct {
+	Ctx context.Context
+}
+
+// Diff returns modules which are added, removed or required with another version by the new go.mod, keyed by module path.
+// Paths are go.mod files or directories containing one. Replaced modules are compared by their replacement.
+func (d *Differ) Diff(oldPath, newPath string) (map[string]internal.DiffResult, error) {
+	parser := ModParser{ctx: d.Ctx}
+
+	oldModules, err := parser.ParseFile(oldPath)
+	if err != nil {
+		return nil, err
+	}
+
+	newModules, err := parser.ParseFile(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffModules(oldModules, newModules), nil
+}
+
+// diffModules compares requirements, see Diff.
+func diffModules(oldModules, newModules []PackageResult) map[string]internal.DiffResult {
+	old := make(map[string]PackageResult, len(oldModules))
+	for _, m := range oldModules {
+		old[m.Path] = m
+	}
+
+	results := make(map[string]internal.DiffResult)
+
+	for _, m := range newModules {
+		o, ok := old[m.Path]
+		delete(old, m.Path)
+
+		if !ok {
+			results[m.Path] = internal.DiffResult{Change: internal.DiffAdded, NewVersion: m.LocalVersion, Indirect: m.Indirect}
+			continue
+		}
+
+		if o.ResolvePath() == m.ResolvePath() && versionOf(o) == versionOf(m) {
+			continue
+		}
+
+		results[m.Path] = internal.DiffResult{
+			Change:     internal.DiffChanged,
+			OldVersion: o.LocalVersion,
+			NewVersion: m.LocalVersion,
+			Indirect:   m.Indirect,
+		}
+	}
+
+	for path, o := range old {
+		results[path] = internal.DiffResult{Change: internal.DiffRemoved, OldVersion: o.LocalVersion, Indirect: o.Indirect}
+	}
+
+	return results
+}
+
+// versionOf returns the local version of the module, empty if it is replaced by a local path.
+func versionOf(m PackageResult) string {
+	if m.LocalVersion == nil {
+		return ""
+	}
+
+	return m.LocalVersion.Original()
+}
@@ -0,0 +1,70 @@
+package module
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffer_Diff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gomodctl-diff")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	oldFile := filepath.Join(dir, "old.mod")
+	newFile := filepath.Join(dir, "new.mod")
+
+	assert.NoError(t, ioutil.WriteFile(oldFile, []byte(`module example.com/m
+
+go 1.15
+
+require (
+	example.com/a v1.2.3
+	example.com/b v1.0.0
+	example.com/c v1.4.0 // indirect
+	example.com/d v0.1.0
+	example.com/e v1.0.0
+)
+`), 0644))
+
+	assert.NoError(t, ioutil.WriteFile(newFile, []byte(`module example.com/m
+
+go 1.15
+
+require (
+	example.com/a v2.0.0+incompatible
+	example.com/c v1.3.9 // indirect
+	example.com/d v0.1.0
+	example.com/e v1.0.0
+	example.com/f v0.2.0
+)
+
+replace example.com/e => example.com/fork/e v1.0.1
+`), 0644))
+
+	differ := Differ{Ctx: context.Background()}
+
+	results, err := differ.Diff(oldFile, newFile)
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+
+	assert.Equal(t, internal.DiffChanged, results["example.com/a"].Change)
+	assert.Equal(t, internal.UpdateMajor, results["example.com/a"].UpdateType())
+
+	assert.Equal(t, internal.DiffRemoved, results["example.com/b"].Change)
+	assert.Equal(t, "v1.0.0", results["example.com/b"].OldVersion.Original())
+
+	assert.True(t, results["example.com/c"].Downgrade())
+	assert.True(t, results["example.com/c"].Indirect)
+	assert.Equal(t, internal.UpdateMinor, results["example.com/c"].UpdateType())
+
+	assert.Equal(t, internal.UpdatePatch, results["example.com/e"].UpdateType())
+
+	assert.Equal(t, internal.DiffAdded, results["example.com/f"].Change)
+	assert.Nil(t, results["example.com/f"].OldVersion)
+}
@@ -0,0 +1,79 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"sync"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// ErrGosecNotFound is returned by Gosec when the gosec command isn't installed.
+var ErrGosecNotFound = errors.New("gosec not found in PATH, install it from https://github.com/securego/gosec")
+
+// Gosec runs the gosec static analyzer on the sources of the dependencies of the module in the given path.
+// Modules whose gosec output can't be parsed are left out.
+func (c *Scanner) Gosec(path string) (map[string]internal.GosecResult, error) {
+	if _, err := exec.LookPath("gosec"); err != nil {
+		return nil, ErrGosecNotFound
+	}
+
+	parser := ModParser{ctx: c.Ctx}
+
+	packages, err := parser.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return gosecScan(c.Ctx, packages), nil
+}
+
+// gosecScan runs gosec in the directories of the packages concurrently.
+func gosecScan(ctx context.Context, packages []PackageResult) map[string]internal.GosecResult {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	result := make(map[string]internal.GosecResult)
+	jobs := make(chan PackageResult)
+
+	for i := 0; i < getConcurrency(); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for p := range jobs {
+				cmd := exec.CommandContext(ctx, "gosec", "-quiet", "-fmt=json", p.Dir+"/./...")
+				// gosec exits with status 1 when it finds issues, so only its output is checked.
+				out, _ := cmd.Output()
+
+				var gr internal.GosecResult
+				if err := json.Unmarshal(out, &gr); err != nil {
+					continue
+				}
+
+				mu.Lock()
+				result[p.Path] = gr
+				mu.Unlock()
+			}
+		}()
+	}
+
+loop:
+	for _, p := range packages {
+		select {
+		case jobs <- p:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return result
+}
@@ -0,0 +1,124 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Grapher collects the module graph.
+type Grapher struct {
+	Ctx context.Context
+}
+
+// Graph returns the module graph of the main module in the given path, see internal.GraphOptions.
+func (g *Grapher) Graph(path string, options internal.GraphOptions) (*internal.Graph, error) {
+	parser := ModParser{ctx: g.Ctx}
+
+	graph, err := parser.Graph(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := graphRoot(graph, options.Root)
+	if !ok && options.Root == "" {
+		// go mod graph prints nothing for a module without requirements.
+		root, err = mainModule(path)
+		if err != nil {
+			return nil, err
+		}
+	} else if !ok {
+		return nil, fmt.Errorf("%s isn't required by the main module, not even transitively: %w", options.Root, ErrNotInGraph)
+	}
+
+	return subgraph(graph, root, options.Depth), nil
+}
+
+// mainModule reads the path of the main module in the given path from go.mod.
+func mainModule(path string) (module.Version, error) {
+	dir, err := moduleDir(path)
+	if err != nil {
+		return module.Version{}, err
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, goMod))
+	if err != nil {
+		return module.Version{}, err
+	}
+
+	return module.Version{Path: modfile.ModulePath(content)}, nil
+}
+
+// graphRoot returns the module matching the path with an optional @version, the main module if it is empty.
+// If only a path is given and the graph has several versions of it, the highest one is the root as that's the one selected.
+func graphRoot(graph map[module.Version][]module.Version, root string) (module.Version, bool) {
+	rootPath, rootVersion := root, ""
+	if i := strings.LastIndex(root, "@"); i >= 0 {
+		rootPath, rootVersion = root[:i], root[i+1:]
+	}
+
+	var found module.Version
+	ok := false
+
+	matches := func(m module.Version) bool {
+		if root == "" {
+			return m.Version == ""
+		}
+
+		return m.Path == rootPath && m.Version != "" && (rootVersion == "" || m.Version == rootVersion)
+	}
+
+	for from, requirements := range graph {
+		for _, m := range append([]module.Version{from}, requirements...) {
+			if matches(m) && (!ok || semver.Compare(m.Version, found.Version) > 0) {
+				found, ok = m, true
+			}
+		}
+	}
+
+	return found, ok
+}
+
+// subgraph walks the graph breadth first from the root, edges of modules deeper than depth are left out unless it is zero.
+// The go and toolchain entries of go mod graph aren't modules and are left out too.
+func subgraph(graph map[module.Version][]module.Version, root module.Version, depth int) *internal.Graph {
+	result := &internal.Graph{Root: requirement(root)}
+
+	levels := map[module.Version]int{root: 0}
+	queue := []module.Version{root}
+
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+
+		if depth > 0 && levels[m] >= depth {
+			continue
+		}
+
+		for _, r := range graph[m] {
+			if r.Path == "go" || r.Path == "toolchain" {
+				continue
+			}
+
+			result.Edges = append(result.Edges, internal.Edge{From: requirement(m), To: requirement(r)})
+
+			if _, ok := levels[r]; !ok {
+				levels[r] = levels[m] + 1
+				queue = append(queue, r)
+			}
+		}
+	}
+
+	return result
+}
+
+func requirement(m module.Version) internal.Requirement {
+	return internal.Requirement{Path: m.Path, Version: m.Version}
+}
@@ -0,0 +1,48 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/module"
+)
+
+func TestSubgraph(t *testing.T) {
+	main := module.Version{Path: "example.com/main"}
+	a := module.Version{Path: "example.com/a", Version: "v1.0.0"}
+	b := module.Version{Path: "example.com/b", Version: "v1.0.0"}
+	c := module.Version{Path: "example.com/c", Version: "v1.0.0"}
+	c2 := module.Version{Path: "example.com/c", Version: "v1.1.0"}
+
+	graph := map[module.Version][]module.Version{
+		main: {a, b, {Path: "go", Version: "1.21.0"}},
+		a:    {c},
+		b:    {c2, a},
+	}
+
+	root, ok := graphRoot(graph, "")
+	assert.True(t, ok)
+	assert.Equal(t, main, root)
+
+	result := subgraph(graph, root, 0)
+	assert.Equal(t, internal.Requirement{Path: "example.com/main"}, result.Root)
+	assert.Len(t, result.Edges, 5)
+	assert.Equal(t, "example.com/main", result.Edges[0].From.String())
+	assert.Equal(t, "example.com/a@v1.0.0", result.Edges[0].To.String())
+
+	result = subgraph(graph, root, 1)
+	assert.Len(t, result.Edges, 2)
+
+	root, ok = graphRoot(graph, "example.com/c")
+	assert.True(t, ok)
+	assert.Equal(t, c2, root)
+	assert.Empty(t, subgraph(graph, root, 0).Edges)
+
+	root, ok = graphRoot(graph, "example.com/b@v1.0.0")
+	assert.True(t, ok)
+	assert.Len(t, subgraph(graph, root, 0).Edges, 3)
+
+	_, ok = graphRoot(graph, "example.com/x")
+	assert.False(t, ok)
+}
@@ -0,0 +1,286 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/httpclient"
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultOSVURL is used when osv_url config isn't set.
+	DefaultOSVURL = "https://api.osv.dev"
+
+	// osvBatchSize is the maximum number of queries in a batch accepted by OSV.
+	osvBatchSize = 1000
+
+	osvEcosystem = "Go"
+)
+
+// advisorySource looks up vulnerabilities of module versions, either the OSV API or a local copy of the database.
+type advisorySource interface {
+	// queryBatch returns IDs of vulnerabilities affecting each module version, in the order of the given versions.
+	queryBatch(versions []moduleVersion) ([][]string, error)
+	// vulnerability returns details of the vulnerability.
+	vulnerability(id string) (*osvVulnerability, error)
+}
+
+// osvClient queries the OSV database, see https://google.github.io/osv.dev/api/.
+type osvClient struct {
+	ctx        context.Context
+	restClient *resty.Client
+	url        string
+
+	mu    sync.Mutex
+	vulns map[string]*osvVulnerability
+}
+
+// newOSVClient returns a client of the OSV API at the given URL, osv_url config or DefaultOSVURL are used if it's empty.
+func newOSVClient(ctx context.Context, u string) *osvClient {
+	if u == "" {
+		u = viper.GetString("osv_url")
+	}
+
+	u = strings.TrimSuffix(u, "/")
+	if u == "" {
+		u = DefaultOSVURL
+	}
+
+	return &osvClient{
+		ctx:        ctx,
+		restClient: httpclient.New(),
+		url:        u,
+		vulns:      make(map[string]*osvVulnerability),
+	}
+}
+
+type osvQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version"`
+}
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type osvBatchQuery struct {
+	Queries []osvQuery `json:"queries"`
+}
+
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+type osvVulnerability struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Summary  string   `json:"summary"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package osvPackage `json:"package"`
+		Ranges  []struct {
+			Type   string     `json:"type"`
+			Events []osvEvent `json:"events"`
+		} `json:"ranges"`
+		Versions []string `json:"versions"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// osvEvent is an event of an affected range, only one of its fields is set.
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+func (c *osvClient) queryBatch(versions []moduleVersion) ([][]string, error) {
+	ids := make([][]string, len(versions))
+
+	for start := 0; start < len(versions); start += osvBatchSize {
+		end := start + osvBatchSize
+		if end > len(versions) {
+			end = len(versions)
+		}
+
+		query := osvBatchQuery{}
+		for _, v := range versions[start:end] {
+			query.Queries = append(query.Queries, osvQuery{
+				Package: osvPackage{Name: v.path, Ecosystem: osvEcosystem},
+				Version: strings.TrimPrefix(v.version, "v"),
+			})
+		}
+
+		result := &osvBatchResponse{}
+
+		response, err := c.restClient.R().
+			SetContext(c.ctx).
+			SetBody(query).
+			SetResult(result).
+			Post(c.url + "/v1/querybatch")
+		if err != nil {
+			return nil, err
+		}
+
+		if !response.IsSuccess() {
+			return nil, fmt.Errorf("osv: %s", response.Status())
+		}
+
+		if len(result.Results) != end-start {
+			return nil, fmt.Errorf("osv: expected %d results, got %d", end-start, len(result.Results))
+		}
+
+		for i, r := range result.Results {
+			for _, vuln := range r.Vulns {
+				ids[start+i] = append(ids[start+i], vuln.ID)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// vulnerability fetches details of the vulnerability, details are kept for the lifetime of the client.
+func (c *osvClient) vulnerability(id string) (*osvVulnerability, error) {
+	c.mu.Lock()
+	vuln, ok := c.vulns[id]
+	c.mu.Unlock()
+
+	if ok {
+		return vuln, nil
+	}
+
+	vuln = &osvVulnerability{}
+
+	response, err := c.restClient.R().
+		SetContext(c.ctx).
+		SetResult(vuln).
+		Get(c.url + "/v1/vulns/" + url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.IsSuccess() {
+		return nil, fmt.Errorf("osv: %s: %s", id, response.Status())
+	}
+
+	c.mu.Lock()
+	c.vulns[id] = vuln
+	c.mu.Unlock()
+
+	return vuln, nil
+}
+
+// toVulnerability converts the OSV record for the given module version.
+func (v *osvVulnerability) toVulnerability(modulePath string, version *semver.Version) internal.Vulnerability {
+	vulnerability := internal.Vulnerability{
+		ID:       v.ID,
+		Aliases:  v.Aliases,
+		Summary:  v.Summary,
+		Severity: v.DatabaseSpecific.Severity,
+		Fixed:    v.firstFixed(modulePath, version),
+	}
+
+	if len(v.Severity) > 0 {
+		vulnerability.Severity = v.Severity[0].Score
+	}
+
+	vulnerability.Score, vulnerability.Rating = v.rating()
+
+	return vulnerability
+}
+
+// rating returns the CVSS v3 score and rating of the advisory, the database rating is used without a CVSS v3 vector.
+func (v *osvVulnerability) rating() (float64, internal.Severity) {
+	for _, severity := range v.Severity {
+		if severity.Type != "CVSS_V3" {
+			continue
+		}
+
+		if score, err := internal.CVSSScore(severity.Score); err == nil {
+			return score, internal.SeverityOfScore(score)
+		}
+	}
+
+	rating, _ := internal.ParseSeverity(v.DatabaseSpecific.Severity)
+
+	return 0, rating
+}
+
+// rate fills the rating of an unrated vulnerability from its GitHub advisory alias, Go advisories aren't rated.
+func rate(source advisorySource, vulnerability *internal.Vulnerability) {
+	if vulnerability.Rating != internal.SeverityUnknown {
+		return
+	}
+
+	for _, alias := range vulnerability.Aliases {
+		if !strings.HasPrefix(alias, "GHSA-") {
+			continue
+		}
+
+		vuln, err := source.vulnerability(alias)
+		if err != nil {
+			continue
+		}
+
+		if score, rating := vuln.rating(); rating != internal.SeverityUnknown {
+			vulnerability.Score, vulnerability.Rating = score, rating
+			if vulnerability.Severity == "" && len(vuln.Severity) > 0 {
+				vulnerability.Severity = vuln.Severity[0].Score
+			}
+
+			return
+		}
+	}
+}
+
+// firstFixed returns the lowest fixed version of the module which is higher than the given version.
+func (v *osvVulnerability) firstFixed(modulePath string, version *semver.Version) string {
+	var first *semver.Version
+
+	for _, affected := range v.Affected {
+		if affected.Package.Ecosystem != osvEcosystem || affected.Package.Name != modulePath {
+			continue
+		}
+
+		for _, r := range affected.Ranges {
+			for _, event := range r.Events {
+				if event.Fixed == "" {
+					continue
+				}
+
+				fixed, err := semver.NewVersion(event.Fixed)
+				if err != nil || !fixed.GreaterThan(version) {
+					continue
+				}
+
+				if first == nil || fixed.LessThan(first) {
+					first = fixed
+				}
+			}
+		}
+	}
+
+	if first == nil {
+		return ""
+	}
+
+	return "v" + strings.TrimPrefix(first.Original(), "v")
+}
@@ -0,0 +1,151 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVulnerabilityScan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/querybatch":
+			query := osvBatchQuery{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+			assert.Len(t, query.Queries, 2)
+			assert.Equal(t, "1.0.0", query.Queries[0].Version)
+			assert.Equal(t, "Go", query.Queries[0].Package.Ecosystem)
+
+			w.Write([]byte(`{"results":[{"vulns":[{"id":"GO-2021-0001"}]},{}]}`))
+		case "/v1/vulns/GO-2021-0001":
+			w.Write([]byte(`{
+				"id": "GO-2021-0001",
+				"aliases": ["CVE-2021-0001"],
+				"summary": "remote code execution",
+				"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+				"affected": [{
+					"package": {"name": "github.com/a/b", "ecosystem": "Go"},
+					"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.9.0"}, {"introduced": "1.0.0"}, {"fixed": "1.2.0"}, {"fixed": "1.1.0"}]}]
+				}]
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("osv_url", server.URL)
+	defer viper.Set("osv_url", nil)
+
+	ctx := context.Background()
+	packages := []PackageResult{
+		{Path: "github.com/a/b", LocalVersion: semver.MustParse("v1.0.0")},
+		{Path: "github.com/c/d", LocalVersion: semver.MustParse("v2.0.0")},
+	}
+
+	result, err := vulnerabilityScan(ctx, newOSVClient(ctx, ""), packages, internal.ScanOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	vulnerabilities := result["github.com/a/b"].Vulnerabilities
+	assert.Len(t, vulnerabilities, 1)
+	assert.Equal(t, "GO-2021-0001", vulnerabilities[0].ID)
+	assert.Equal(t, []string{"CVE-2021-0001"}, vulnerabilities[0].Aliases)
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", vulnerabilities[0].Severity)
+	assert.Equal(t, "v1.1.0", vulnerabilities[0].Fixed)
+	assert.Equal(t, 9.8, vulnerabilities[0].Score)
+	assert.Equal(t, internal.SeverityCritical, vulnerabilities[0].Rating)
+
+	assert.Empty(t, result["github.com/c/d"].Vulnerabilities)
+	assert.NoError(t, result["github.com/c/d"].Error)
+}
+
+func TestVulnerabilityScan_MinSeverity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/querybatch":
+			w.Write([]byte(`{"results":[{"vulns":[{"id":"GO-2021-0002"},{"id":"GO-2021-0003"}]},{"vulns":[{"id":"GO-2021-0004"}]}]}`))
+		case "/v1/vulns/GO-2021-0002":
+			w.Write([]byte(`{"id": "GO-2021-0002", "aliases": ["GHSA-aaaa-bbbb-cccc"]}`))
+		case "/v1/vulns/GHSA-aaaa-bbbb-cccc":
+			w.Write([]byte(`{"id": "GHSA-aaaa-bbbb-cccc", "database_specific": {"severity": "HIGH"}}`))
+		case "/v1/vulns/GO-2021-0003", "/v1/vulns/GO-2021-0004":
+			w.Write([]byte(`{"id": "GO-2021-0003", "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("osv_url", server.URL)
+	defer viper.Set("osv_url", nil)
+
+	ctx := context.Background()
+	packages := []PackageResult{
+		{Path: "github.com/a/b", LocalVersion: semver.MustParse("v1.0.0")},
+		{Path: "github.com/c/d", LocalVersion: semver.MustParse("v2.0.0")},
+	}
+
+	result, err := vulnerabilityScan(ctx, newOSVClient(ctx, ""), packages, internal.ScanOptions{MinSeverity: internal.SeverityMedium})
+	assert.NoError(t, err)
+
+	vulnerabilities := result["github.com/a/b"].Vulnerabilities
+	assert.Len(t, vulnerabilities, 1)
+	assert.Equal(t, "GO-2021-0002", vulnerabilities[0].ID)
+	assert.Equal(t, internal.SeverityHigh, vulnerabilities[0].Rating)
+
+	assert.Empty(t, result["github.com/c/d"].Vulnerabilities)
+}
+
+func TestUpdater_Severities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/querybatch":
+			query := osvBatchQuery{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+
+			results := make([]string, len(query.Queries))
+			for i, q := range query.Queries {
+				results[i] = "{}"
+				if q.Package.Name == "github.com/a/b" {
+					results[i] = `{"vulns":[{"id":"GO-2021-0003"},{"id":"GO-2021-0005"}]}`
+				}
+			}
+
+			w.Write([]byte(`{"results":[` + strings.Join(results, ",") + `]}`))
+		case "/v1/vulns/GO-2021-0003":
+			w.Write([]byte(`{"id": "GO-2021-0003", "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N"}]}`))
+		case "/v1/vulns/GO-2021-0005":
+			w.Write([]byte(`{"id": "GO-2021-0005", "database_specific": {"severity": "HIGH"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("osv_url", server.URL)
+	defer viper.Set("osv_url", nil)
+
+	updater := Updater{Ctx: context.Background()}
+	severities, err := updater.Severities(map[string]internal.CheckResult{
+		"github.com/a/b": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.1.0")},
+		"github.com/c/d": {LocalVersion: semver.MustParse("v2.0.0"), LatestVersion: semver.MustParse("v2.1.0")},
+		"github.com/e/f": {LocalVersion: semver.MustParse("v0.1.0"), Replaced: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]internal.Severity{"github.com/a/b": internal.SeverityHigh}, severities)
+}
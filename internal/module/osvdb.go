@@ -0,0 +1,245 @@
+package module
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// osvDatabase is a local copy of the OSV database, advisories are indexed by ID and affected module once loaded.
+type osvDatabase struct {
+	vulns    map[string]*osvVulnerability
+	byModule map[string][]*osvVulnerability
+}
+
+// loadOSVDatabase reads OSV JSON records of the given path, which is a directory of .json files, a .zip of them,
+// e.g. the Go/all.zip export of osv.dev, or a single .json file of a record or an array of records.
+func loadOSVDatabase(path string) (*osvDatabase, error) {
+	db := &osvDatabase{
+		vulns:    make(map[string]*osvVulnerability),
+		byModule: make(map[string][]*osvVulnerability),
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case info.IsDir():
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || filepath.Ext(file) != ".json" {
+				return err
+			}
+
+			return db.addFile(file)
+		})
+	case filepath.Ext(path) == ".zip":
+		err = db.addZip(path)
+	default:
+		err = db.addFile(path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (db *osvDatabase) addFile(file string) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	return db.add(file, content)
+}
+
+func (db *osvDatabase) addZip(file string) error {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || filepath.Ext(f.Name) != ".json" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+
+		if err := db.add(file+":"+f.Name, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add indexes the record or the array of records of the content, name is the source in errors.
+func (db *osvDatabase) add(name string, content []byte) error {
+	var vulns []*osvVulnerability
+
+	content = bytes.TrimSpace(content)
+	if bytes.HasPrefix(content, []byte("[")) {
+		if err := json.Unmarshal(content, &vulns); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	} else {
+		vuln := &osvVulnerability{}
+		if err := json.Unmarshal(content, vuln); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		vulns = append(vulns, vuln)
+	}
+
+	for _, vuln := range vulns {
+		if vuln.ID == "" {
+			return fmt.Errorf("%s: OSV record without id", name)
+		}
+
+		db.vulns[vuln.ID] = vuln
+
+		seen := make(map[string]bool)
+		for _, affected := range vuln.Affected {
+			name := affected.Package.Name
+			if affected.Package.Ecosystem == osvEcosystem && !seen[name] {
+				seen[name] = true
+				db.byModule[name] = append(db.byModule[name], vuln)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (db *osvDatabase) queryBatch(versions []moduleVersion) ([][]string, error) {
+	ids := make([][]string, len(versions))
+
+	for i, v := range versions {
+		for _, vuln := range db.byModule[v.path] {
+			if vuln.affects(v.path, v.version) {
+				ids[i] = append(ids[i], vuln.ID)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+func (db *osvDatabase) vulnerability(id string) (*osvVulnerability, error) {
+	vuln, ok := db.vulns[id]
+	if !ok {
+		return nil, fmt.Errorf("osv: %s not found in the database", id)
+	}
+
+	return vuln, nil
+}
+
+// affects reports whether the given version of the module is affected, by an affected range or a listed version.
+// Only SEMVER and ECOSYSTEM ranges are evaluated, GIT ranges need the repository.
+func (v *osvVulnerability) affects(modulePath, version string) bool {
+	ver, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+
+	for _, affected := range v.Affected {
+		if affected.Package.Ecosystem != osvEcosystem || affected.Package.Name != modulePath {
+			continue
+		}
+
+		for _, listed := range affected.Versions {
+			if strings.TrimPrefix(listed, "v") == strings.TrimPrefix(version, "v") {
+				return true
+			}
+		}
+
+		for _, r := range affected.Ranges {
+			if (r.Type == "SEMVER" || r.Type == "ECOSYSTEM") && inRange(r.Events, ver) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// inRange evaluates the events of a range in version order, an introduced event starts an affected interval
+// which a fixed event ends before its version and a last_affected event after its version.
+func inRange(events []osvEvent, version *semver.Version) bool {
+	type event struct {
+		osvEvent
+		// version is nil for introduced "0", i.e. every version.
+		version *semver.Version
+	}
+
+	sorted := make([]event, 0, len(events))
+
+	for _, e := range events {
+		s := e.Introduced + e.Fixed + e.LastAffected
+		if s == "0" && e.Introduced != "" {
+			sorted = append(sorted, event{osvEvent: e})
+			continue
+		}
+
+		v, err := semver.NewVersion(s)
+		if err != nil {
+			continue
+		}
+
+		sorted = append(sorted, event{osvEvent: e, version: v})
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].version == nil || sorted[j].version == nil {
+			return sorted[i].version == nil && sorted[j].version != nil
+		}
+
+		return sorted[i].version.LessThan(sorted[j].version)
+	})
+
+	affected := false
+
+	for _, e := range sorted {
+		switch {
+		case e.Introduced != "":
+			if e.version != nil && version.LessThan(e.version) {
+				return affected
+			}
+			affected = true
+		case e.Fixed != "":
+			if version.LessThan(e.version) {
+				return affected
+			}
+			affected = false
+		case e.LastAffected != "":
+			if !version.GreaterThan(e.version) {
+				return affected
+			}
+			affected = false
+		}
+	}
+
+	return affected
+}
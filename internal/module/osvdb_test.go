@@ -0,0 +1,150 @@
+package module
+
+import (
+	"archive/zip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdvisory = `{
+	"id": "GO-2021-0001",
+	"aliases": ["GHSA-aaaa-bbbb-cccc"],
+	"summary": "remote code execution",
+	"affected": [{
+		"package": {"name": "github.com/a/b", "ecosystem": "Go"},
+		"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.2.0"}]}]
+	}]
+}`
+
+const testAdvisories = `[{
+	"id": "GHSA-aaaa-bbbb-cccc",
+	"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+	"affected": [{"package": {"name": "github.com/a/b", "ecosystem": "Go"}}]
+}, {
+	"id": "GO-2021-0002",
+	"summary": "denial of service",
+	"affected": [{
+		"package": {"name": "github.com/c/d", "ecosystem": "Go"},
+		"ranges": [{"type": "SEMVER", "events": [{"introduced": "2.0.0"}, {"last_affected": "2.1.0"}]}],
+		"versions": ["1.5.0"]
+	}]
+}]`
+
+func TestLoadOSVDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osvdb")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	records := filepath.Join(dir, "records")
+	require.NoError(t, os.MkdirAll(filepath.Join(records, "go"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(records, "go", "GO-2021-0001.json"), []byte(testAdvisory), 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(records, "all.json"), []byte(testAdvisories), 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(records, "README.md"), []byte("not a record"), 0666))
+
+	archive := filepath.Join(dir, "all.zip")
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{"GO-2021-0001.json": testAdvisory, "all.json": testAdvisories} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	versions := []moduleVersion{
+		{path: "github.com/a/b", version: "v1.1.0"},
+		{path: "github.com/a/b", version: "v1.2.0"},
+		{path: "github.com/c/d", version: "v1.5.0"},
+		{path: "github.com/c/d", version: "v2.1.0"},
+		{path: "github.com/c/d", version: "v2.1.1"},
+		{path: "github.com/e/f", version: "v1.0.0"},
+	}
+
+	for _, path := range []string{records, archive} {
+		db, err := loadOSVDatabase(path)
+		require.NoError(t, err, path)
+		assert.Len(t, db.vulns, 3, path)
+
+		ids, err := db.queryBatch(versions)
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"GO-2021-0001"}, nil, {"GO-2021-0002"}, {"GO-2021-0002"}, nil, nil}, ids, path)
+
+		_, err = db.vulnerability("GO-2021-9999")
+		assert.Error(t, err)
+	}
+
+	_, err = loadOSVDatabase(filepath.Join(records, "README.md"))
+	assert.Error(t, err)
+
+	_, err = loadOSVDatabase(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestVulnerabilityScan_Database(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osvdb")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "GO-2021-0001.json"), []byte(testAdvisory), 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "all.json"), []byte(testAdvisories), 0666))
+
+	db, err := loadOSVDatabase(dir)
+	require.NoError(t, err)
+
+	packages := []PackageResult{
+		{Path: "github.com/a/b", LocalVersion: semver.MustParse("v1.0.0")},
+		{Path: "github.com/c/d", LocalVersion: semver.MustParse("v3.0.0")},
+	}
+
+	result, err := vulnerabilityScan(context.Background(), db, packages, internal.ScanOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result["github.com/c/d"].Vulnerabilities)
+
+	vulnerabilities := result["github.com/a/b"].Vulnerabilities
+	require.Len(t, vulnerabilities, 1)
+	assert.Equal(t, "GO-2021-0001", vulnerabilities[0].ID)
+	assert.Equal(t, "v1.2.0", vulnerabilities[0].Fixed)
+	// the rating comes from the GHSA alias in the database.
+	assert.Equal(t, internal.SeverityCritical, vulnerabilities[0].Rating)
+
+	streamed := make(map[string]internal.VulnerabilityResult)
+	result, err = vulnerabilityScan(context.Background(), db, packages, internal.ScanOptions{
+		Stream: func(path string, result internal.VulnerabilityResult) {
+			streamed[path] = result
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result, "streamed results aren't collected")
+	assert.Len(t, streamed, 2)
+	assert.Len(t, streamed["github.com/a/b"].Vulnerabilities, 1)
+}
+
+func TestInRange(t *testing.T) {
+	events := []osvEvent{{Fixed: "0.9.0"}, {Introduced: "0"}, {Introduced: "1.0.0"}, {Fixed: "1.2.0"}, {Introduced: "2.0.0"}, {LastAffected: "2.1.0"}}
+
+	tests := map[string]bool{
+		"v0.0.0-20200101000000-abcdefabcdef": true,
+		"v0.8.0":                             true,
+		"v0.9.0":                             false,
+		"v1.0.0":                             true,
+		"v1.1.9":                             true,
+		"v1.2.0":                             false,
+		"v2.1.0":                             true,
+		"v2.1.1":                             false,
+	}
+
+	for version, expected := range tests {
+		assert.Equal(t, expected, inRange(events, semver.MustParse(version)), version)
+	}
+}
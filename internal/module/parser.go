@@ -4,13 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
-	"github.com/spf13/viper"
+	"github.com/beatlabs/gomodctl/internal"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
 var regex = regexp.MustCompile(`({([^}]*)})`)
@@ -18,13 +24,14 @@ var regex = regexp.MustCompile(`({([^}]*)})`)
 var go115 = semver.MustParse("1.15.0")
 
 type item struct {
-	Path     string   `json:"Path"`
-	Version  string   `json:"Version"`
-	Versions []string `json:"Versions"`
-	Indirect bool     `json:"Indirect"`
-	Main     bool     `json:"Main"`
-	Dir      string   `json:"Dir"`
-	GoMod    string   `json:"GoMod"`
+	Path     string    `json:"Path"`
+	Version  string    `json:"Version"`
+	Versions []string  `json:"Versions"`
+	Indirect bool      `json:"Indirect"`
+	Main     bool      `json:"Main"`
+	Dir      string    `json:"Dir"`
+	GoMod    string    `json:"GoMod"`
+	Time     time.Time `json:"Time"`
 }
 
 // NewModParser creates a new ModParser.
@@ -39,35 +46,140 @@ type ModParser struct {
 
 // PackageResult contains module specific information.
 type PackageResult struct {
-	Path              string
-	LocalVersion      *semver.Version
+	Path         string
+	LocalVersion *semver.Version
+	// AvailableVersions isn't filled by Parse, versions are resolved by version check.
 	AvailableVersions []*semver.Version
 	Dir               string
+	// Replaced is true when the module is redirected by a replace directive,
+	// in that case LocalVersion and AvailableVersions belong to ReplacePath.
+	Replaced    bool
+	ReplacePath string
+	// Version is the selected version of Path, it differs from LocalVersion when the module is replaced.
+	Version string
+	// Indirect is true for modules which aren't imported by the main module,
+	// Parse returns only those of them which are required in go.mod with an // indirect comment.
+	Indirect bool
+	// Main is only set by ParseAll.
+	Main bool
+	// Position is where go.mod requires the module, ReplacePosition where it replaces it.
+	// Both are zero if there is no such directive, e.g. for modules required only transitively.
+	Position        internal.Position
+	ReplacePosition internal.Position
 }
 
-// Parse is exported
+// ResolvePath returns path of the module that versions are resolved for.
+func (p PackageResult) ResolvePath() string {
+	if p.Replaced {
+		return p.ReplacePath
+	}
+
+	return p.Path
+}
+
+// Parse returns modules required by go.mod.
 func (v *ModParser) Parse(path string) ([]PackageResult, error) {
+	return v.parse(path, false)
+}
+
+// ParseAll returns the main module and all of its direct and indirect dependencies.
+// The main module has no LocalVersion.
+func (v *ModParser) ParseAll(path string) ([]PackageResult, error) {
+	return v.parse(path, true)
+}
+
+// ParseFile returns modules required by the given go.mod file, or by go.mod in the given directory.
+// Unlike Parse it only reads the file, so the module graph isn't loaded and nothing is downloaded.
+func (v *ModParser) ParseFile(path string) ([]PackageResult, error) {
+	file, err := absDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(file); err == nil && info.IsDir() {
+		file = filepath.Join(file, goMod)
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRequirements(file, content)
+}
+
+// parseRequirements returns modules required by go.mod content, file is its name in errors.
+func parseRequirements(file string, content []byte) ([]PackageResult, error) {
+	goModFile, err := modfile.Parse(file, content, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PackageResult, 0, len(goModFile.Require))
+
+	for _, r := range goModFile.Require {
+		packageResult := PackageResult{
+			Path:     r.Mod.Path,
+			Version:  r.Mod.Version,
+			Indirect: r.Indirect,
+			Position: position(r.Syntax),
+		}
+
+		version := r.Mod.Version
+
+		rep := findReplace(goModFile.Replace, r.Mod.Path, r.Mod.Version)
+		if rep != nil {
+			packageResult.Replaced = true
+			packageResult.ReplacePath = rep.New.Path
+			packageResult.ReplacePosition = position(rep.Syntax)
+			// local path replacements have no version.
+			version = rep.New.Version
+		}
+
+		if version != "" {
+			var err error
+			packageResult.LocalVersion, err = semver.NewVersion(version)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%s: invalid version of %s: %w", file, packageResult.Position, r.Mod.Path, err)
+			}
+		}
+
+		result = append(result, packageResult)
+	}
+
+	return result, nil
+}
+
+func (v *ModParser) parse(path string, all bool) ([]PackageResult, error) {
 	goVersion, err := v.goRuntimeVersion()
 	if err != nil {
 		return nil, err
 	}
 
-	args := []string{"list", "-m", "-versions", "-json", "-mod=mod", "all"}
+	args := []string{"list", "-m", "-json", "-mod=mod", "all"}
 	if goVersion.LessThan(go115) {
-		args = []string{"list", "-m", "-versions", "-json", "all"}
+		args = []string{"list", "-m", "-json", "all"}
+	}
+
+	dir, err := moduleDir(path)
+	if err != nil {
+		return nil, err
 	}
 
 	cmd := exec.CommandContext(v.ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = goEnv()
 
-	if path != "" {
-		home := viper.GetString("home")
+	goModFile, err := parseGoMod(cmd.Dir)
+	if err != nil {
+		return nil, err
+	}
 
-		if strings.HasPrefix(path, home) {
-			l := path[len(home):]
-			cmd.Dir = filepath.Join(home, l)
-		} else {
-			cmd.Dir = filepath.Join(home, path)
-		}
+	replaces := goModFile.Replace
+
+	required := make(map[string]*modfile.Require)
+	for _, r := range goModFile.Require {
+		required[r.Mod.Path] = r
 	}
 
 	out, err := cmd.CombinedOutput()
@@ -92,25 +204,292 @@ func (v *ModParser) Parse(path string) ([]PackageResult, error) {
 			return nil, err
 		}
 
-		if !it.Indirect && !it.Main {
-			availableVersions := make([]*semver.Version, len(it.Versions))
+		if all || (!it.Main && (!it.Indirect || required[it.Path] != nil)) {
+			packageResult := PackageResult{
+				Path: it.Path,
+				Dir:  it.Dir,
+			}
 
-			for i, version := range it.Versions {
-				availableVersions[i] = semver.MustParse(version)
+			if r := required[it.Path]; r != nil {
+				packageResult.Position = position(r.Syntax)
 			}
 
-			result = append(result, PackageResult{
-				Path:              it.Path,
-				LocalVersion:      semver.MustParse(it.Version),
-				Dir:               it.Dir,
-				AvailableVersions: availableVersions,
-			})
+			if it.Main {
+				packageResult.Main = true
+				result = append(result, packageResult)
+				continue
+			}
+
+			packageResult.Indirect = it.Indirect
+			packageResult.Version = it.Version
+
+			rep := findReplace(replaces, it.Path, it.Version)
+			if rep != nil {
+				// local path replacements can't be checked against a registry.
+				if rep.New.Version == "" {
+					continue
+				}
+
+				it.Version = rep.New.Version
+				packageResult.Replaced = true
+				packageResult.ReplacePath = rep.New.Path
+				packageResult.ReplacePosition = position(rep.Syntax)
+			}
+
+			packageResult.LocalVersion = semver.MustParse(it.Version)
+
+			result = append(result, packageResult)
 		}
 	}
 
 	return result, nil
 }
 
+// BuildModules returns modules providing packages imported by non-test packages of the main module, including the main module
+// itself, and those providing packages imported by its tests only. Modules of the module graph which provide no imported
+// package are in neither.
+func (v *ModParser) BuildModules(path string) (production, testOnly map[string]bool, err error) {
+	dir, err := moduleDir(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	production, err = v.depModules(dir, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	withTests, err := v.depModules(dir, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	testOnly = make(map[string]bool)
+	for path := range withTests {
+		if !production[path] {
+			testOnly[path] = true
+		}
+	}
+
+	return production, testOnly, nil
+}
+
+// depModules returns modules of the packages of the main module in dir and their dependencies, with tests optionally.
+func (v *ModParser) depModules(dir string, tests bool) (map[string]bool, error) {
+	args := []string{"list", "-deps", "-f", "{{with .Module}}{{.Path}}{{end}}"}
+	if tests {
+		args = append(args, "-test")
+	}
+
+	cmd := exec.CommandContext(v.ctx, "go", append(args, "./...")...)
+	cmd.Dir = dir
+	cmd.Env = goEnv()
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("go list -deps with output [%s] %w", strings.TrimSpace(string(out)), err)
+	}
+
+	modules := make(map[string]bool)
+	for _, path := range strings.Fields(string(out)) {
+		modules[path] = true
+	}
+
+	return modules, nil
+}
+
+// Graph returns requirements of each module in the module graph of the module in the given path.
+// The main module has no version.
+func (v *ModParser) Graph(path string) (map[module.Version][]module.Version, error) {
+	dir, err := moduleDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(v.ctx, "go", "mod", "graph")
+	cmd.Dir = dir
+	cmd.Env = goEnv()
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	graph := make(map[module.Version][]module.Version)
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		from, to := splitModuleVersion(fields[0]), splitModuleVersion(fields[1])
+		graph[from] = append(graph[from], to)
+	}
+
+	return graph, nil
+}
+
+// splitModuleVersion splits path@version of go mod graph output.
+func splitModuleVersion(s string) module.Version {
+	i := strings.LastIndex(s, "@")
+	if i < 0 {
+		return module.Version{Path: s}
+	}
+
+	return module.Version{Path: s[:i], Version: s[i+1:]}
+}
+
+// absDir returns absolute directory of the given path, the current directory if path is empty.
+// Relative paths are relative to the current directory, a leading ~ is the home directory.
+// internal.StdinPath is a temporary directory with go.mod read from stdin.
+func absDir(path string) (string, error) {
+	if path == internal.StdinPath {
+		return stdinDir()
+	}
+
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(path)
+}
+
+// expandHome replaces a leading ~ of the path with the home directory of the user.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+// moduleDir returns absolute directory of the module in the given path, see absDir.
+// It returns an error if there is no go.mod in the directory.
+func moduleDir(path string) (string, error) {
+	dir, err := absDir(path)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, goMod)); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no go.mod found in %s", dir)
+		}
+
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// parseGoMod reads go.mod in the given directory.
+func parseGoMod(dir string) (*modfile.File, error) {
+	file := filepath.Join(dir, "go.mod")
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return modfile.Parse(file, content, nil)
+}
+
+// parseWorkspace returns directories of modules used by go.work in the given directory.
+// It returns nil if there is no go.work in the directory.
+func parseWorkspace(dir string) ([]string, error) {
+	file := filepath.Join(dir, "go.work")
+
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// go.work shares the syntax of go.mod, its use directives are kept as unknown statements by ParseLax.
+	parse, err := modfile.ParseLax(file, content, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+
+	addUse := func(token string) error {
+		if strings.HasPrefix(token, `"`) {
+			token, err = strconv.Unquote(token)
+			if err != nil {
+				return fmt.Errorf("%s: invalid use directive: %w", file, err)
+			}
+		}
+
+		dirs = append(dirs, token)
+
+		return nil
+	}
+
+	for _, stmt := range parse.Syntax.Stmt {
+		switch x := stmt.(type) {
+		case *modfile.Line:
+			if len(x.Token) == 2 && x.Token[0] == "use" {
+				if err := addUse(x.Token[1]); err != nil {
+					return nil, err
+				}
+			}
+		case *modfile.LineBlock:
+			if len(x.Token) == 1 && x.Token[0] == "use" {
+				for _, line := range x.Line {
+					if len(line.Token) == 1 {
+						if err := addUse(line.Token[0]); err != nil {
+							return nil, err
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return dirs, nil
+}
+
+// position returns where the directive starts in go.mod, zero if the directive isn't parsed from a file.
+func position(line *modfile.Line) internal.Position {
+	if line == nil {
+		return internal.Position{}
+	}
+
+	return internal.Position{Line: line.Start.Line, Column: line.Start.LineRune}
+}
+
+// findReplace returns the replace directive applied to the given module version, nil if there is none.
+// A replace with a version on the left side takes precedence over the one without.
+func findReplace(replaces []*modfile.Replace, path, version string) *modfile.Replace {
+	var found *modfile.Replace
+
+	for _, rep := range replaces {
+		if rep.Old.Path != path {
+			continue
+		}
+
+		if rep.Old.Version == version {
+			return rep
+		}
+
+		if rep.Old.Version == "" {
+			found = rep
+		}
+	}
+
+	return found
+}
+
 func (v *ModParser) goRuntimeVersion() (*semver.Version, error) {
 	cmd := exec.CommandContext(v.ctx, "go", "version")
 
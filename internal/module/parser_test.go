@@ -0,0 +1,190 @@
+package module
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+func TestFindReplace(t *testing.T) {
+	content := []byte(`module example.com/test
+
+go 1.15
+
+require (
+	github.com/a/b v1.2.3
+	github.com/c/d v1.0.0
+)
+
+replace github.com/a/b => ../b
+
+replace github.com/a/b v1.2.3 => github.com/a/b v1.2.4
+
+replace github.com/c/d => github.com/e/d v1.1.0
+`)
+
+	parse, err := modfile.Parse("go.mod", content, nil)
+	assert.NoError(t, err)
+
+	rep := findReplace(parse.Replace, "github.com/a/b", "v1.2.3")
+	assert.NotNil(t, rep)
+	assert.Equal(t, "v1.2.4", rep.New.Version)
+
+	rep = findReplace(parse.Replace, "github.com/a/b", "v1.0.0")
+	assert.NotNil(t, rep)
+	assert.Equal(t, "../b", rep.New.Path)
+	assert.Empty(t, rep.New.Version)
+
+	rep = findReplace(parse.Replace, "github.com/c/d", "v1.0.0")
+	assert.NotNil(t, rep)
+	assert.Equal(t, "github.com/e/d", rep.New.Path)
+
+	assert.Nil(t, findReplace(parse.Replace, "github.com/x/y", "v1.0.0"))
+}
+
+func TestParseWorkspace(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	uses, err := parseWorkspace(tempDir)
+	assert.NoError(t, err)
+	assert.Nil(t, uses)
+
+	content := []byte(`go 1.18
+
+use ./tools
+
+use (
+	./services/a
+	"./services/b"
+)
+`)
+
+	err = ioutil.WriteFile(filepath.Join(tempDir, "go.work"), content, 0666)
+	assert.NoError(t, err)
+
+	uses, err = parseWorkspace(tempDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"./tools", "./services/a", "./services/b"}, uses)
+}
+
+func TestSplitModuleVersion(t *testing.T) {
+	assert.Equal(t, module.Version{Path: "example.com/test"}, splitModuleVersion("example.com/test"))
+	assert.Equal(t, module.Version{Path: "github.com/a/b", Version: "v1.2.3"}, splitModuleVersion("github.com/a/b@v1.2.3"))
+}
+
+func TestModuleDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gomodctl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = moduleDir(dir)
+	assert.EqualError(t, err, "no go.mod found in "+dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/test\n"), 0644))
+
+	got, err := moduleDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, dir, got)
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	got, err = absDir("")
+	assert.NoError(t, err)
+	assert.Equal(t, wd, got)
+}
+
+func TestAbsDirHome(t *testing.T) {
+	home, err := ioutil.TempDir("", "gomodctl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	assert.NoError(t, os.Setenv("HOME", home))
+
+	got, err := absDir("~/projects/x")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "projects", "x"), got)
+
+	got, err = absDir("~")
+	assert.NoError(t, err)
+	assert.Equal(t, home, got)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(home, "projects", "x"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(home, "projects", "x", "go.mod"), []byte("module example.com/x\n"), 0644))
+
+	dirs, err := globModules("~/projects/*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(home, "projects", "x")}, dirs)
+}
+
+func TestParseRequirements(t *testing.T) {
+	content := "module example.com/test\n\ngo 1.15\n\nrequire example.com/a v1.0.0\n\nrequire (\n\texample.com/b v1.1.0 // indirect\n\texample.com/c v1.2.0\n)\n\nreplace example.com/c => example.com/d v1.3.0\n"
+
+	results, err := parseRequirements("go.mod", []byte(content))
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	assert.Equal(t, "example.com/a", results[0].Path)
+	assert.Equal(t, internal.Position{Line: 5, Column: 1}, results[0].Position)
+
+	assert.True(t, results[1].Indirect)
+	assert.Equal(t, internal.Position{Line: 8, Column: 2}, results[1].Position)
+
+	assert.Equal(t, internal.Position{Line: 9, Column: 2}, results[2].Position)
+	assert.True(t, results[2].Replaced)
+	assert.Equal(t, internal.Position{Line: 12, Column: 1}, results[2].ReplacePosition)
+	assert.Equal(t, "example.com/d", results[2].ResolvePath())
+	assert.Equal(t, "v1.3.0", results[2].LocalVersion.Original())
+	assert.False(t, results[0].ReplacePosition.IsValid())
+}
+
+func TestBuildModules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gomodctl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	goModContent := []byte(`module example.com/test
+
+go 1.17
+
+require (
+	example.com/a v0.0.0
+	example.com/b v0.0.0
+	example.com/c v0.0.0
+)
+
+replace (
+	example.com/a => ./a
+	example.com/b => ./b
+	example.com/c => ./c
+)
+`)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.mod"), goModContent, 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nimport _ \"example.com/a\"\n\nfunc main() {}\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main_test.go"), []byte("package main\n\nimport _ \"example.com/b\"\n"), 0644))
+
+	// example.com/c is required but no package imports it.
+	for _, name := range []string{"a", "b", "c"} {
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, name), 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name, "go.mod"), []byte("module example.com/"+name+"\n\ngo 1.15\n"), 0644))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name, name+".go"), []byte("package "+name+"\n"), 0644))
+	}
+
+	parser := NewModParser(context.Background())
+
+	production, testOnly, err := parser.BuildModules(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com/test": true, "example.com/a": true}, production)
+	assert.Equal(t, map[string]bool{"example.com/b": true}, testOnly)
+}
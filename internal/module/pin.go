@@ -0,0 +1,130 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// ErrVersionNotFound is returned when a pinned version of a module doesn't exist.
+var ErrVersionNotFound = errors.New("version not found")
+
+// ErrDowngrade is returned when a pinned version is lower than the current one and downgrades aren't allowed.
+var ErrDowngrade = errors.New("downgrade not allowed")
+
+// Pin sets the required versions of the given modules in go.mod, other requirements aren't updated.
+// Every module has to be required by go.mod and its version has to exist, a downgrade is an error unless
+// options.AllowDowngrade is set.
+// go.mod is written like by Apply, then go mod tidy is run unless options.Verify already does it.
+func (u *Updater) Pin(path string, targets []internal.Requirement, options internal.UpdateOptions) (map[string]internal.CheckResult, error) {
+	if !options.DryRun {
+		if err := checkWritable(path); err != nil {
+			return nil, err
+		}
+	}
+
+	parser := ModParser{ctx: u.Ctx}
+
+	results, err := parser.ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	required := make(map[string]PackageResult, len(results))
+	for _, result := range results {
+		required[result.Path] = result
+	}
+
+	privatePatterns, err := getPrivatePatterns(u.Ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := newVersionResolver(u.Ctx, privatePatterns)
+
+	pins := make(map[string]internal.CheckResult, len(targets))
+
+	for _, target := range targets {
+		result, ok := required[target.Path]
+		if !ok {
+			return nil, fmt.Errorf("%s isn't required by go.mod", target.Path)
+		}
+
+		if result.Replaced {
+			return nil, fmt.Errorf("%s is replaced, change its replace directive instead", target.Path)
+		}
+
+		version, err := pinnedVersion(resolver, target)
+		if err != nil {
+			return nil, err
+		}
+
+		if version.LessThan(result.LocalVersion) && !options.AllowDowngrade {
+			return nil, fmt.Errorf("%s@%s is a downgrade from %s, use --allow-downgrade: %w", target.Path, target.Version, result.LocalVersion.Original(), ErrDowngrade)
+		}
+
+		pins[target.Path] = internal.CheckResult{LocalVersion: result.LocalVersion, LatestVersion: version, Indirect: result.Indirect}
+	}
+
+	if err := u.apply(path, pins, options, options.AllowDowngrade); err != nil {
+		return nil, err
+	}
+
+	if options.DryRun || options.Verify {
+		return pins, nil
+	}
+
+	dir, err := moduleDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tidy(u.Ctx, dir); err != nil {
+		return nil, err
+	}
+
+	return pins, nil
+}
+
+// pinnedVersion returns the version of the target, it has to be listed by the proxy or, e.g. for pseudo-versions, be downloadable.
+func pinnedVersion(resolver *versionResolver, target internal.Requirement) (*semver.Version, error) {
+	version, err := semver.NewVersion(target.Version)
+	if err != nil || !strings.HasPrefix(target.Version, "v") {
+		return nil, fmt.Errorf("invalid version %q of %s, e.g. v1.2.3 is expected", target.Version, target.Path)
+	}
+
+	versions, err := resolver.Versions(target.Path)
+	if err != nil {
+		return nil, fmt.Errorf("versions of %s: %w", target.Path, err)
+	}
+
+	for _, v := range versions {
+		if v.Original() == target.Version {
+			return version, nil
+		}
+	}
+
+	if _, err := resolver.goMod(target.Path, target.Version); err != nil {
+		return nil, fmt.Errorf("%s@%s: %w", target.Path, target.Version, ErrVersionNotFound)
+	}
+
+	return version, nil
+}
+
+// tidy runs go mod tidy in the given module directory.
+func tidy(ctx context.Context, dir string) error {
+	cmd := exec.CommandContext(ctx, "go", "mod", "tidy")
+	cmd.Dir = dir
+	cmd.Env = goEnv()
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return &VerifyError{Command: "go mod tidy", Output: string(out), Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"github.com/spf13/viper"
+)
+
+// DefaultGoProxy is used when neither proxy config nor GOPROXY is set.
+const DefaultGoProxy = "https://proxy.golang.org"
+
+// GoProxy returns the proxy list used to resolve versions.
+// It accepts the same comma separated syntax as GOPROXY, the proxy config key takes precedence over GOPROXY.
+func GoProxy() string {
+	if proxy := strings.TrimSpace(viper.GetString("proxy")); proxy != "" {
+		return proxy
+	}
+
+	if proxy := strings.TrimSpace(os.Getenv("GOPROXY")); proxy != "" {
+		return proxy
+	}
+
+	return DefaultGoProxy
+}
+
+// ErrProxyOff is returned when the proxy list disables module lookups, like GOPROXY=off does for the go command.
+var ErrProxyOff = errors.New("module lookup disabled by GOPROXY=off")
+
+// Proxy is an entry of the proxy list, see GoProxies.
+type Proxy struct {
+	URL string
+	// FallbackOnError is true if the entry is followed by a pipe, the next entry is tried after any error then.
+	// After a comma only 404 and 410 responses fall back, like the go command does.
+	FallbackOnError bool
+}
+
+// GoProxies returns the proxies of the list in order, up to the first direct or off entry, see GoProxyDirect.
+// The list is empty for direct only, versions are resolved by the go toolchain then. ErrProxyOff is returned if off
+// comes before any proxy.
+func GoProxies() ([]Proxy, error) {
+	var proxies []Proxy
+
+	for _, entry := range proxyEntries(GoProxy()) {
+		switch entry.URL {
+		case "off":
+			if len(proxies) == 0 {
+				return nil, ErrProxyOff
+			}
+
+			return proxies, nil
+		case "direct":
+			return proxies, nil
+		}
+
+		proxies = append(proxies, entry)
+	}
+
+	return proxies, nil
+}
+
+// GoProxyDirect reports whether the proxies of GoProxies are followed by direct, modules none of them knows are
+// resolved by the go toolchain then.
+func GoProxyDirect() bool {
+	for _, entry := range proxyEntries(GoProxy()) {
+		if entry.URL == "direct" || entry.URL == "off" {
+			return entry.URL == "direct"
+		}
+	}
+
+	return false
+}
+
+// proxyEntries splits the proxy list at commas and pipes, direct and off are entries too.
+func proxyEntries(list string) []Proxy {
+	var entries []Proxy
+
+	for list != "" {
+		entry, separator := list, ""
+		if i := strings.IndexAny(list, ",|"); i >= 0 {
+			entry, separator, list = list[:i], list[i:i+1], list[i+1:]
+		} else {
+			list = ""
+		}
+
+		entry = strings.TrimSuffix(strings.TrimSpace(entry), "/")
+		if entry != "" {
+			entries = append(entries, Proxy{URL: entry, FallbackOnError: separator == "|"})
+		}
+	}
+
+	return entries
+}
+
+// goEnv returns environment for go commands run by gomodctl.
+// Every module is listed on its own, workspaces are handled by the caller.
+func goEnv() []string {
+	return append(os.Environ(), "GOWORK=off", "GOPROXY="+GoProxy())
+}
+
+// getPrivatePatterns returns module path patterns of GOPRIVATE and GONOSUMDB as a comma separated list.
+// go env is used since these may be set in the go env file too, the environment if the go command fails.
+// Only a done context fails it.
+func getPrivatePatterns(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", "env", "GOPRIVATE", "GONOSUMDB")
+	cmd.Env = goEnv()
+
+	out, err := cmd.Output()
+	if err != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	// without a working go command the environment is the only source, modules are still checked.
+	if err != nil {
+		logger.Warnf("go env GOPRIVATE GONOSUMDB failed, using the environment for private modules: %v", err)
+		out = []byte(os.Getenv("GOPRIVATE") + " " + os.Getenv("GONOSUMDB"))
+	}
+
+	return strings.Join(strings.Fields(string(out)), ","), nil
+}
+
+// getGoVersion returns the Go version update candidates must support, the version of the go command if none is given.
+// A go command whose version can't be resolved, e.g. a development build, disables the check.
+func getGoVersion(ctx context.Context, version string) string {
+	if version != "" {
+		return strings.TrimPrefix(version, "go")
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "env", "GOVERSION")
+	cmd.Env = goEnv()
+
+	out, err := cmd.Output()
+	if err != nil || !strings.HasPrefix(string(out), "go") {
+		logger.Debugf("go version of the toolchain not resolved, candidates aren't checked for it: %v", err)
+		return ""
+	}
+
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), "go")
+}
+
+// compareGoVersions compares Go versions, e.g. 1.21, 1.21.3 or 1.22rc1, an empty version is equal to every version.
+func compareGoVersions(a, b string) int {
+	if a == "" || b == "" {
+		return 0
+	}
+
+	releaseA, preA := goVersionParts(a)
+	releaseB, preB := goVersionParts(b)
+
+	for i := range releaseA {
+		if releaseA[i] != releaseB[i] {
+			if releaseA[i] < releaseB[i] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	case preA < preB:
+		return -1
+	}
+
+	return 1
+}
+
+// goVersionParts splits a Go version into its release numbers and prerelease, e.g. [1 22 0] and rc1 for 1.22rc1.
+func goVersionParts(version string) ([3]int, string) {
+	var release [3]int
+
+	v := strings.TrimPrefix(version, "go")
+
+	for i := range release {
+		j := 0
+		for j < len(v) && '0' <= v[j] && v[j] <= '9' {
+			release[i] = release[i]*10 + int(v[j]-'0')
+			j++
+		}
+
+		v = v[j:]
+		if !strings.HasPrefix(v, ".") {
+			break
+		}
+
+		v = v[1:]
+	}
+
+	return release, v
+}
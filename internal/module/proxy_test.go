@@ -0,0 +1,85 @@
+package module
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoProxy(t *testing.T) {
+	goProxy := os.Getenv("GOPROXY")
+	defer os.Setenv("GOPROXY", goProxy)
+	defer viper.Set("proxy", nil)
+
+	os.Setenv("GOPROXY", "")
+	assert.Equal(t, DefaultGoProxy, GoProxy())
+	assertProxies(t, []Proxy{{URL: DefaultGoProxy}}, false)
+
+	os.Setenv("GOPROXY", "https://env.example.com,direct")
+	assert.Equal(t, "https://env.example.com,direct", GoProxy())
+	assertProxies(t, []Proxy{{URL: "https://env.example.com"}}, true)
+
+	viper.Set("proxy", "https://athens.example.com/,https://proxy.golang.org|direct")
+	assertProxies(t, []Proxy{{URL: "https://athens.example.com"}, {URL: "https://proxy.golang.org", FallbackOnError: true}}, true)
+
+	viper.Set("proxy", "direct")
+	assertProxies(t, nil, true)
+
+	viper.Set("proxy", "https://athens.example.com,off")
+	assertProxies(t, []Proxy{{URL: "https://athens.example.com"}}, false)
+
+	viper.Set("proxy", "off")
+	_, err := GoProxies()
+	assert.ErrorIs(t, err, ErrProxyOff)
+	assert.False(t, GoProxyDirect())
+}
+
+func assertProxies(t *testing.T, expected []Proxy, direct bool) {
+	t.Helper()
+
+	proxies, err := GoProxies()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, proxies)
+	assert.Equal(t, direct, GoProxyDirect())
+}
+
+func TestProxyList_FallbackOnError(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("v1.0.0\n"))
+	}))
+	defer server.Close()
+
+	// the broken proxy isn't retried.
+	viper.Set("retries", 0)
+	viper.Set("no_cache", true)
+	defer viper.Set("retries", nil)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	viper.Set("proxy", broken.URL+"|"+server.URL)
+
+	versions, err := newVersionResolver(context.Background(), "").rawVersions("example.com/a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1.0.0"}, versions, "any error falls back after a pipe")
+
+	viper.Set("proxy", broken.URL+","+server.URL)
+
+	_, err = newVersionResolver(context.Background(), "").rawVersions("example.com/a")
+	assert.ErrorIs(t, err, internal.ErrProxy, "only not found falls back after a comma")
+
+	viper.Set("proxy", "off")
+
+	_, err = newVersionResolver(context.Background(), "").rawVersions("example.com/a")
+	assert.ErrorIs(t, err, ErrProxyOff)
+}
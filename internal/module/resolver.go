@@ -0,0 +1,766 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/httpclient"
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	modsemver "golang.org/x/mod/semver"
+)
+
+// versionResolver resolves available versions of modules.
+// Versions are fetched from the configured proxies in order, private modules and
+// proxy lists ending with direct fall back to the go toolchain.
+type versionResolver struct {
+	ctx             context.Context
+	restClient      *resty.Client
+	cache           *versionCache
+	retractions     *versionCache
+	deprecations    *versionCache
+	times           *versionCache
+	goVersions      *versionCache
+	privatePatterns string
+	// successors enables resolving of modules with higher major version suffixes, see Successors.
+	successors bool
+	// goVersion is the Go version candidates must support, see resolveLatest. Empty disables the check.
+	goVersion string
+	// retries is how often RetriedVersions looks up versions again after a network error.
+	retries int
+}
+
+// retryBackoff is the wait before the first lookup again of RetriedVersions, it doubles with every retry up to maxRetryBackoff.
+// It is a variable so tests don't wait.
+var (
+	retryBackoff    = time.Second
+	maxRetryBackoff = 30 * time.Second
+)
+
+func newVersionResolver(ctx context.Context, privatePatterns string) *versionResolver {
+	return &versionResolver{
+		ctx:             ctx,
+		restClient:      httpclient.New(),
+		cache:           newVersionCache(),
+		retractions:     newRetractionCache(),
+		deprecations:    newDeprecationCache(),
+		times:           newTimeCache(),
+		goVersions:      newGoVersionCache(),
+		privatePatterns: privatePatterns,
+	}
+}
+
+// withContext returns a copy of the resolver whose requests and commands are canceled by ctx, caches are shared.
+func (r *versionResolver) withContext(ctx context.Context) *versionResolver {
+	c := *r
+	c.ctx = ctx
+
+	return &c
+}
+
+// disableCache makes the resolver look up everything again instead of reading the caches, see CheckOptions.NoCache.
+func (r *versionResolver) disableCache() {
+	r.cache, r.retractions, r.deprecations, r.times, r.goVersions = nil, nil, nil, nil, nil
+}
+
+// Versions returns available versions of the module, versions which aren't valid semver are skipped.
+func (r *versionResolver) Versions(modulePath string) ([]*semver.Version, error) {
+	versions, err := r.rawVersions(modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseVersions(versions), nil
+}
+
+// RetriedVersions returns Versions, which are looked up again up to retries times if they fail with a network error.
+// Requests are retried by the HTTP client already, this retries the whole lookup after a longer wait, including the
+// fallback to the go toolchain.
+func (r *versionResolver) RetriedVersions(modulePath string) ([]*semver.Version, error) {
+	versions, err := r.Versions(modulePath)
+
+	for retry := 0; retry < r.retries && errors.Is(err, internal.ErrNetwork); retry++ {
+		wait := retryBackoff << uint(retry)
+		if wait <= 0 || wait > maxRetryBackoff {
+			wait = maxRetryBackoff
+		}
+
+		logger.Debugf("versions of %s failed with a network error, retrying in %s: %v", modulePath, wait, err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-r.ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+
+		versions, err = r.Versions(modulePath)
+	}
+
+	return versions, err
+}
+
+// parseVersions parses the version list, versions which aren't valid semver are skipped.
+func parseVersions(versions []string) []*semver.Version {
+	result := make([]*semver.Version, 0, len(versions))
+
+	for _, version := range versions {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, v)
+	}
+
+	return result
+}
+
+func (r *versionResolver) rawVersions(modulePath string) ([]string, error) {
+	var stale cacheEntry
+
+	if r.cache != nil {
+		entry, fresh, _ := r.cache.lookup(modulePath)
+		if fresh {
+			return entry.Versions, nil
+		}
+
+		stale = entry
+	}
+
+	var entry cacheEntry
+	var err error
+
+	if module.MatchPrefixPatterns(r.privatePatterns, modulePath) {
+		entry.Versions, err = r.toolchainVersions(modulePath)
+	} else {
+		entry, err = r.proxyVersions(modulePath, stale)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if r.cache != nil {
+		// a failing cache shouldn't fail the check.
+		_ = r.cache.setEntry(modulePath, entry)
+	}
+
+	return entry.Versions, nil
+}
+
+// proxyVersions fetches the version list from each proxy in order until one knows the module.
+// If none of them does and the list doesn't end with direct, the module isn't found, which isn't cached as it may
+// be published later.
+func (r *versionResolver) proxyVersions(modulePath string, stale cacheEntry) (cacheEntry, error) {
+	entry, found, err := r.proxyList(modulePath, stale)
+	if err != nil || found {
+		return entry, err
+	}
+
+	if GoProxyDirect() {
+		versions, err := r.toolchainVersions(modulePath)
+		return cacheEntry{Versions: versions}, err
+	}
+
+	return cacheEntry{}, internal.Categorize(internal.ErrNotFound, ErrNoVersionAvailable)
+}
+
+// notFound reports whether the proxy doesn't know the module or version, the next proxy is tried then.
+func notFound(response *resty.Response) bool {
+	return response.StatusCode() == http.StatusNotFound || response.StatusCode() == http.StatusGone
+}
+
+// requestError categorizes a request which failed without a response as a network error,
+// unless the context is done, which isn't a failure of the network.
+func (r *versionResolver) requestError(err error) error {
+	if r.ctx.Err() != nil {
+		return err
+	}
+
+	return internal.Categorize(internal.ErrNetwork, err)
+}
+
+// proxyList fetches the version list from the configured proxies only, false if none of them knows the module.
+// If the stale entry was fetched from one of the proxies, the request to it is conditional and the entry is
+// returned again if the proxy answers 304 Not Modified.
+func (r *versionResolver) proxyList(modulePath string, stale cacheEntry) (cacheEntry, bool, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return cacheEntry{}, false, err
+	}
+
+	proxies, err := GoProxies()
+	if err != nil {
+		return cacheEntry{}, false, err
+	}
+
+	for _, proxy := range proxies {
+		listURL := fmt.Sprintf("%s/%s/@v/list", proxy.URL, escaped)
+		conditional := stale.URL == listURL && !stale.Validators.IsZero()
+
+		request := r.restClient.R().SetContext(r.ctx)
+		if conditional {
+			stale.Validators.Apply(request)
+		}
+
+		response, err := request.Get(listURL)
+		if err != nil && proxy.FallbackOnError && r.ctx.Err() == nil {
+			logger.Debugf("versions of %s failed on %s, trying the next proxy: %v", modulePath, proxy.URL, err)
+			continue
+		}
+		if err != nil {
+			return cacheEntry{}, false, r.requestError(err)
+		}
+
+		if conditional && httpclient.NotModified(response) {
+			logger.Debugf("versions of %s not modified on %s", modulePath, proxy.URL)
+			return stale, true, nil
+		}
+
+		// same as the go command, only not found falls back to the next proxy unless it is separated by a pipe.
+		if notFound(response) || proxy.FallbackOnError && !response.IsSuccess() {
+			continue
+		}
+
+		if !response.IsSuccess() {
+			return cacheEntry{}, false, internal.Categorize(internal.ErrProxy, fmt.Errorf("%s: %s", proxy.URL, response.Status()))
+		}
+
+		return cacheEntry{Versions: strings.Fields(response.String()), URL: listURL, Validators: httpclient.ValidatorsOf(response)}, true, nil
+	}
+
+	return cacheEntry{}, false, nil
+}
+
+// Successors returns versions of modules with higher major version suffixes than modulePath,
+// e.g. example.com/foo/v2 and example.com/foo/v3 for example.com/foo, keyed by module path.
+// Probing starts above the highest major of the given versions, which may be +incompatible,
+// and stops at the first major version which doesn't exist. Public modules are looked up
+// only on the proxies, as most of the probed paths don't exist and asking VCS for them is slow.
+func (r *versionResolver) Successors(modulePath string, versions []*semver.Version) map[string][]*semver.Version {
+	successors := make(map[string][]*semver.Version)
+
+	prefix, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return successors
+	}
+
+	separator := "/"
+	major := 1
+
+	if pathMajor != "" {
+		separator = pathMajor[:1]
+
+		n, err := strconv.Atoi(pathMajor[2:])
+		if err != nil {
+			return successors
+		}
+
+		major = n
+	}
+
+	for _, v := range versions {
+		if int(v.Major()) > major {
+			major = int(v.Major())
+		}
+	}
+
+	for n := major + 1; ; n++ {
+		path := fmt.Sprintf("%s%sv%d", prefix, separator, n)
+
+		versions, err := r.successorVersions(path)
+		if err != nil {
+			break
+		}
+
+		var compatible []*semver.Version
+
+		for _, v := range versions {
+			if modsemver.Major(v.Original()) == fmt.Sprintf("v%d", n) {
+				compatible = append(compatible, v)
+			}
+		}
+
+		if len(compatible) == 0 {
+			break
+		}
+
+		successors[path] = withoutRetracted(compatible, r.Retracted(path, compatible))
+	}
+
+	return successors
+}
+
+// successorVersions returns available versions of a probed successor module, see Successors.
+func (r *versionResolver) successorVersions(modulePath string) ([]*semver.Version, error) {
+	if module.MatchPrefixPatterns(r.privatePatterns, modulePath) {
+		return r.Versions(modulePath)
+	}
+
+	var stale cacheEntry
+
+	if r.cache != nil {
+		entry, fresh, _ := r.cache.lookup(modulePath)
+		if fresh {
+			return parseVersions(entry.Versions), nil
+		}
+
+		stale = entry
+	}
+
+	entry, found, err := r.proxyList(modulePath, stale)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	if r.cache != nil {
+		_ = r.cache.setEntry(modulePath, entry)
+	}
+
+	return parseVersions(entry.Versions), nil
+}
+
+// toolchainVersions fetches available versions with the go command, which also supports VCS directly.
+func (r *versionResolver) toolchainVersions(modulePath string) ([]string, error) {
+	cmd := exec.CommandContext(r.ctx, "go", "list", "-m", "-versions", "-json", modulePath+"@latest")
+	cmd.Env = goEnv()
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, r.toolchainError(err)
+	}
+
+	it := item{}
+
+	err = json.Unmarshal(out, &it)
+	if err != nil {
+		return nil, internal.Categorize(internal.ErrParse, err)
+	}
+
+	return it.Versions, nil
+}
+
+// toolchainError categorizes a failed go command by the error it printed, like the go command itself
+// it doesn't tell a missing module from a missing repository.
+func (r *versionResolver) toolchainError(err error) error {
+	var exitErr *exec.ExitError
+	if r.ctx.Err() != nil || !errors.As(err, &exitErr) {
+		return err
+	}
+
+	stderr := string(exitErr.Stderr)
+
+	switch {
+	case containsAny(stderr, "dial tcp", "no such host", "connection refused", "connection reset", "i/o timeout", "TLS handshake timeout"):
+		return internal.Categorize(internal.ErrNetwork, err)
+	case containsAny(stderr, "404 Not Found", "410 Gone", "not found", "unknown revision", "no matching versions"):
+		return internal.Categorize(internal.ErrNotFound, err)
+	}
+
+	return err
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Retracted returns the given versions which are retracted by go.mod of the latest version.
+// Retractions are advisory, a missing go.mod or a failing proxy means no version is retracted.
+func (r *versionResolver) Retracted(modulePath string, versions []*semver.Version) map[string]bool {
+	retracted := make(map[string]bool)
+
+	if r.retractions != nil {
+		if cached, ok := r.retractions.get(modulePath); ok {
+			for _, v := range cached {
+				retracted[v] = true
+			}
+
+			return retracted
+		}
+	}
+
+	if len(versions) == 0 {
+		return retracted
+	}
+
+	latest := versions[0]
+	for _, v := range versions {
+		if v.GreaterThan(latest) {
+			latest = v
+		}
+	}
+
+	intervals, err := r.retractIntervals(modulePath, latest.Original())
+	if err != nil {
+		logger.Debugf("retractions of %s not resolved: %v", modulePath, err)
+		return retracted
+	}
+
+	var list []string
+
+	for _, v := range versions {
+		for _, interval := range intervals {
+			if modsemver.Compare(interval.Low, v.Original()) <= 0 && modsemver.Compare(v.Original(), interval.High) <= 0 {
+				retracted[v.Original()] = true
+				list = append(list, v.Original())
+				break
+			}
+		}
+	}
+
+	if r.retractions != nil {
+		_ = r.retractions.set(modulePath, list)
+	}
+
+	return retracted
+}
+
+// retractIntervals returns retract directives of go.mod of the given module version.
+func (r *versionResolver) retractIntervals(modulePath, version string) ([]modfile.VersionInterval, error) {
+	content, err := r.goMod(modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := modfile.ParseLax("go.mod", content, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := make([]modfile.VersionInterval, 0, len(parse.Retract))
+	for _, retract := range parse.Retract {
+		intervals = append(intervals, retract.VersionInterval)
+	}
+
+	return intervals, nil
+}
+
+// deprecatedRE matches a paragraph starting with "Deprecated:" of a module comment, like the go command does.
+var deprecatedRE = regexp.MustCompile(`(?s)(?:^|\n\n)Deprecated: *(.*?)(?:$|\n\n)`)
+
+// Deprecated returns the deprecation message of go.mod of the latest of the given versions, empty if the module isn't deprecated.
+// The latest release is used, prereleases only if there is no release. Like retractions it is advisory.
+func (r *versionResolver) Deprecated(modulePath string, versions []*semver.Version) string {
+	if r.deprecations != nil {
+		if cached, ok := r.deprecations.get(modulePath); ok {
+			return strings.Join(cached, "")
+		}
+	}
+
+	latest := latestRelease(versions)
+	if latest == nil {
+		return ""
+	}
+
+	content, err := r.goMod(modulePath, latest.Original())
+	if err != nil {
+		logger.Debugf("deprecation of %s not resolved: %v", modulePath, err)
+		return ""
+	}
+
+	message := deprecation(content)
+
+	if r.deprecations != nil {
+		entry := []string{}
+		if message != "" {
+			entry = append(entry, message)
+		}
+
+		_ = r.deprecations.set(modulePath, entry)
+	}
+
+	return message
+}
+
+// latestRelease returns the highest release of the versions, the highest prerelease if there is no release.
+func latestRelease(versions []*semver.Version) *semver.Version {
+	var release, prerelease *semver.Version
+
+	for _, v := range versions {
+		if v.Prerelease() != "" {
+			if prerelease == nil || v.GreaterThan(prerelease) {
+				prerelease = v
+			}
+		} else if release == nil || v.GreaterThan(release) {
+			release = v
+		}
+	}
+
+	if release == nil {
+		return prerelease
+	}
+
+	return release
+}
+
+// deprecation returns the deprecation message of comments of the module directive of go.mod content.
+// Like goDirective it scans lines, comments right above the directive and at its end are its comments.
+func deprecation(content []byte) string {
+	var comments []string
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "//") {
+			comments = append(comments, strings.TrimSpace(strings.TrimPrefix(line, "//")))
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "module" {
+			// a blank line or another directive detaches the comments above.
+			comments = nil
+			continue
+		}
+
+		if i := strings.Index(line, "//"); i >= 0 {
+			comments = append(comments, strings.TrimSpace(line[i+2:]))
+		}
+
+		if m := deprecatedRE.FindStringSubmatch(strings.Join(comments, "\n")); m != nil {
+			return m[1]
+		}
+
+		return ""
+	}
+
+	return ""
+}
+
+// RequiredGo returns the go directive of go.mod of the module version, empty if there is none.
+// Like retractions it is advisory, a missing go.mod or a failing proxy means no Go version is required.
+func (r *versionResolver) RequiredGo(modulePath, version string) string {
+	var cached []string
+	if r.goVersions != nil {
+		cached, _ = r.goVersions.get(modulePath)
+
+		for _, entry := range cached {
+			fields := strings.Fields(entry)
+			if len(fields) == 2 && fields[0] == version {
+				return fields[1]
+			} else if len(fields) == 1 && fields[0] == version {
+				return ""
+			}
+		}
+	}
+
+	content, err := r.goMod(modulePath, version)
+	if err != nil {
+		logger.Debugf("go version of %s@%s not resolved: %v", modulePath, version, err)
+		return ""
+	}
+
+	goVersion := goDirective(content)
+
+	if r.goVersions != nil {
+		_ = r.goVersions.set(modulePath, append(cached, strings.TrimSpace(version+" "+goVersion)))
+	}
+
+	return goVersion
+}
+
+// goDirective returns the version of the go directive of go.mod content, empty if there is none.
+// go.mod is scanned line by line since modfile rejects go versions with a patch release, e.g. go 1.21.0.
+func goDirective(content []byte) string {
+	for _, line := range strings.Split(string(content), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "go" {
+			return fields[1]
+		}
+	}
+
+	return ""
+}
+
+// goMod fetches go.mod of the module version from the first proxy which knows it, private modules are downloaded by the go toolchain.
+func (r *versionResolver) goMod(modulePath, version string) ([]byte, error) {
+	if !module.MatchPrefixPatterns(r.privatePatterns, modulePath) {
+		content, found, err := r.proxyFile(modulePath, version, "mod")
+		if err != nil || found {
+			return content, err
+		}
+
+		if !GoProxyDirect() {
+			return nil, ErrNoVersionAvailable
+		}
+	}
+
+	cmd := exec.CommandContext(r.ctx, "go", "mod", "download", "-json", modulePath+"@"+version)
+	cmd.Env = goEnv()
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	it := item{}
+
+	err = json.Unmarshal(out, &it)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadFile(it.GoMod)
+}
+
+// proxyFile fetches the .mod or .info file of the module version from the first proxy which knows it, false if none of them does.
+func (r *versionResolver) proxyFile(modulePath, version, ext string) ([]byte, bool, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, false, err
+	}
+
+	proxies, err := GoProxies()
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, proxy := range proxies {
+		response, err := r.restClient.R().
+			SetContext(r.ctx).
+			Get(fmt.Sprintf("%s/%s/@v/%s.%s", proxy.URL, escaped, escapedVersion, ext))
+		if err != nil && proxy.FallbackOnError && r.ctx.Err() == nil {
+			continue
+		}
+		if err != nil {
+			return nil, false, r.requestError(err)
+		}
+
+		if notFound(response) || proxy.FallbackOnError && !response.IsSuccess() {
+			continue
+		}
+
+		if !response.IsSuccess() {
+			return nil, false, internal.Categorize(internal.ErrProxy, fmt.Errorf("%s: %s", proxy.URL, response.Status()))
+		}
+
+		return response.Body(), true, nil
+	}
+
+	return nil, false, nil
+}
+
+// Times returns publish times of the given versions of the module keyed by version.
+// Like retractions they are informational, versions which can't be resolved are left out.
+func (r *versionResolver) Times(modulePath string, versions ...*semver.Version) map[string]time.Time {
+	times := make(map[string]time.Time)
+
+	var cached []string
+	if r.times != nil {
+		cached, _ = r.times.get(modulePath)
+		times = parseTimes(cached)
+	}
+
+	var fetched []string
+
+	for _, v := range versions {
+		if v == nil {
+			continue
+		}
+
+		if _, ok := times[v.Original()]; ok {
+			continue
+		}
+
+		t, err := r.versionTime(modulePath, v.Original())
+		if err != nil {
+			logger.Debugf("publish time of %s@%s not resolved: %v", modulePath, v.Original(), err)
+			continue
+		}
+		if t.IsZero() {
+			continue
+		}
+
+		times[v.Original()] = t
+		fetched = append(fetched, v.Original()+" "+t.Format(time.RFC3339))
+	}
+
+	if r.times != nil && len(fetched) > 0 {
+		_ = r.times.set(modulePath, append(cached, fetched...))
+	}
+
+	return times
+}
+
+// versionTime fetches the .info of the module version, private modules are resolved by the go toolchain.
+func (r *versionResolver) versionTime(modulePath, version string) (time.Time, error) {
+	var it item
+
+	if !module.MatchPrefixPatterns(r.privatePatterns, modulePath) {
+		content, found, err := r.proxyFile(modulePath, version, "info")
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if found {
+			err = json.Unmarshal(content, &it)
+			return it.Time, err
+		}
+
+		if !GoProxyDirect() {
+			return time.Time{}, ErrNoVersionAvailable
+		}
+	}
+
+	cmd := exec.CommandContext(r.ctx, "go", "list", "-m", "-json", modulePath+"@"+version)
+	cmd.Env = goEnv()
+
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = json.Unmarshal(out, &it)
+
+	return it.Time, err
+}
+
+// parseTimes parses cached "version time" entries of Times.
+func parseTimes(entries []string) map[string]time.Time {
+	times := make(map[string]time.Time, len(entries))
+
+	for _, entry := range entries {
+		fields := strings.Fields(entry)
+		if len(fields) != 2 {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			continue
+		}
+
+		times[fields[0]] = t
+	}
+
+	return times
+}
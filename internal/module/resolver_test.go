@@ -0,0 +1,430 @@
+package module
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveLatest_Retracted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.0.0\nv1.1.0\nv1.2.0\nv1.3.0\n"))
+		case "/example.com/a/@v/v1.3.0.mod":
+			w.Write([]byte("module example.com/a\n\nretract (\n\tv1.3.0 // published by mistake\n\t[v1.0.0, v1.1.0]\n)\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	resolver := newVersionResolver(context.Background(), "")
+	result := PackageResult{Path: "example.com/a", LocalVersion: semver.MustParse("v1.1.0")}
+
+	checkResult := internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "v1.2.0", checkResult.LatestVersion.Original())
+	assert.True(t, checkResult.Retracted)
+
+	result.LocalVersion = semver.MustParse("v1.2.0")
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.False(t, checkResult.Retracted)
+}
+
+func TestResolveLatest_Deprecated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.0.0\nv1.1.0\nv1.2.0-rc.1\n"))
+		case "/example.com/a/@v/v1.1.0.mod":
+			w.Write([]byte("// Deprecated: use example.com/b instead.\nmodule example.com/a\n\ngo 1.21.0\n"))
+		case "/example.com/c/@v/list":
+			w.Write([]byte("v1.0.0\n"))
+		case "/example.com/c/@v/v1.0.0.mod":
+			w.Write([]byte("module example.com/c\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	resolver := newVersionResolver(context.Background(), "")
+
+	// go.mod of the latest release is read, not the one of the prerelease.
+	checkResult := internal.CheckResult{}
+	resolveLatest(resolver, PackageResult{Path: "example.com/a", LocalVersion: semver.MustParse("v1.1.0")}, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "use example.com/b instead.", checkResult.Deprecated)
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, PackageResult{Path: "example.com/c", LocalVersion: semver.MustParse("v1.0.0")}, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Empty(t, checkResult.Deprecated)
+}
+
+func TestResolveLatest_Pseudo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte(""))
+		case "/example.com/b/@v/list":
+			w.Write([]byte("v0.1.0\nv0.2.0\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	resolver := newVersionResolver(context.Background(), "")
+	pseudo := semver.MustParse("v0.0.0-20230101000000-abcdef123456")
+
+	checkResult := internal.CheckResult{}
+	resolveLatest(resolver, PackageResult{Path: "example.com/a", LocalVersion: pseudo}, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.True(t, checkResult.Pseudo)
+	assert.True(t, checkResult.Untagged)
+	assert.Equal(t, pseudo, checkResult.LatestVersion)
+	assert.False(t, checkResult.Updatable())
+	assert.False(t, checkResult.TagAvailable())
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, PackageResult{Path: "example.com/b", LocalVersion: pseudo}, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.True(t, checkResult.Pseudo)
+	assert.False(t, checkResult.Untagged)
+	assert.Equal(t, "v0.2.0", checkResult.LatestVersion.Original())
+	assert.True(t, checkResult.TagAvailable())
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, PackageResult{Path: "example.com/b", LocalVersion: semver.MustParse("v0.1.0")}, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.False(t, checkResult.Pseudo)
+	assert.False(t, checkResult.TagAvailable())
+}
+
+func TestDeprecation(t *testing.T) {
+	tests := map[string]string{
+		"module example.com/a\n":                                           "",
+		"// Deprecated: use b\nmodule example.com/a\n":                     "use b",
+		"module example.com/a // Deprecated: use c\n":                      "use c",
+		"// Package a does things.\n//\n// Deprecated: use b\nmodule a\n":  "use b",
+		"// Deprecated: use b\n\nmodule example.com/a\n":                   "",
+		"// Not Deprecated: use b\nmodule example.com/a\n":                 "",
+		"module example.com/a\n\n// Deprecated: use b\nrequire b v1.0.0\n": "",
+	}
+
+	for content, expected := range tests {
+		assert.Equal(t, expected, deprecation([]byte(content)), content)
+	}
+}
+
+func TestResolveLatest_Successors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.4.0\nv1.5.0\nv2.0.0+incompatible\n"))
+		case "/example.com/a/v3/@v/list":
+			w.Write([]byte("v3.1.0\nv3.3.0\n"))
+		case "/example.com/a/v4/@v/list":
+			w.Write([]byte("v4.0.0\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	resolver := newVersionResolver(context.Background(), "")
+	resolver.successors = true
+
+	result := PackageResult{Path: "example.com/a", LocalVersion: semver.MustParse("v1.4.0")}
+
+	checkResult := internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestVersion, getWantedVersion, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "v4.0.0", checkResult.LatestVersion.Original())
+	assert.Equal(t, "example.com/a/v4", checkResult.LatestPath)
+	assert.Equal(t, "v1.5.0", checkResult.WantedVersion.Original(), "wanted is resolved from the same versions")
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestMinorVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "v1.5.0", checkResult.LatestVersion.Original())
+	assert.Empty(t, checkResult.LatestPath)
+
+	result = PackageResult{Path: "example.com/a/v3", LocalVersion: semver.MustParse("v3.1.0")}
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestMinorVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "v3.3.0", checkResult.LatestVersion.Original())
+	assert.Empty(t, checkResult.LatestPath)
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestMajorVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "v4.0.0", checkResult.LatestVersion.Original())
+	assert.Equal(t, "example.com/a/v4", checkResult.LatestPath)
+
+	resolver.successors = false
+	result = PackageResult{Path: "example.com/a", LocalVersion: semver.MustParse("v1.4.0")}
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "v2.0.0+incompatible", checkResult.LatestVersion.Original())
+	assert.Empty(t, checkResult.LatestPath)
+}
+
+func TestResolveLatest_GoVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.1.0\nv1.2.0\nv1.3.0\nv1.4.0\n"))
+		case "/example.com/a/@v/v1.4.0.mod":
+			w.Write([]byte("module example.com/a\n\ngo 1.23.0\n\ntoolchain go1.23.4\n"))
+		case "/example.com/a/@v/v1.3.0.mod":
+			w.Write([]byte("module example.com/a\n\ngo 1.22rc1\n"))
+		case "/example.com/a/@v/v1.2.0.mod":
+			w.Write([]byte("module example.com/a\n\ngo 1.21 // language version\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	resolver := newVersionResolver(context.Background(), "")
+	resolver.goVersion = "1.21.5"
+
+	result := PackageResult{Path: "example.com/a", LocalVersion: semver.MustParse("v1.1.0")}
+
+	checkResult := internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "v1.2.0", checkResult.LatestVersion.Original())
+	assert.Equal(t, "v1.4.0", checkResult.SkippedVersion.Original())
+	assert.Equal(t, "1.23.0", checkResult.SkippedGo)
+
+	resolver.goVersion = "1.20"
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "v1.1.0", checkResult.LatestVersion.Original())
+	assert.False(t, checkResult.Updatable())
+
+	resolver.goVersion = "1.23.4"
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, result, "", getLatestVersion, nil, &checkResult)
+	assert.NoError(t, checkResult.Error)
+	assert.Equal(t, "v1.4.0", checkResult.LatestVersion.Original())
+	assert.Nil(t, checkResult.SkippedVersion)
+}
+
+func TestCompareGoVersions(t *testing.T) {
+	assert.Equal(t, 0, compareGoVersions("1.21", "1.21.0"))
+	assert.Equal(t, 0, compareGoVersions("", "1.21"))
+	assert.Equal(t, 1, compareGoVersions("1.22rc1", "1.21.9"))
+	assert.Equal(t, -1, compareGoVersions("1.22rc1", "1.22.0"))
+	assert.Equal(t, -1, compareGoVersions("1.22beta1", "1.22rc1"))
+	assert.Equal(t, -1, compareGoVersions("1.9", "go1.10"))
+	assert.Equal(t, 1, compareGoVersions("1.21.10", "1.21.9"))
+}
+
+func TestChecker_Versions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.0.0\nv1.10.0\nv1.2.0\nv2.0.0-rc.1\nv1.3.0\n"))
+		case "/example.com/a/@v/v2.0.0-rc.1.mod":
+			w.Write([]byte("module example.com/a\n\nretract v1.3.0\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	checker := Checker{Ctx: context.Background()}
+
+	versions, err := checker.Versions("example.com/a")
+	assert.NoError(t, err)
+
+	var list []string
+	for _, v := range versions {
+		list = append(list, v.Original())
+	}
+
+	assert.Equal(t, []string{"v2.0.0-rc.1", "v1.10.0", "v1.2.0", "v1.0.0"}, list)
+}
+
+func TestVersionResolver_Times(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/v1.0.0.info":
+			w.Write([]byte(`{"Version":"v1.0.0","Time":"2020-01-02T03:04:05Z"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	resolver := newVersionResolver(context.Background(), "")
+
+	times := resolver.Times("example.com/a", semver.MustParse("v1.0.0"), semver.MustParse("v1.1.0"), nil)
+	assert.Len(t, times, 1)
+	assert.Equal(t, "2020-01-02T03:04:05Z", times["v1.0.0"].Format(time.RFC3339))
+}
+
+func TestResolveLatest_ErrorCategories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/broken/@v/list":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	viper.Set("retries", 0)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+	defer viper.Set("retries", nil)
+
+	resolver := newVersionResolver(context.Background(), "")
+
+	checkResult := internal.CheckResult{}
+	resolveLatest(resolver, PackageResult{Path: "example.com/missing"}, "", getLatestVersion, nil, &checkResult)
+	assert.ErrorIs(t, checkResult.Error, internal.ErrNotFound)
+	assert.ErrorIs(t, checkResult.Error, ErrNoVersionAvailable, "the error doesn't change")
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, PackageResult{Path: "example.com/broken"}, "", getLatestVersion, nil, &checkResult)
+	assert.ErrorIs(t, checkResult.Error, internal.ErrProxy)
+	assert.Equal(t, "proxy", internal.ErrorCategory(checkResult.Error))
+
+	server.Close()
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(resolver, PackageResult{Path: "example.com/a"}, "", getLatestVersion, nil, &checkResult)
+	assert.ErrorIs(t, checkResult.Error, internal.ErrNetwork)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	checkResult = internal.CheckResult{}
+	resolveLatest(newVersionResolver(ctx, ""), PackageResult{Path: "example.com/a"}, "", getLatestVersion, nil, &checkResult)
+	assert.ErrorIs(t, checkResult.Error, context.Canceled)
+	assert.Empty(t, internal.ErrorCategory(checkResult.Error), "a canceled check isn't a network error")
+}
+
+func TestVersionResolver_RetriedVersions(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+
+	count := func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests[path]
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		n := requests[r.URL.Path]
+		mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/example.com/a/@v/list" && n == 1:
+			// the connection is dropped without a response, a network error.
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		case r.URL.Path == "/example.com/a/@v/list":
+			w.Write([]byte("v1.0.0\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	viper.Set("retries", 0)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+	defer viper.Set("retries", nil)
+
+	backoff := retryBackoff
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = backoff }()
+
+	resolver := newVersionResolver(context.Background(), "")
+
+	_, err := resolver.RetriedVersions("example.com/a")
+	assert.ErrorIs(t, err, internal.ErrNetwork, "modules aren't retried by default")
+
+	mu.Lock()
+	requests = map[string]int{}
+	mu.Unlock()
+	resolver.retries = 2
+
+	versions, err := resolver.RetriedVersions("example.com/a")
+	assert.NoError(t, err)
+	assert.Len(t, versions, 1)
+	assert.Equal(t, 2, count("/example.com/a/@v/list"))
+
+	_, err = resolver.RetriedVersions("example.com/missing")
+	assert.ErrorIs(t, err, internal.ErrNotFound)
+	assert.Equal(t, 1, count("/example.com/missing/@v/list"), "not found isn't retried")
+}
@@ -2,11 +2,15 @@ package module
 
 import (
 	"context"
-	"encoding/json"
-	"os/exec"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"github.com/spf13/viper"
+	"golang.org/x/mod/module"
 )
 
 // Scanner is exported.
@@ -14,58 +18,232 @@ type Scanner struct {
 	Ctx context.Context
 }
 
-// Scan is exported.
-func (c *Scanner) Scan(path string) (map[string]internal.VulnerabilityResult, error) {
-	return getModAndVulnerabilitiesCheck(c.Ctx, path)
+// moduleVersion is a module version to scan.
+type moduleVersion struct {
+	path    string
+	version string
 }
 
-func getModAndVulnerabilitiesCheck(ctx context.Context, path string) (map[string]internal.VulnerabilityResult, error) {
+// Scan checks all dependencies of the module in the given path against the OSV database.
+// Advisories rated lower than options.MinSeverity are left out.
+func (c *Scanner) Scan(path string, options internal.ScanOptions) (map[string]internal.VulnerabilityResult, error) {
+	return getModAndVulnerabilitiesCheck(c.Ctx, path, options)
+}
+
+// severities returns the highest rating of advisories affecting the current version of each given module,
+// modules without known vulnerabilities are left out. Replaced modules are pinned, so they aren't looked up.
+func severities(ctx context.Context, results map[string]internal.CheckResult) (map[string]internal.Severity, error) {
+	packages := make([]PackageResult, 0, len(results))
+	for name, result := range results {
+		if result.LocalVersion != nil && !result.Replaced {
+			packages = append(packages, PackageResult{Path: name, LocalVersion: result.LocalVersion})
+		}
+	}
+
+	scanned, err := vulnerabilityScan(ctx, newOSVClient(ctx, ""), packages, internal.ScanOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	severities := make(map[string]internal.Severity)
+
+	for name, result := range scanned {
+		if result.Error != nil {
+			return nil, fmt.Errorf("%s: %w", name, result.Error)
+		}
+
+		for i, v := range result.Vulnerabilities {
+			if i == 0 || v.Rating.Rank() > severities[name].Rank() {
+				severities[name] = v.Rating
+			}
+		}
+	}
+
+	return severities, nil
+}
+
+func getModAndVulnerabilitiesCheck(ctx context.Context, path string, options internal.ScanOptions) (map[string]internal.VulnerabilityResult, error) {
+	if options.Stream != nil && options.Upgrades {
+		return nil, errors.New("upgrades can't be resolved while results are streamed")
+	}
+
 	parser := ModParser{ctx: ctx}
-	vs := make(map[string]internal.VulnerabilityResult)
-	results, err := parser.Parse(path)
+
+	results, err := parser.ParseAll(path)
 	if err != nil {
 		return nil, err
 	}
 
-	vs = vulnerabilityScan(ctx, results)
-	return vs, nil
+	var changed map[string]internal.DiffResult
+	if options.Changed {
+		changed, err = changedModules(ctx, path, "HEAD")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var production, testOnly map[string]bool
+	if options.ProdOnly {
+		production, testOnly, err = parser.BuildModules(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	trusted := trustedPatterns(options.Trust)
+
+	var packages, trustedPackages []PackageResult
+	skipped, skippedTests := 0, 0
+
+	for _, result := range results {
+		switch {
+		case result.Main || (changed != nil && !inDiff(changed, result.Path)):
+		case production != nil && !production[result.Path]:
+			skipped++
+			if testOnly[result.Path] {
+				skippedTests++
+			}
+		case module.MatchPrefixPatterns(trusted, result.Path):
+			trustedPackages = append(trustedPackages, result)
+		default:
+			packages = append(packages, result)
+		}
+	}
+
+	if options.ProdOnly {
+		logger.Infof("%d modules aren't imported by non-test packages and aren't scanned, %d of them are imported by tests", skipped, skippedTests)
+	}
+
+	var source advisorySource = newOSVClient(ctx, options.DBURL)
+	if options.DB != "" {
+		source, err = loadOSVDatabase(options.DB)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scanned, err := vulnerabilityScan(ctx, source, packages, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range trustedPackages {
+		result := internal.VulnerabilityResult{LocalVersion: p.LocalVersion, Position: p.Position, Trusted: true}
+		if options.Stream != nil {
+			options.Stream(p.Path, result)
+			continue
+		}
+
+		scanned[p.Path] = result
+	}
+
+	if !options.Upgrades {
+		return scanned, nil
+	}
+
+	graph, err := parser.Graph(path)
+	if err != nil {
+		return nil, err
+	}
+
+	privatePatterns, err := getPrivatePatterns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	directUpgrades(newVersionResolver(ctx, privatePatterns), graph, results, scanned)
+
+	return scanned, nil
+}
+
+// trustedPatterns joins the trusted_prefixes config and the given prefixes into a GOPRIVATE like pattern list.
+func trustedPatterns(trust []string) string {
+	return strings.Join(append(viper.GetStringSlice("trusted_prefixes"), trust...), ",")
 }
 
-// vulnerabilityScan function check for possible vulnerabilities using the gosec tool
-func vulnerabilityScan(ctx context.Context, packages []PackageResult) map[string]internal.VulnerabilityResult {
+// vulnerabilityScan queries vulnerabilities of the packages in a batch and fetches their details concurrently.
+// Results are passed to options.Stream instead of being returned if it is set.
+func vulnerabilityScan(ctx context.Context, client advisorySource, packages []PackageResult, options internal.ScanOptions) (map[string]internal.VulnerabilityResult, error) {
+	versions := make([]moduleVersion, 0, len(packages))
+	for _, p := range packages {
+		versions = append(versions, moduleVersion{path: p.ResolvePath(), version: p.LocalVersion.Original()})
+	}
+
+	ids, err := client.queryBatch(versions)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := options.Progress
+	if progress == nil {
+		progress = func(int, int) {}
+	}
+
+	result := make(map[string]internal.VulnerabilityResult)
 
 	var (
-		wg     sync.WaitGroup
-		mu     sync.Mutex
-		result map[string]internal.VulnerabilityResult
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
 	)
 
-	doneCh := make(chan bool, 1)
-	wg.Add(len(packages))
-	result = make(map[string]internal.VulnerabilityResult, 0)
-	for i := 0; i < len(packages); i++ {
-		go func(i int) {
+	jobs := make(chan int)
+
+	for i := 0; i < getConcurrency(); i++ {
+		wg.Add(1)
+
+		go func() {
 			defer wg.Done()
-			goSecDir := packages[i].Dir + "/./..."
-			arg := []string{"-quiet", "-fmt=json", goSecDir}
-			cmd := exec.CommandContext(ctx, "gosec", arg...)
-			out, _ := cmd.CombinedOutput()
-			output := string(out)
-			var vr internal.VulnerabilityResult
-			err := json.Unmarshal([]byte(output), &vr)
-			if err == nil {
+
+			for i := range jobs {
+				p := packages[i]
+				vr := internal.VulnerabilityResult{LocalVersion: p.LocalVersion, Position: p.Position}
+
+				for _, id := range ids[i] {
+					vuln, err := client.vulnerability(id)
+					if err != nil {
+						vr.Error = err
+						break
+					}
+
+					vulnerability := vuln.toVulnerability(p.ResolvePath(), p.LocalVersion)
+					rate(client, &vulnerability)
+
+					if vulnerability.Rating.AtLeast(options.MinSeverity) {
+						vr.Vulnerabilities = append(vr.Vulnerabilities, vulnerability)
+					}
+				}
+
 				mu.Lock()
-				result[packages[i].Path] = vr
+				done++
+				if options.Stream != nil {
+					options.Stream(p.Path, vr)
+				} else {
+					result[p.Path] = vr
+				}
+				progress(done, len(packages))
 				mu.Unlock()
 			}
-		}(i)
+		}()
 	}
-	go func() {
-		wg.Wait()
-		close(doneCh)
-	}()
-	select {
-	case <-doneCh:
-		return result
+
+	progress(0, len(packages))
+
+loop:
+	for i := range packages {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break loop
+		}
 	}
+
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
@@ -0,0 +1,23 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/module"
+)
+
+func TestTrustedPatterns(t *testing.T) {
+	viper.Set("trusted_prefixes", []string{"github.com/mycompany"})
+	defer viper.Set("trusted_prefixes", nil)
+
+	trusted := trustedPatterns([]string{"*.corp.example.com"})
+	assert.Equal(t, "github.com/mycompany,*.corp.example.com", trusted)
+
+	assert.True(t, module.MatchPrefixPatterns(trusted, "github.com/mycompany"))
+	assert.True(t, module.MatchPrefixPatterns(trusted, "github.com/mycompany/lib/v2"))
+	assert.True(t, module.MatchPrefixPatterns(trusted, "git.corp.example.com/team/lib"))
+	assert.False(t, module.MatchPrefixPatterns(trusted, "github.com/mycompanyfork/lib"))
+	assert.False(t, module.MatchPrefixPatterns(trusted, "github.com/other/lib"))
+}
@@ -0,0 +1,82 @@
+package module
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// resolveSecure sets advisories affecting the local versions of the packages and the lowest versions fixing all of them.
+// Replaced modules are pinned and those which failed can't be updated, so they aren't scanned.
+func resolveSecure(resolver *versionResolver, packages []PackageResult, checkResults map[string]internal.CheckResult) error {
+	var scan []PackageResult
+	for _, p := range packages {
+		if result, ok := checkResults[p.Path]; ok && result.Error == nil && !p.Replaced && p.LocalVersion != nil {
+			scan = append(scan, p)
+		}
+	}
+
+	scanned, err := vulnerabilityScan(resolver.ctx, newOSVClient(resolver.ctx, ""), scan, internal.ScanOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range scan {
+		vr := scanned[p.Path]
+		if vr.Error != nil {
+			return fmt.Errorf("%s: %w", p.Path, vr.Error)
+		}
+
+		if len(vr.Vulnerabilities) == 0 {
+			continue
+		}
+
+		checkResult := checkResults[p.Path]
+
+		for _, v := range vr.Vulnerabilities {
+			checkResult.Advisories = append(checkResult.Advisories, v.ID)
+		}
+
+		sort.Strings(checkResult.Advisories)
+
+		if fixed := fixedVersion(vr.Vulnerabilities); fixed != "" {
+			checkResult.SecureVersion = secureVersion(resolver, p.Path, fixed)
+		}
+
+		checkResults[p.Path] = checkResult
+	}
+
+	return nil
+}
+
+// secureVersion returns the lowest available version which isn't older than the fixed version, nil if there is none.
+// Retracted versions are left out, prereleases too unless the fix is a prerelease.
+func secureVersion(resolver *versionResolver, modulePath, fixed string) *semver.Version {
+	fixedVersion, err := semver.NewVersion(fixed)
+	if err != nil {
+		return nil
+	}
+
+	versions, err := resolver.Versions(modulePath)
+	if err != nil {
+		return nil
+	}
+
+	versions = withoutRetracted(versions, resolver.Retracted(modulePath, versions))
+
+	var secure *semver.Version
+
+	for _, v := range versions {
+		if v.LessThan(fixedVersion) || (v.Prerelease() != "" && fixedVersion.Prerelease() == "") {
+			continue
+		}
+
+		if secure == nil || v.LessThan(secure) {
+			secure = v
+		}
+	}
+
+	return secure
+}
@@ -0,0 +1,86 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSecure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v1/") {
+			w.Header().Set("Content-Type", "application/json")
+		}
+
+		switch r.URL.Path {
+		case "/v1/querybatch":
+			query := osvBatchQuery{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+
+			results := make([]string, len(query.Queries))
+			for i, q := range query.Queries {
+				results[i] = "{}"
+				switch q.Package.Name {
+				case "example.com/a":
+					results[i] = `{"vulns":[{"id":"GO-2021-0003"},{"id":"GO-2021-0001"}]}`
+				case "example.com/b":
+					results[i] = `{"vulns":[{"id":"GO-2021-0002"}]}`
+				}
+			}
+
+			w.Write([]byte(`{"results":[` + strings.Join(results, ",") + `]}`))
+		case "/v1/vulns/GO-2021-0001":
+			w.Write([]byte(`{"id": "GO-2021-0001", "affected": [{"package": {"ecosystem": "Go", "name": "example.com/a"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.1.0"}]}]}]}`))
+		case "/v1/vulns/GO-2021-0003":
+			w.Write([]byte(`{"id": "GO-2021-0003", "affected": [{"package": {"ecosystem": "Go", "name": "example.com/a"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.2.0"}]}]}]}`))
+		case "/v1/vulns/GO-2021-0002":
+			w.Write([]byte(`{"id": "GO-2021-0002", "affected": [{"package": {"ecosystem": "Go", "name": "example.com/b"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]}]}`))
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.0.0\nv1.1.0\nv1.2.1-rc.1\nv1.2.1\nv1.3.0\nv1.4.0\n"))
+		case "/example.com/a/@v/v1.4.0.mod":
+			w.Write([]byte("module example.com/a\n\nretract v1.2.1\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("osv_url", server.URL)
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("osv_url", nil)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	packages := []PackageResult{
+		{Path: "example.com/a", LocalVersion: semver.MustParse("v1.0.0")},
+		{Path: "example.com/b", LocalVersion: semver.MustParse("v0.1.0")},
+		{Path: "example.com/c", LocalVersion: semver.MustParse("v0.1.0")},
+	}
+
+	checkResults := map[string]internal.CheckResult{
+		"example.com/a": {LocalVersion: semver.MustParse("v1.0.0")},
+		"example.com/b": {LocalVersion: semver.MustParse("v0.1.0")},
+		"example.com/c": {LocalVersion: semver.MustParse("v0.1.0")},
+	}
+
+	err := resolveSecure(newVersionResolver(context.Background(), ""), packages, checkResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"GO-2021-0001", "GO-2021-0003"}, checkResults["example.com/a"].Advisories)
+	assert.Equal(t, "v1.3.0", checkResults["example.com/a"].SecureVersion.Original(), "prereleases and retracted versions are skipped")
+
+	assert.Equal(t, []string{"GO-2021-0002"}, checkResults["example.com/b"].Advisories)
+	assert.Nil(t, checkResults["example.com/b"].SecureVersion, "there is no fix")
+
+	assert.Empty(t, checkResults["example.com/c"].Advisories)
+	assert.Nil(t, checkResults["example.com/c"].SecureVersion)
+}
@@ -0,0 +1,83 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/term"
+)
+
+// ErrStdinReadOnly is returned when go.mod read from stdin would be changed.
+var ErrStdinReadOnly = errors.New("go.mod read from stdin can't be updated, use --dry-run")
+
+// stdinReader is the source of go.mod for internal.StdinPath, tests replace it.
+var stdinReader io.Reader = os.Stdin
+
+// stdin keeps the temporary module directory of go.mod read from stdin, it is read only once.
+var stdin struct {
+	once sync.Once
+	dir  string
+	err  error
+}
+
+// stdinDir reads go.mod from stdin into a temporary directory and returns it, so go commands can run in it.
+// Replacements with relative local paths can't be resolved from there.
+func stdinDir() (string, error) {
+	stdin.once.Do(func() {
+		stdin.dir, stdin.err = readStdin()
+	})
+
+	return stdin.dir, stdin.err
+}
+
+func readStdin() (string, error) {
+	if f, ok := stdinReader.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return "", errors.New("go.mod is read from stdin, but stdin is a terminal")
+	}
+
+	content, err := ioutil.ReadAll(stdinReader)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = modfile.Parse("stdin", content, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid go.mod from stdin: %w", err)
+	}
+
+	dir, err := ioutil.TempDir("", "gomodctl-stdin")
+	if err != nil {
+		return "", err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, goMod), content, 0666)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// RemoveStdinDir removes the temporary directory of go.mod read from stdin, if there is one.
+func RemoveStdinDir() {
+	if stdin.dir != "" {
+		os.RemoveAll(stdin.dir)
+	}
+}
+
+// checkWritable returns ErrStdinReadOnly if the path is internal.StdinPath.
+func checkWritable(path string) error {
+	if path == internal.StdinPath {
+		return ErrStdinReadOnly
+	}
+
+	return nil
+}
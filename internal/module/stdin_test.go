@@ -0,0 +1,48 @@
+package module
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdinDir(t *testing.T) {
+	content := "module example.com/stdin\n\ngo 1.15\n\nrequire github.com/x/y v1.0.0\n"
+
+	stdinReader = strings.NewReader(content)
+	defer func() { stdinReader = os.Stdin }()
+
+	dir, err := absDir(internal.StdinPath)
+	require.NoError(t, err)
+
+	got, err := ioutil.ReadFile(filepath.Join(dir, goMod))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(got))
+
+	// stdin is read only once.
+	again, err := moduleDir(internal.StdinPath)
+	require.NoError(t, err)
+	assert.Equal(t, dir, again)
+
+	parser := ModParser{}
+	results, err := parser.ParseFile(internal.StdinPath)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "github.com/x/y", results[0].Path)
+
+	assert.Equal(t, ErrStdinReadOnly, checkWritable(internal.StdinPath))
+	assert.NoError(t, checkWritable(dir))
+
+	updater := Updater{}
+	assert.Equal(t, ErrStdinReadOnly, updater.Restore(internal.StdinPath))
+
+	RemoveStdinDir()
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
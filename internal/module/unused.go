@@ -0,0 +1,149 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"golang.org/x/mod/modfile"
+)
+
+// Unused returns modules required by go.mod in the given path which aren't needed by the build, sorted by path.
+// go mod tidy is run against a copy of go.mod and go.sum, so the module isn't changed.
+func (c *Checker) Unused(path string) ([]internal.UnusedModule, error) {
+	return getUnusedModules(c.Ctx, path)
+}
+
+// Unused returns modules required by go.mod in the given path which aren't needed by the build, see Checker.Unused.
+func (u *Updater) Unused(path string) ([]internal.UnusedModule, error) {
+	return getUnusedModules(u.Ctx, path)
+}
+
+func getUnusedModules(ctx context.Context, path string) ([]internal.UnusedModule, error) {
+	dir, err := moduleDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := parseGoMod(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tidy, err := tidyGoMod(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	required := make(map[string]bool)
+	for _, r := range tidy.Require {
+		required[r.Mod.Path] = true
+	}
+
+	var unused []internal.UnusedModule
+
+	for _, r := range current.Require {
+		if !required[r.Mod.Path] {
+			unused = append(unused, internal.UnusedModule{
+				Path:     r.Mod.Path,
+				Version:  r.Mod.Version,
+				Indirect: r.Indirect,
+			})
+		}
+	}
+
+	sort.Slice(unused, func(i, j int) bool {
+		return unused[i].Path < unused[j].Path
+	})
+
+	return unused, nil
+}
+
+// IndirectFixes returns requirements of go.mod in the given path whose // indirect comment differs from the one
+// go mod tidy would write, sorted by path. Unused modules and modules tidy would add aren't reported.
+func (u *Updater) IndirectFixes(path string) ([]internal.IndirectFix, error) {
+	dir, err := moduleDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := parseGoMod(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tidy, err := tidyGoMod(u.Ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	indirect := make(map[string]bool)
+	for _, r := range tidy.Require {
+		indirect[r.Mod.Path] = r.Indirect
+	}
+
+	var fixes []internal.IndirectFix
+
+	for _, r := range current.Require {
+		if marker, ok := indirect[r.Mod.Path]; ok && marker != r.Indirect {
+			fixes = append(fixes, internal.IndirectFix{
+				Path:     r.Mod.Path,
+				Version:  r.Mod.Version,
+				Indirect: marker,
+			})
+		}
+	}
+
+	sort.Slice(fixes, func(i, j int) bool {
+		return fixes[i].Path < fixes[j].Path
+	})
+
+	return fixes, nil
+}
+
+// tidyGoMod returns go.mod of the module in the given directory as go mod tidy would write it.
+func tidyGoMod(ctx context.Context, dir string) (*modfile.File, error) {
+	tmp, err := ioutil.TempDir("", "gomodctl-tidy")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	// -modfile reads and writes go.sum next to the given file with .sum extension.
+	tmpMod := filepath.Join(tmp, "go.mod")
+
+	for _, name := range []string{goMod, goSum} {
+		content, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) && name == goSum {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = ioutil.WriteFile(filepath.Join(tmp, name), content, 0666)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "mod", "tidy", "-modfile="+tmpMod)
+	cmd.Dir = dir
+	cmd.Env = goEnv()
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if len(out) > 0 {
+			return nil, fmt.Errorf("go mod tidy with output [%s] %w", out, err)
+		}
+
+		return nil, err
+	}
+
+	return parseGoMod(tmp)
+}
@@ -0,0 +1,87 @@
+package module
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnused(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gomodctl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	goModContent := []byte(`module example.com/test
+
+go 1.15
+
+require github.com/pkg/errors v0.9.1 // indirect
+`)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.mod"), goModContent, 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+
+	checker := Checker{Ctx: context.Background()}
+
+	unused, err := checker.Unused(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []internal.UnusedModule{{Path: "github.com/pkg/errors", Version: "v0.9.1", Indirect: true}}, unused)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	assert.NoError(t, err)
+	assert.Equal(t, goModContent, content)
+}
+
+func TestIndirectFixes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gomodctl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	goModContent := []byte(`module example.com/test
+
+go 1.17
+
+require (
+	example.com/a v0.0.0 // indirect
+	example.com/b v0.0.0
+)
+
+replace (
+	example.com/a => ./a
+	example.com/b => ./b
+)
+`)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.mod"), goModContent, 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nimport _ \"example.com/a\"\n\nfunc main() {}\n"), 0644))
+
+	// example.com/a imports example.com/b, since go 1.17 go.mod lists b as an indirect requirement.
+	for name, source := range map[string]string{"a": "package a\n\nimport _ \"example.com/b\"\n", "b": "package b\n"} {
+		goMod := "module example.com/" + name + "\n\ngo 1.15\n"
+		if name == "a" {
+			goMod += "\nrequire example.com/b v0.0.0\n\nreplace example.com/b => ../b\n"
+		}
+
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, name), 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name, "go.mod"), []byte(goMod), 0644))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name, name+".go"), []byte(source), 0644))
+	}
+
+	updater := Updater{Ctx: context.Background()}
+
+	fixes, err := updater.IndirectFixes(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []internal.IndirectFix{
+		{Path: "example.com/a", Version: "v0.0.0", Indirect: false},
+		{Path: "example.com/b", Version: "v0.0.0", Indirect: true},
+	}, fixes)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	assert.NoError(t, err)
+	assert.Equal(t, goModContent, content)
+}
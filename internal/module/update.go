@@ -2,6 +2,7 @@ package module
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
 
@@ -20,69 +21,196 @@ const (
 )
 
 // Update is exported
-func (u *Updater) Update(path string) (map[string]internal.CheckResult, error) {
-	absolutePath, err := filepath.Abs(path)
+func (u *Updater) Update(path string, options internal.UpdateOptions) (map[string]internal.CheckResult, error) {
+	if !options.DryRun {
+		if err := checkWritable(path); err != nil {
+			return nil, err
+		}
+	}
+
+	latestMinors, err := u.Plan(path, options)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.Apply(path, latestMinors, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return latestMinors, nil
+}
+
+// Plan resolves update candidates without changing go.mod.
+func (u *Updater) Plan(path string, options internal.UpdateOptions) (map[string]internal.CheckResult, error) {
+	absolutePath, err := moduleDir(path)
 	if err != nil {
 		return nil, err
 	}
 
+	filter := getFilter(options.CheckOptions)
+
+	// update can't change import paths, so successors with another major version suffix aren't candidates.
+	return getModAndFilter(u.Ctx, absolutePath, options.CheckOptions, filter, false)
+}
+
+// Severities returns the highest rating of advisories affecting the current version of each given module, see Checker.Severities.
+func (u *Updater) Severities(updates map[string]internal.CheckResult) (map[string]internal.Severity, error) {
+	return severities(u.Ctx, updates)
+}
+
+// Apply writes the given updates into go.mod, drops modules of options.Remove and creates go.mod.backup, nothing is written in dry run.
+// With options.Verify the module is tidied and built afterwards, go.mod and go.sum are restored and *VerifyError returned if that fails.
+// Results with an error, replaced modules and results without a higher version are skipped, e.g. of a retracted
+// local version, only Pin writes lower versions.
+func (u *Updater) Apply(path string, updates map[string]internal.CheckResult, options internal.UpdateOptions) error {
+	return u.apply(path, updates, options, false)
+}
+
+// apply is Apply which writes lower versions too if allowDowngrade is set, see applicable.
+func (u *Updater) apply(path string, updates map[string]internal.CheckResult, options internal.UpdateOptions, allowDowngrade bool) error {
+	if options.DryRun {
+		return nil
+	}
+
+	if err := checkWritable(path); err != nil {
+		return err
+	}
+
+	absolutePath, err := moduleDir(path)
+	if err != nil {
+		return err
+	}
+
 	absoluteFile := filepath.Join(absolutePath, goMod)
 	backupFile := filepath.Join(absolutePath, goModBackup)
 
 	content, err := ioutil.ReadFile(absoluteFile)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	parse, err := modfile.Parse("go.mod", content, nil)
+	format, n, err := editGoMod(content, updates, options.Remove, options.Indirect, allowDowngrade)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	filter := getLatestVersion
+	if n == 0 {
+		return nil
+	}
 
-	latestMinors, err := getModAndFilter(u.Ctx, absolutePath, filter)
+	// verify restores go.mod and go.sum from the backup when the build fails.
+	if options.Backup || options.Verify {
+		err = backup(absolutePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ioutil.WriteFile(absoluteFile, format, 0666)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	updates := 0
+	err = ioutil.WriteFile(backupFile, content, 0666)
+	if err != nil {
+		return err
+	}
 
-	for moduleName, result := range latestMinors {
-		if result.Error == nil && result.LatestVersion.GreaterThan(result.LocalVersion) {
-			err := parse.DropRequire(moduleName)
-			if err != nil {
-				return nil, err
-			}
+	if !options.Verify {
+		return nil
+	}
 
-			err = parse.AddRequire(moduleName, result.LatestVersion.Original())
-			if err != nil {
-				return nil, err
-			}
+	verifyErr := verify(u.Ctx, absolutePath)
+	if verifyErr == nil {
+		return nil
+	}
 
-			updates++
-		}
+	err = u.Restore(absolutePath)
+	if err != nil {
+		return fmt.Errorf("restore after failed verification: %w, %v", err, verifyErr)
 	}
 
-	if updates > 0 {
-		parse.Cleanup()
-		parse.SortBlocks()
+	verifyErr.Suspects = suspects(verifyErr.Output, updates, allowDowngrade)
+	verifyErr.Restored = true
 
-		format, err := parse.Format()
-		if err != nil {
-			return nil, err
+	return verifyErr
+}
+
+// applicable reports whether the update is written into go.mod, a lower version only if allowDowngrade is set.
+func applicable(result internal.CheckResult, allowDowngrade bool) bool {
+	return result.Updatable() || allowDowngrade && result.Downgrade()
+}
+
+// editGoMod returns go.mod content with the given updates applied, modules of remove dropped and // indirect comments
+// of modules of indirect set, together with the number of changed requirements. Lower versions are skipped unless
+// allowDowngrade is set, see applicable.
+func editGoMod(content []byte, updates map[string]internal.CheckResult, remove []string, indirect map[string]bool, allowDowngrade bool) ([]byte, int, error) {
+	parse, err := modfile.Parse("go.mod", content, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	n := setIndirect(parse, indirect)
+
+	for moduleName, result := range updates {
+		if !applicable(result, allowDowngrade) {
+			continue
 		}
 
-		err = ioutil.WriteFile(absoluteFile, format, 0666)
+		err := parse.DropRequire(moduleName)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
-		err = ioutil.WriteFile(backupFile, content, 0666)
+		parse.AddNewRequire(moduleName, result.LatestVersion.Original(), result.Indirect)
+
+		n++
+	}
+
+	for _, moduleName := range remove {
+		err := parse.DropRequire(moduleName)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+
+		n++
 	}
 
-	return latestMinors, nil
+	if n == 0 {
+		return content, 0, nil
+	}
+
+	parse.Cleanup()
+	parse.SortBlocks()
+
+	format, err := parse.Format()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return format, n, nil
+}
+
+// setIndirect sets // indirect comments of the given modules keeping their versions and returns the number of changed requirements.
+func setIndirect(parse *modfile.File, indirect map[string]bool) int {
+	n := 0
+	requires := make([]*modfile.Require, 0, len(parse.Require))
+
+	for _, r := range parse.Require {
+		marker, ok := indirect[r.Mod.Path]
+		if !ok || marker == r.Indirect {
+			requires = append(requires, r)
+			continue
+		}
+
+		requires = append(requires, &modfile.Require{Mod: r.Mod, Indirect: marker})
+		n++
+	}
+
+	if n > 0 {
+		parse.SetRequire(requires)
+	}
+
+	return n
 }
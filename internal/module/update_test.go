@@ -3,10 +3,15 @@ package module
 import (
 	"context"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -52,7 +57,7 @@ func (s *UpdateTestSuite) Test_() {
 		Ctx: s.ctx,
 	}
 
-	update, err := updater.Update(s.tempDir)
+	update, err := updater.Update(s.tempDir, internal.UpdateOptions{})
 
 	s.NoError(err)
 	s.NotEmpty(update)
@@ -62,3 +67,133 @@ func (s *UpdateTestSuite) Test_() {
 	s.NoError(err)
 	s.NotEqual(content, string(file))
 }
+
+func (s *UpdateTestSuite) Test_BackupRestore() {
+	updater := Updater{
+		Ctx: s.ctx,
+	}
+
+	s.Equal(ErrNoBackup, updater.Restore(s.tempDir))
+
+	s.NoError(backup(s.tempDir))
+	s.NoError(ioutil.WriteFile(s.tempFile, []byte("module changed"), 0666))
+
+	s.NoError(updater.Restore(s.tempDir))
+
+	file, err := ioutil.ReadFile(s.tempFile)
+	s.NoError(err)
+	s.Equal(content, file)
+
+	_, err = os.Stat(s.tempFile + backupSuffix)
+	s.True(os.IsNotExist(err))
+
+	sumFile := filepath.Join(s.tempDir, goSum)
+	s.NoError(ioutil.WriteFile(sumFile+backupSuffix, []byte("stale"), 0666))
+
+	s.NoError(backup(s.tempDir))
+	_, err = os.Stat(sumFile + backupSuffix)
+	s.True(os.IsNotExist(err), "a stale go.sum.bak is removed")
+
+	s.NoError(ioutil.WriteFile(sumFile, []byte("created"), 0666))
+	s.NoError(updater.Restore(s.tempDir))
+
+	_, err = os.Stat(sumFile)
+	s.True(os.IsNotExist(err), "go.sum created after the backup is removed")
+}
+
+func (s *UpdateTestSuite) Test_Pin() {
+	updater := Updater{
+		Ctx: s.ctx,
+	}
+
+	pins, err := updater.Pin(s.tempDir, []internal.Requirement{{Path: "github.com/stretchr/testify", Version: "v1.4.0"}}, internal.UpdateOptions{DryRun: true})
+	s.NoError(err)
+	s.Equal("v1.1.1", pins["github.com/stretchr/testify"].LocalVersion.Original())
+	s.Equal("v1.4.0", pins["github.com/stretchr/testify"].LatestVersion.Original())
+
+	_, err = updater.Pin(s.tempDir, []internal.Requirement{{Path: "github.com/stretchr/testify", Version: "v1.4.99"}}, internal.UpdateOptions{DryRun: true})
+	s.ErrorIs(err, ErrVersionNotFound)
+
+	_, err = updater.Pin(s.tempDir, []internal.Requirement{{Path: "github.com/stretchr/objx", Version: "v0.1.0"}}, internal.UpdateOptions{DryRun: true})
+	s.Error(err, "only required modules are pinned")
+
+	file, err := ioutil.ReadFile(s.tempFile)
+	s.NoError(err)
+	s.Equal(content, file)
+}
+
+func (s *UpdateTestSuite) Test_PinDowngrade() {
+	updater := Updater{
+		Ctx: s.ctx,
+	}
+
+	s.NoError(ioutil.WriteFile(s.tempFile, []byte("module github.com/beatlabs/gomodctl\n\ngo 1.13\n\nrequire github.com/stretchr/testify v1.4.0\n"), 0666))
+
+	_, err := updater.Pin(s.tempDir, []internal.Requirement{{Path: "github.com/stretchr/testify", Version: "v1.2.0"}}, internal.UpdateOptions{DryRun: true})
+	s.ErrorIs(err, ErrDowngrade)
+
+	pins, err := updater.Pin(s.tempDir, []internal.Requirement{{Path: "github.com/stretchr/testify", Version: "v1.2.0"}}, internal.UpdateOptions{DryRun: true, AllowDowngrade: true})
+	s.NoError(err)
+	s.True(pins["github.com/stretchr/testify"].Downgrade())
+}
+
+func (s *UpdateTestSuite) Test_EditGoModDowngrade() {
+	downgrade := internal.CheckResult{LocalVersion: semver.MustParse("v1.1.1"), LatestVersion: semver.MustParse("v1.1.0")}
+
+	format, n, err := editGoMod(content, map[string]internal.CheckResult{"github.com/stretchr/testify": downgrade}, nil, nil, false)
+	s.NoError(err)
+	s.Equal(0, n, "lower versions are skipped unless downgrades are allowed")
+	s.Equal(content, format)
+
+	format, n, err = editGoMod(content, map[string]internal.CheckResult{"github.com/stretchr/testify": downgrade}, nil, nil, true)
+	s.NoError(err)
+	s.Equal(1, n)
+	s.Contains(string(format), "github.com/stretchr/testify v1.1.0")
+}
+
+func (s *UpdateTestSuite) Test_UpdateRetractedLocal() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.4.0\nv1.5.0\n"))
+		case "/example.com/a/@v/v1.5.0.mod":
+			w.Write([]byte("module example.com/a\n\nretract v1.5.0 // published by mistake\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	goMod := []byte("module example.com/app\n\ngo 1.13\n\nrequire example.com/a v1.5.0\n")
+	s.NoError(ioutil.WriteFile(s.tempFile, goMod, 0666))
+
+	updater := Updater{
+		Ctx: s.ctx,
+	}
+
+	results, err := updater.Update(s.tempDir, internal.UpdateOptions{AllowDowngrade: true})
+	s.NoError(err)
+	s.True(results["example.com/a"].Retracted)
+	s.True(results["example.com/a"].Downgrade())
+
+	file, err := ioutil.ReadFile(s.tempFile)
+	s.NoError(err)
+	s.Equal(goMod, file, "only pins lower versions")
+}
+
+func (s *UpdateTestSuite) Test_EditGoModIndirect() {
+	format, n, err := editGoMod(content, nil, nil, map[string]bool{"github.com/stretchr/testify": true}, false)
+	s.NoError(err)
+	s.Equal(1, n)
+	s.Contains(string(format), "github.com/stretchr/testify v1.1.1 // indirect")
+
+	format, n, err = editGoMod(format, nil, nil, map[string]bool{"github.com/stretchr/testify": false}, false)
+	s.NoError(err)
+	s.Equal(1, n)
+	s.NotContains(string(format), "// indirect")
+}
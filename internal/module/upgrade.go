@@ -0,0 +1,169 @@
+package module
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"golang.org/x/mod/module"
+	modsemver "golang.org/x/mod/semver"
+)
+
+// directUpgrades fills Upgrades of the vulnerable modules which aren't direct dependencies. An upgrade is the lowest
+// version of a direct dependency requiring the module in the graph whose go.mod requires a version fixing all the
+// vulnerabilities, or doesn't need the module anymore. Since Go 1.17 go.mod lists all transitive requirements,
+// older direct dependencies are found only if they require the module themselves.
+// Later versions are assumed not to require lower versions of the module.
+func directUpgrades(resolver *versionResolver, graph map[module.Version][]module.Version, packages []PackageResult, scanned map[string]internal.VulnerabilityResult) {
+	var direct []PackageResult
+	for _, p := range packages {
+		if !p.Main && !p.Indirect && !p.Replaced && p.Position != (internal.Position{}) {
+			direct = append(direct, p)
+		}
+	}
+
+	for _, p := range packages {
+		result, ok := scanned[p.Path]
+		if !ok || p.Replaced || len(result.Vulnerabilities) == 0 || !(p.Indirect || p.Position == internal.Position{}) {
+			continue
+		}
+
+		fixed := fixedVersion(result.Vulnerabilities)
+		if fixed == "" {
+			continue
+		}
+
+		for _, d := range direct {
+			if !reaches(graph, module.Version{Path: d.Path, Version: d.LocalVersion.Original()}, p.Path) {
+				continue
+			}
+
+			if version := lowestFixing(resolver, d, p.Path, fixed); version != "" {
+				result.Upgrades = append(result.Upgrades, internal.Requirement{Path: d.Path, Version: version})
+			}
+		}
+
+		sort.Slice(result.Upgrades, func(i, j int) bool {
+			return result.Upgrades[i].Path < result.Upgrades[j].Path
+		})
+
+		scanned[p.Path] = result
+	}
+}
+
+// fixedVersion returns the lowest version fixing all the vulnerabilities, empty if any of them isn't fixed.
+func fixedVersion(vulnerabilities []internal.Vulnerability) string {
+	fixed := ""
+
+	for _, v := range vulnerabilities {
+		if v.Fixed == "" {
+			return ""
+		}
+
+		if modsemver.Compare(v.Fixed, fixed) > 0 {
+			fixed = v.Fixed
+		}
+	}
+
+	return fixed
+}
+
+// reaches tells whether the module requires a version of the target path, directly or transitively.
+func reaches(graph map[module.Version][]module.Version, from module.Version, target string) bool {
+	visited := map[module.Version]bool{from: true}
+	queue := []module.Version{from}
+
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+
+		for _, r := range graph[m] {
+			if r.Path == target {
+				return true
+			}
+
+			if !visited[r] {
+				visited[r] = true
+				queue = append(queue, r)
+			}
+		}
+	}
+
+	return false
+}
+
+// lowestFixing returns the lowest stable version of the direct dependency above its local version whose go.mod
+// requires at least the fixed version of the target, empty if even the latest one doesn't. A go.mod of Go 1.17 or
+// later which doesn't require the target fixes it too, as the target isn't in the module graph of that version.
+// Versions are binary searched, so only a few go.mod files are fetched.
+func lowestFixing(resolver *versionResolver, p PackageResult, target, fixed string) string {
+	versions, err := resolver.Versions(p.Path)
+	if err != nil {
+		logger.Debugf("versions of %s not resolved: %v", p.Path, err)
+		return ""
+	}
+
+	var candidates []*semver.Version
+	for _, v := range versions {
+		if v.Prerelease() == "" && v.GreaterThan(p.LocalVersion) {
+			candidates = append(candidates, v)
+		}
+	}
+
+	sort.Sort(semver.Collection(candidates))
+
+	fixes := func(v *semver.Version) bool {
+		content, err := resolver.goMod(p.Path, v.Original())
+		if err != nil {
+			logger.Debugf("go.mod of %s@%s not resolved: %v", p.Path, v.Original(), err)
+			return false
+		}
+
+		required := requiredVersion(content, target)
+		if required == "" {
+			goVersion := goDirective(content)
+			return goVersion != "" && compareGoVersions(goVersion, "1.17") >= 0
+		}
+
+		return modsemver.Compare(required, fixed) >= 0
+	}
+
+	if len(candidates) == 0 || !fixes(candidates[len(candidates)-1]) {
+		return ""
+	}
+
+	i := sort.Search(len(candidates)-1, func(i int) bool {
+		return fixes(candidates[i])
+	})
+
+	return candidates[i].Original()
+}
+
+// requiredVersion returns the version of the module required by go.mod content, empty if it isn't required.
+// Like goDirective it scans go.mod line by line as modfile rejects newer go directives.
+func requiredVersion(content []byte, modulePath string) string {
+	block := false
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+
+		switch {
+		case len(fields) == 2 && fields[0] == "require" && fields[1] == "(":
+			block = true
+		case block && len(fields) == 1 && fields[0] == ")":
+			block = false
+		case block && len(fields) == 2 && fields[0] == modulePath:
+			return fields[1]
+		case !block && len(fields) == 3 && fields[0] == "require" && fields[1] == modulePath:
+			return fields[2]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,90 @@
+package module
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/module"
+)
+
+func TestDirectUpgrades(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/a/@v/list":
+			w.Write([]byte("v1.0.0\nv1.1.0\nv1.2.0\nv1.3.0\nv1.4.0-rc.1\n"))
+		case "/example.com/a/@v/v1.1.0.mod":
+			w.Write([]byte("module example.com/a\n\nrequire example.com/t v0.1.0\n"))
+		case "/example.com/a/@v/v1.2.0.mod":
+			w.Write([]byte("module example.com/a\n\ngo 1.21.0\n\nrequire (\n\texample.com/t v0.2.1 // indirect\n)\n"))
+		case "/example.com/a/@v/v1.3.0.mod":
+			w.Write([]byte("module example.com/a\n\nrequire (\n\texample.com/t v0.3.0\n)\n"))
+		case "/example.com/b/@v/list":
+			w.Write([]byte("v2.0.0\n"))
+		case "/example.com/b/@v/v2.0.0.mod":
+			w.Write([]byte("module example.com/b\n\nrequire example.com/t v0.1.5\n"))
+		case "/example.com/e/@v/list":
+			w.Write([]byte("v1.1.0\nv1.2.0\n"))
+		case "/example.com/e/@v/v1.1.0.mod":
+			w.Write([]byte("module example.com/e\n"))
+		case "/example.com/e/@v/v1.2.0.mod":
+			w.Write([]byte("module example.com/e\n\ngo 1.17\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("proxy", server.URL)
+	viper.Set("no_cache", true)
+	defer viper.Set("proxy", nil)
+	defer viper.Set("no_cache", nil)
+
+	graph := map[module.Version][]module.Version{
+		{Path: "example.com/main"}:                  {{Path: "example.com/a", Version: "v1.0.0"}, {Path: "example.com/b", Version: "v1.0.0"}, {Path: "example.com/c", Version: "v1.0.0"}, {Path: "example.com/e", Version: "v1.0.0"}},
+		{Path: "example.com/a", Version: "v1.0.0"}:  {{Path: "example.com/x", Version: "v1.0.0"}},
+		{Path: "example.com/x", Version: "v1.0.0"}:  {{Path: "example.com/t", Version: "v0.1.0"}},
+		{Path: "example.com/b", Version: "v1.0.0"}:  {{Path: "example.com/t", Version: "v0.1.0"}},
+		{Path: "example.com/c", Version: "v1.0.0"}:  {{Path: "example.com/y", Version: "v1.0.0"}},
+		{Path: "example.com/e", Version: "v1.0.0"}:  {{Path: "example.com/t", Version: "v0.1.0"}},
+		{Path: "example.com/y", Version: "v1.0.0"}:  nil,
+		{Path: "example.com/t", Version: "v0.1.0"}:  nil,
+		{Path: "example.com/ok", Version: "v1.0.0"}: nil,
+	}
+
+	packages := []PackageResult{
+		{Path: "example.com/main", Main: true},
+		{Path: "example.com/a", LocalVersion: semver.MustParse("v1.0.0"), Position: internal.Position{Line: 3}},
+		{Path: "example.com/b", LocalVersion: semver.MustParse("v1.0.0"), Position: internal.Position{Line: 4}},
+		{Path: "example.com/c", LocalVersion: semver.MustParse("v1.0.0"), Position: internal.Position{Line: 5}},
+		{Path: "example.com/e", LocalVersion: semver.MustParse("v1.0.0"), Position: internal.Position{Line: 6}},
+		{Path: "example.com/t", LocalVersion: semver.MustParse("v0.1.0"), Indirect: true},
+		{Path: "example.com/x", LocalVersion: semver.MustParse("v1.0.0"), Indirect: true},
+	}
+
+	scanned := map[string]internal.VulnerabilityResult{
+		"example.com/a": {Vulnerabilities: []internal.Vulnerability{{ID: "GO-1", Fixed: "v1.1.0"}}},
+		"example.com/t": {Vulnerabilities: []internal.Vulnerability{{ID: "GO-2", Fixed: "v0.1.5"}, {ID: "GO-3", Fixed: "v0.2.0"}}},
+		"example.com/x": {Vulnerabilities: []internal.Vulnerability{{ID: "GO-4"}}},
+	}
+
+	directUpgrades(newVersionResolver(context.Background(), ""), graph, packages, scanned)
+
+	assert.Equal(t, []internal.Requirement{{Path: "example.com/a", Version: "v1.2.0"}, {Path: "example.com/e", Version: "v1.2.0"}}, scanned["example.com/t"].Upgrades)
+	assert.Empty(t, scanned["example.com/a"].Upgrades, "direct dependencies are upgraded themselves")
+	assert.Empty(t, scanned["example.com/x"].Upgrades, "there is no fix")
+}
+
+func TestRequiredVersion(t *testing.T) {
+	content := []byte("module example.com/a\n\ngo 1.21.0\n\nrequire example.com/b v1.0.0 // indirect\n\nrequire (\n\texample.com/c v1.2.0\n\texample.com/d v0.1.0 // indirect\n)\n")
+
+	assert.Equal(t, "v1.0.0", requiredVersion(content, "example.com/b"))
+	assert.Equal(t, "v1.2.0", requiredVersion(content, "example.com/c"))
+	assert.Equal(t, "v0.1.0", requiredVersion(content, "example.com/d"))
+	assert.Empty(t, requiredVersion(content, "example.com/e"))
+}
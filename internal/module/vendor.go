@@ -0,0 +1,136 @@
+package module
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+const vendorModules = "vendor/modules.txt"
+
+// ParseVendor returns modules listed in vendor/modules.txt of the module in the given path, keyed by module path.
+func (v *ModParser) ParseVendor(path string) (map[string]PackageResult, error) {
+	dir, err := moduleDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := filepath.Join(dir, vendorModules)
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseVendorModules(file, content)
+}
+
+// parseVendorModules parses the module lines of vendor/modules.txt content, file is its name in errors.
+// A module line is "# path version" optionally followed by "=> replacement [version]",
+// package and "##" annotation lines are left out.
+func parseVendorModules(file string, content []byte) (map[string]PackageResult, error) {
+	result := make(map[string]PackageResult)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		text := scanner.Text()
+		if !strings.HasPrefix(text, "# ") {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimPrefix(text, "# "))
+
+		var replacement []string
+		for i, f := range fields {
+			if f == "=>" {
+				fields, replacement = fields[:i], fields[i+1:]
+				break
+			}
+		}
+
+		if len(fields) == 0 || len(fields) > 2 || (replacement != nil && (len(replacement) == 0 || len(replacement) > 2)) {
+			return nil, fmt.Errorf("%s:%d: invalid module line %q", file, line, text)
+		}
+
+		packageResult := PackageResult{Path: fields[0]}
+		version := ""
+
+		if len(fields) == 2 {
+			packageResult.Version = fields[1]
+			version = fields[1]
+		}
+
+		if replacement != nil {
+			packageResult.Replaced = true
+			packageResult.ReplacePath = replacement[0]
+			// local path replacements have no version.
+			version = ""
+			if len(replacement) == 2 {
+				version = replacement[1]
+			}
+		}
+
+		if version != "" {
+			var err error
+			packageResult.LocalVersion, err = semver.NewVersion(version)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid version of %s: %w", file, line, packageResult.Path, err)
+			}
+		}
+
+		result[packageResult.Path] = packageResult
+	}
+
+	return result, scanner.Err()
+}
+
+// vendorMismatch returns ErrVendorMismatch if the module isn't vendored as go.mod requires it.
+// It returns nil if vendored is nil, i.e. vendor isn't checked.
+func vendorMismatch(vendored map[string]PackageResult, result PackageResult) error {
+	if vendored == nil {
+		return nil
+	}
+
+	v, ok := vendored[result.Path]
+	if !ok {
+		return fmt.Errorf("%w: not vendored", internal.ErrVendorMismatch)
+	}
+
+	if v.Version != result.Version || v.ResolvePath() != result.ResolvePath() || !sameVersion(v.LocalVersion, result.LocalVersion) {
+		return fmt.Errorf("%w: vendored at %s", internal.ErrVendorMismatch, vendoredVersion(v))
+	}
+
+	return nil
+}
+
+// vendoredVersion describes the vendored version of the module, including its replacement.
+func vendoredVersion(v PackageResult) string {
+	if !v.Replaced {
+		return v.Version
+	}
+
+	s := v.Version + " => " + v.ReplacePath
+	if v.LocalVersion != nil {
+		s += " " + v.LocalVersion.Original()
+	}
+
+	return strings.TrimSpace(s)
+}
+
+func sameVersion(a, b *semver.Version) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Original() == b.Original()
+}
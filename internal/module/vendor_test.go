@@ -0,0 +1,72 @@
+package module
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVendorModules(t *testing.T) {
+	content := `# example.com/a v1.0.0
+## explicit
+example.com/a
+example.com/a/sub
+# example.com/b v1.1.0 => example.com/fork v1.1.1
+## explicit; go 1.15
+example.com/b
+# example.com/c v0.1.0 => ../c
+# example.com/d => ../d
+`
+
+	vendored, err := parseVendorModules(vendorModules, []byte(content))
+	require.NoError(t, err)
+	require.Len(t, vendored, 4)
+
+	assert.Equal(t, "v1.0.0", vendored["example.com/a"].Version)
+	assert.Equal(t, "v1.0.0", vendored["example.com/a"].LocalVersion.Original())
+	assert.False(t, vendored["example.com/a"].Replaced)
+
+	assert.Equal(t, "v1.1.0", vendored["example.com/b"].Version)
+	assert.True(t, vendored["example.com/b"].Replaced)
+	assert.Equal(t, "example.com/fork", vendored["example.com/b"].ReplacePath)
+	assert.Equal(t, "v1.1.1", vendored["example.com/b"].LocalVersion.Original())
+
+	assert.Equal(t, "../c", vendored["example.com/c"].ReplacePath)
+	assert.Nil(t, vendored["example.com/c"].LocalVersion)
+	assert.Equal(t, "", vendored["example.com/d"].Version)
+
+	_, err = parseVendorModules(vendorModules, []byte("# example.com/a v1.0.0 extra\n"))
+	assert.EqualError(t, err, `vendor/modules.txt:1: invalid module line "# example.com/a v1.0.0 extra"`)
+
+	_, err = parseVendorModules(vendorModules, []byte("# example.com/a\n# example.com/b latest\n"))
+	assert.Error(t, err)
+}
+
+func TestVendorMismatch(t *testing.T) {
+	vendored := map[string]PackageResult{
+		"example.com/a": {Path: "example.com/a", Version: "v1.0.0", LocalVersion: semver.MustParse("v1.0.0")},
+		"example.com/b": {Path: "example.com/b", Version: "v1.1.0", Replaced: true, ReplacePath: "example.com/fork", LocalVersion: semver.MustParse("v1.1.1")},
+	}
+
+	a := PackageResult{Path: "example.com/a", Version: "v1.0.0", LocalVersion: semver.MustParse("v1.0.0")}
+	b := PackageResult{Path: "example.com/b", Version: "v1.1.0", Replaced: true, ReplacePath: "example.com/fork", LocalVersion: semver.MustParse("v1.1.1")}
+
+	assert.NoError(t, vendorMismatch(nil, a))
+	assert.NoError(t, vendorMismatch(vendored, a))
+	assert.NoError(t, vendorMismatch(vendored, b))
+
+	a.Version, a.LocalVersion = "v1.2.0", semver.MustParse("v1.2.0")
+	err := vendorMismatch(vendored, a)
+	assert.True(t, errors.Is(err, internal.ErrVendorMismatch))
+	assert.EqualError(t, err, "vendor/modules.txt doesn't match go.mod: vendored at v1.0.0")
+
+	b.Replaced, b.ReplacePath, b.LocalVersion = false, "", semver.MustParse("v1.1.0")
+	assert.EqualError(t, vendorMismatch(vendored, b), "vendor/modules.txt doesn't match go.mod: vendored at v1.1.0 => example.com/fork v1.1.1")
+
+	err = vendorMismatch(vendored, PackageResult{Path: "example.com/c", Version: "v0.1.0"})
+	assert.EqualError(t, err, "vendor/modules.txt doesn't match go.mod: not vendored")
+}
@@ -0,0 +1,105 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+)
+
+// VerifyError is returned when the module doesn't build after an update.
+type VerifyError struct {
+	// Command is the go command which failed, e.g. go build ./...
+	Command string
+	Output  string
+	// Suspects are updated modules mentioned in the output, which likely caused the failure.
+	Suspects []string
+	// Restored is true if go.mod and go.sum were restored from the backup.
+	Restored bool
+	Err      error
+}
+
+func (e *VerifyError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s failed after update: %v", e.Command, e.Err)
+
+	if e.Output != "" {
+		fmt.Fprintf(&b, "\n%s", strings.TrimRight(e.Output, "\n"))
+	}
+
+	if len(e.Suspects) > 0 {
+		fmt.Fprintf(&b, "\nlikely caused by: %s", strings.Join(e.Suspects, ", "))
+	}
+
+	if e.Restored {
+		b.WriteString("\ngo.mod and go.sum restored from backup")
+	}
+
+	return b.String()
+}
+
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
+
+// verify runs go mod tidy and go build ./... in the given module directory.
+func verify(ctx context.Context, dir string) *VerifyError {
+	for _, args := range [][]string{{"mod", "tidy"}, {"build", "./..."}} {
+		cmd := exec.CommandContext(ctx, "go", args...)
+		cmd.Dir = dir
+		cmd.Env = goEnv()
+
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return &VerifyError{Command: "go " + strings.Join(args, " "), Output: string(out), Err: err}
+		}
+	}
+
+	return nil
+}
+
+// suspects returns updated modules whose path appears in the output of a failed go command.
+func suspects(output string, updates map[string]internal.CheckResult, allowDowngrade bool) []string {
+	var names []string
+
+	for name, result := range updates {
+		if !applicable(result, allowDowngrade) {
+			continue
+		}
+
+		if mentions(output, name) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// mentions reports whether output contains the module path itself and not only a longer path starting with it,
+// e.g. example.com/foo@v1.2.0 or example.com/foo/bar mention example.com/foo but example.com/foobar doesn't.
+func mentions(output, modulePath string) bool {
+	for i := strings.Index(output, modulePath); i >= 0; {
+		end := i + len(modulePath)
+		if end == len(output) || !isPathChar(output[end]) {
+			return true
+		}
+
+		next := strings.Index(output[end:], modulePath)
+		if next < 0 {
+			return false
+		}
+		i = end + next
+	}
+
+	return false
+}
+
+func isPathChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0
+}
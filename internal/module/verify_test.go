@@ -0,0 +1,85 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, goMod), []byte("module example.com/verify\n\ngo 1.15\n"), 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0666))
+
+	assert.Nil(t, verify(context.Background(), dir))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() { undefined() }\n"), 0666))
+
+	verifyErr := verify(context.Background(), dir)
+	require.NotNil(t, verifyErr)
+	assert.Equal(t, "go build ./...", verifyErr.Command)
+	assert.Contains(t, verifyErr.Output, "undefined")
+}
+
+func TestApplyVerifyRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the module cache has github.com/Masterminds/semver, a dependency of gomodctl, so tidy runs offline.
+	viper.Set("proxy", "off")
+	defer viper.Set("proxy", nil)
+	defer os.Setenv("GOSUMDB", os.Getenv("GOSUMDB"))
+	require.NoError(t, os.Setenv("GOSUMDB", "off"))
+
+	goModContent := []byte("module example.com/verify\n\ngo 1.15\n\nrequire (\n\tgithub.com/Masterminds/semver v1.5.0\n\tgithub.com/spf13/pflag v1.0.5\n)\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, goMod), goModContent, 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nimport \"github.com/Masterminds/semver\"\n\nfunc main() { semver.MustParse(undefined) }\n"), 0666))
+
+	updater := Updater{Ctx: context.Background()}
+
+	err = updater.Apply(dir, nil, internal.UpdateOptions{Verify: true, Remove: []string{"github.com/spf13/pflag"}})
+
+	var verifyErr *VerifyError
+	require.True(t, errors.As(err, &verifyErr), "%v", err)
+	assert.Equal(t, "go build ./...", verifyErr.Command, "go mod tidy succeeds and creates go.sum")
+	assert.True(t, verifyErr.Restored)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, goMod))
+	require.NoError(t, err)
+	assert.Equal(t, goModContent, content)
+
+	_, err = os.Stat(filepath.Join(dir, goSum))
+	assert.True(t, os.IsNotExist(err), "go.sum created by go mod tidy is removed")
+}
+
+func TestSuspects(t *testing.T) {
+	updates := map[string]internal.CheckResult{
+		"example.com/foo": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.1.0")},
+		"example.com/bar": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.1.0")},
+		"example.com/baz": {LocalVersion: semver.MustParse("v1.1.0"), LatestVersion: semver.MustParse("v1.1.0")},
+	}
+
+	output := `# example.com/app
+../pkg/mod/example.com/foo@v1.1.0/foo.go:10:2: undefined: bar
+../pkg/mod/example.com/barista@v1.0.0/a.go:1:1: example.com/baz is mentioned, but not updated`
+
+	assert.Equal(t, []string{"example.com/foo"}, suspects(output, updates, false))
+	assert.Empty(t, suspects("./main.go:3:15: undefined: x", updates, false))
+
+	updates["example.com/baz"] = internal.CheckResult{LocalVersion: semver.MustParse("v1.1.0"), LatestVersion: semver.MustParse("v1.0.0")}
+	assert.Equal(t, []string{"example.com/foo"}, suspects(output, updates, false), "lower versions aren't written")
+	assert.Equal(t, []string{"example.com/baz", "example.com/foo"}, suspects(output, updates, true))
+}
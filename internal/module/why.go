@@ -0,0 +1,99 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"golang.org/x/mod/module"
+)
+
+// ErrNotInGraph is returned when the module isn't required by the main module, not even transitively.
+var ErrNotInGraph = errors.New("module isn't in the module graph")
+
+// Explainer tells why a module is in the module graph.
+type Explainer struct {
+	Ctx context.Context
+}
+
+// Why returns the shortest requirement chain from the main module in the given path to the target module,
+// the first element is the main module and the last one the target. The target is a module path, with an optional @version.
+func (e *Explainer) Why(path, target string) ([]internal.Requirement, error) {
+	parser := ModParser{ctx: e.Ctx}
+
+	graph, err := parser.Graph(path)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := shortestChain(graph, target)
+	if errors.Is(err, ErrNotInGraph) {
+		return nil, fmt.Errorf("%s isn't required by the main module, not even transitively: %w", target, err)
+	}
+
+	return chain, err
+}
+
+// shortestChain searches the graph breadth first from the main module, the module without a version.
+func shortestChain(graph map[module.Version][]module.Version, target string) ([]internal.Requirement, error) {
+	targetPath, targetVersion := target, ""
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		targetPath, targetVersion = target[:i], target[i+1:]
+	}
+
+	var main *module.Version
+	for m := range graph {
+		if m.Version == "" {
+			m := m
+			main = &m
+			break
+		}
+	}
+	if main == nil {
+		return nil, ErrNotInGraph
+	}
+
+	parents := map[module.Version]module.Version{}
+	visited := map[module.Version]bool{*main: true}
+	queue := []module.Version{*main}
+
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+
+		if m.Path == targetPath && (targetVersion == "" || m.Version == targetVersion) && m != *main {
+			return chain(parents, *main, m), nil
+		}
+
+		for _, r := range graph[m] {
+			if !visited[r] {
+				visited[r] = true
+				parents[r] = m
+				queue = append(queue, r)
+			}
+		}
+	}
+
+	return nil, ErrNotInGraph
+}
+
+// chain walks parents back from the target to the main module.
+func chain(parents map[module.Version]module.Version, main, target module.Version) []internal.Requirement {
+	var reversed []internal.Requirement
+
+	for m := target; ; m = parents[m] {
+		reversed = append(reversed, internal.Requirement{Path: m.Path, Version: m.Version})
+		if m == main {
+			break
+		}
+	}
+
+	result := make([]internal.Requirement, len(reversed))
+	for i, r := range reversed {
+		result[len(reversed)-1-i] = r
+	}
+
+	return result
+}
@@ -0,0 +1,44 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/module"
+)
+
+func TestShortestChain(t *testing.T) {
+	main := module.Version{Path: "example.com/main"}
+	a := module.Version{Path: "example.com/a", Version: "v1.0.0"}
+	b := module.Version{Path: "example.com/b", Version: "v1.0.0"}
+	c := module.Version{Path: "example.com/c", Version: "v1.0.0"}
+	c2 := module.Version{Path: "example.com/c", Version: "v1.1.0"}
+	d := module.Version{Path: "example.com/d", Version: "v1.0.0"}
+
+	graph := map[module.Version][]module.Version{
+		main: {a, b},
+		a:    {c},
+		b:    {d},
+		d:    {c2},
+	}
+
+	chain, err := shortestChain(graph, "example.com/c")
+	assert.NoError(t, err)
+	assert.Equal(t, []internal.Requirement{
+		{Path: "example.com/main"},
+		{Path: "example.com/a", Version: "v1.0.0"},
+		{Path: "example.com/c", Version: "v1.0.0"},
+	}, chain)
+
+	chain, err = shortestChain(graph, "example.com/c@v1.1.0")
+	assert.NoError(t, err)
+	assert.Len(t, chain, 4)
+	assert.Equal(t, "example.com/d", chain[2].Path)
+
+	_, err = shortestChain(graph, "example.com/x")
+	assert.Equal(t, ErrNotInGraph, err)
+
+	_, err = shortestChain(graph, "example.com/main")
+	assert.Equal(t, ErrNotInGraph, err)
+}
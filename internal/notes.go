@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// ReleaseNotesURL returns a best-effort URL of the release notes of the latest version of a module, empty if there is none.
+// Only modules hosted on GitHub are supported. The URL compares the tags of the current and the latest version, without a
+// tagged current version it is the release page of the latest one. Tags of a module in a subdirectory of the repository
+// are prefixed with the subdirectory, like the go command expects.
+func ReleaseNotesURL(modulePath, current, latest string) string {
+	prefix, _, ok := module.SplitPathVersion(modulePath)
+	if !ok || latest == "" || IsPseudoVersion(latest) {
+		return ""
+	}
+
+	parts := strings.SplitN(prefix, "/", 4)
+	if len(parts) < 3 || parts[0] != "github.com" {
+		return ""
+	}
+
+	repository := "https://github.com/" + parts[1] + "/" + parts[2]
+
+	tagPrefix := ""
+	if len(parts) == 4 {
+		tagPrefix = parts[3] + "/"
+	}
+
+	tag := func(version string) string {
+		return tagPrefix + strings.TrimSuffix(version, "+incompatible")
+	}
+
+	if current == "" || IsPseudoVersion(current) {
+		return repository + "/releases/tag/" + tag(latest)
+	}
+
+	return repository + "/compare/" + tag(current) + "..." + tag(latest)
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseNotesURL(t *testing.T) {
+	for _, tc := range []struct {
+		path, current, latest, expected string
+	}{
+		{"github.com/spf13/cobra", "v1.1.1", "v1.2.0", "https://github.com/spf13/cobra/compare/v1.1.1...v1.2.0"},
+		{"github.com/go-resty/resty/v2", "v2.3.0", "v2.4.0", "https://github.com/go-resty/resty/compare/v2.3.0...v2.4.0"},
+		{"github.com/aws/aws-sdk-go-v2/service/s3", "v1.1.0", "v1.2.0", "https://github.com/aws/aws-sdk-go-v2/compare/service/s3/v1.1.0...service/s3/v1.2.0"},
+		{"github.com/docker/docker", "v17.3.0+incompatible", "v20.10.0+incompatible", "https://github.com/docker/docker/compare/v17.3.0...v20.10.0"},
+		{"github.com/spf13/cobra", "", "v1.2.0", "https://github.com/spf13/cobra/releases/tag/v1.2.0"},
+		{"github.com/spf13/cobra", "v0.0.0-20191109021931-daa7c04131f5", "v1.2.0", "https://github.com/spf13/cobra/releases/tag/v1.2.0"},
+		{"github.com/spf13/cobra", "v1.1.1", "v0.0.0-20191109021931-daa7c04131f5", ""},
+		{"github.com/spf13", "v1.1.1", "v1.2.0", ""},
+		{"golang.org/x/mod", "v0.4.1", "v0.5.0", ""},
+	} {
+		assert.Equal(t, tc.expected, ReleaseNotesURL(tc.path, tc.current, tc.latest), tc.path)
+	}
+}
@@ -0,0 +1,61 @@
+package printer
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// JUnitTestSuite is a JUnit XML report of a single test suite.
+type JUnitTestSuite struct {
+	XMLName  xml.Name        `xml:"testsuite"`
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Cases    []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a test case of JUnit XML report, it passes if it has neither failure nor error.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *JUnitMessage `xml:"failure,omitempty"`
+	Error     *JUnitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitMessage is a failure or an error of a test case, Text holds the details.
+type JUnitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitPrintable is implemented by results which can be printed as a JUnit XML report.
+type JUnitPrintable interface {
+	JUnitData() *JUnitTestSuite
+}
+
+// PrintJUnit prints printable result as a JUnit XML report, counts of the suite are computed from its test cases.
+func PrintJUnit(p JUnitPrintable) {
+	suite := p.JUnitData()
+
+	suite.Tests, suite.Failures, suite.Errors = len(suite.Cases), 0, 0
+	for _, c := range suite.Cases {
+		if c.Failure != nil {
+			suite.Failures++
+		}
+		if c.Error != nil {
+			suite.Errors++
+		}
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		fmt.Fprintln(output, "failed to write junit", err)
+		return
+	}
+
+	fmt.Fprint(output, xml.Header)
+	fmt.Fprintln(output, string(data))
+}
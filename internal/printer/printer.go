@@ -1,13 +1,179 @@
 package printer
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
+// Output formats of the --format flag.
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+	FormatJUnit    = "junit"
+	FormatYAML     = "yaml"
+)
+
+// Formats contains output formats supported by every command.
+var Formats = []string{FormatTable, FormatJSON, FormatCSV, FormatMarkdown}
+
+// FormatsAnnotation is the cobra command annotation listing space separated output formats
+// the command supports besides Formats, e.g. junit.
+const FormatsAnnotation = "gomodctl_formats"
+
+// Color modes of the --color flag.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// ColorModes contains all supported color modes.
+var ColorModes = []string{ColorAuto, ColorAlways, ColorNever}
+
+// Color is a foreground color of a table cell.
+type Color int
+
+// Colors of table cells.
+const (
+	ColorNone   Color = 0
+	ColorRed    Color = Color(tablewriter.FgRedColor)
+	ColorGreen  Color = Color(tablewriter.FgGreenColor)
+	ColorYellow Color = Color(tablewriter.FgYellowColor)
+)
+
+// colorMode tells when tables are colored.
+var colorMode = ColorAuto
+
+// SetColorMode sets when tables are colored, auto colors them only if results are printed to a terminal and NO_COLOR isn't set.
+func SetColorMode(mode string) error {
+	for _, m := range ColorModes {
+		if m == mode {
+			colorMode = mode
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid --color value %q, must be one of %s", mode, strings.Join(ColorModes, ", "))
+}
+
+// colorEnabled tells whether tables are colored, see https://no-color.org for NO_COLOR.
+func colorEnabled() bool {
+	switch colorMode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return IsTerminal()
+}
+
+// IsTerminal reports whether results are printed to a terminal.
+func IsTerminal() bool {
+	f, ok := output.(*os.File)
+
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// output is where results are printed.
+var output io.Writer = os.Stdout
+
+// SetOutput sets where results are printed, stdout by default.
+func SetOutput(w io.Writer) {
+	output = w
+}
+
+// Output returns the writer results are printed to, commands printing results on their own should use it.
+func Output() io.Writer {
+	return output
+}
+
+// WriteFile writes content to the file, missing parent directories are created.
+func WriteFile(file string, content []byte) error {
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, content, 0644)
+}
+
+// CreateFile creates or truncates the file for writing, missing parent directories are created.
+func CreateFile(file string) (*os.File, error) {
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Create(file)
+}
+
+// ValidateFormat returns error if format is neither one of Formats nor one of the extra ones.
+func ValidateFormat(format string, extra ...string) error {
+	formats := append(append([]string{}, Formats...), extra...)
+
+	for _, f := range formats {
+		if f == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid --format value %q, must be one of %s", format, strings.Join(formats, ", "))
+}
+
+// FormatOf returns output format of the --format flag, the deprecated --json flag takes precedence.
+func FormatOf(flags *pflag.FlagSet) string {
+	if j, _ := flags.GetBool("json"); j {
+		return FormatJSON
+	}
+
+	format, _ := flags.GetString("format")
+	if format == "" {
+		return FormatTable
+	}
+
+	return format
+}
+
+// Print prints printable result in the given format, table is used for an unknown format.
+func Print(p Printable, format string) {
+	switch format {
+	case FormatJSON:
+		PrintJSON(p)
+	case FormatCSV:
+		PrintCSV(p)
+	case FormatMarkdown:
+		PrintMarkdown(p)
+	case FormatYAML:
+		PrintYAML(p)
+	case FormatJUnit:
+		if jp, ok := p.(JUnitPrintable); ok {
+			PrintJUnit(jp)
+			return
+		}
+
+		PrintTable(p)
+	default:
+		PrintTable(p)
+	}
+}
+
 // TableData defines print options for table output.
 type TableData struct {
 	Header       []string
@@ -16,6 +182,8 @@ type TableData struct {
 	ShowBorder   bool
 	ShowRowLine  bool
 	Data         [][]string
+	// Colors of the data cells by row and column, it may be shorter than data, it is used only in table output.
+	Colors [][]Color
 }
 
 // Printable defines contract to be implemented in order to print
@@ -28,13 +196,33 @@ type Printable interface {
 // PrintTable prints printable result as a table output.
 func PrintTable(p Printable) {
 	td := p.TableData()
-	table := tablewriter.NewWriter(os.Stdout)
+	table := tablewriter.NewWriter(output)
 	table.SetHeader(td.Header)
 	table.SetFooter(td.Footer)
 	table.SetRowSeparator(td.RowSeparator)
 	table.SetBorder(td.ShowBorder)
 	table.SetRowLine(td.ShowRowLine)
-	table.AppendBulk(td.Data)
+
+	if !colorEnabled() {
+		td.Colors = nil
+	}
+
+	for i, row := range td.Data {
+		if i >= len(td.Colors) || td.Colors[i] == nil {
+			table.Append(row)
+			continue
+		}
+
+		colors := make([]tablewriter.Colors, len(td.Colors[i]))
+		for j, c := range td.Colors[i] {
+			if c != ColorNone {
+				colors[j] = tablewriter.Colors{int(c)}
+			}
+		}
+
+		table.Rich(row, colors)
+	}
+
 	table.Render()
 }
 
@@ -42,14 +230,62 @@ func PrintTable(p Printable) {
 func PrintJSON(p Printable) {
 	data := p.JSONData()
 	if data == nil {
-		fmt.Println("no data")
+		fmt.Fprintln(output, "no data")
 		return
 	}
 
 	dataB, err := json.Marshal(data)
 	if err != nil {
-		fmt.Println("failed to parse json", err)
+		fmt.Fprintln(output, "failed to parse json", err)
 	} else {
-		fmt.Println(string(dataB))
+		fmt.Fprintln(output, string(dataB))
 	}
 }
+
+// PrintCSV prints header and rows of the table data as CSV, footer is left out.
+func PrintCSV(p Printable) {
+	td := p.TableData()
+
+	w := csv.NewWriter(output)
+	w.Write(td.Header)
+	w.WriteAll(td.Data)
+
+	if err := w.Error(); err != nil {
+		fmt.Fprintln(output, "failed to write csv", err)
+	}
+}
+
+// PrintMarkdown prints header and rows of the table data as a GitHub flavored markdown table, footer is left out.
+func PrintMarkdown(p Printable) {
+	td := p.TableData()
+
+	var b strings.Builder
+
+	writeMarkdownRow(&b, td.Header)
+
+	separator := make([]string, len(td.Header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(&b, separator)
+
+	for _, row := range td.Data {
+		writeMarkdownRow(&b, row)
+	}
+
+	fmt.Fprint(output, b.String())
+}
+
+var markdownReplacer = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+
+	for _, cell := range cells {
+		b.WriteString(" ")
+		b.WriteString(markdownReplacer.Replace(cell))
+		b.WriteString(" |")
+	}
+
+	b.WriteString("\n")
+}
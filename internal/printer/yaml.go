@@ -0,0 +1,92 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// PrintYAML prints printable result as a YAML output with the same schema as the JSON output.
+func PrintYAML(p Printable) {
+	data := p.JSONData()
+	if data == nil {
+		fmt.Fprintln(output, "no data")
+		return
+	}
+
+	dataB, err := toYAML(data)
+	if err != nil {
+		fmt.Fprintln(output, "failed to parse yaml", err)
+		return
+	}
+
+	fmt.Fprint(output, string(dataB))
+}
+
+// toYAML marshals data through its JSON encoding, so json tags, omitempty and
+// custom marshalers apply and the key order matches the JSON output.
+func toYAML(data interface{}) ([]byte, error) {
+	dataB, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(dataB))
+	decoder.UseNumber()
+
+	value, err := decodeOrdered(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(value)
+}
+
+// decodeOrdered decodes the next JSON value, objects are decoded into a yaml.MapSlice to keep the key order.
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '[' {
+			list := []interface{}{}
+			for decoder.More() {
+				item, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			_, err = decoder.Token()
+			return list, err
+		}
+
+		object := yaml.MapSlice{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, yaml.MapItem{Key: key, Value: value})
+		}
+		_, err = decoder.Token()
+		return object, err
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	default:
+		return t, nil
+	}
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Priority orders modules of check and update, e.g. to apply the most important updates first.
+type Priority string
+
+const (
+	// PrioritySecurity puts modules with known vulnerabilities first, the highest rated first.
+	PrioritySecurity Priority = "security"
+	// PriorityStaleness puts modules with the oldest local versions first, unknown publish times last.
+	PriorityStaleness Priority = "staleness"
+	// PriorityType puts patches before minor updates before major updates, modules without an update last.
+	PriorityType Priority = "type"
+	// PriorityAlpha orders modules by path.
+	PriorityAlpha Priority = "alpha"
+)
+
+// Priorities are the valid priorities.
+var Priorities = []Priority{PrioritySecurity, PriorityStaleness, PriorityType, PriorityAlpha}
+
+var updateTypeRanks = map[UpdateType]int{
+	UpdatePatch: 0,
+	UpdateMinor: 1,
+	UpdateMajor: 2,
+	UpdateNone:  3,
+}
+
+// ParsePriority parses a priority.
+func ParsePriority(s string) (Priority, error) {
+	for _, p := range Priorities {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+
+	names := make([]string, 0, len(Priorities))
+	for _, p := range Priorities {
+		names = append(names, string(p))
+	}
+
+	return "", fmt.Errorf("invalid priority %q, must be one of %s", s, strings.Join(names, ", "))
+}
+
+// Prioritize returns names of the results in order of the priority, ties are ordered by path.
+// Severities are the highest ratings of advisories by module, they are used only by PrioritySecurity.
+func Prioritize(results map[string]CheckResult, priority Priority, severities map[string]Severity) []string {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		a, b := results[names[i]], results[names[j]]
+
+		switch priority {
+		case PrioritySecurity:
+			sa, va := severities[names[i]]
+			sb, vb := severities[names[j]]
+			if va != vb {
+				return va
+			}
+			if sa.Rank() != sb.Rank() {
+				return sa.Rank() > sb.Rank()
+			}
+		case PriorityStaleness:
+			if a.LocalTime.IsZero() != b.LocalTime.IsZero() {
+				return !a.LocalTime.IsZero()
+			}
+			if !a.LocalTime.Equal(b.LocalTime) {
+				return a.LocalTime.Before(b.LocalTime)
+			}
+		case PriorityType:
+			if ra, rb := updateTypeRanks[a.UpdateType()], updateTypeRanks[b.UpdateType()]; ra != rb {
+				return ra < rb
+			}
+		}
+
+		return names[i] < names[j]
+	})
+
+	return names
+}
@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrioritize(t *testing.T) {
+	now := time.Now()
+
+	results := map[string]CheckResult{
+		"example.com/a": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v2.0.0"), LocalTime: now.Add(-time.Hour)},
+		"example.com/b": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.0.1"), LocalTime: now.Add(-3 * time.Hour)},
+		"example.com/c": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.1.0")},
+		"example.com/d": {LocalVersion: semver.MustParse("v1.0.0"), LatestVersion: semver.MustParse("v1.0.0"), LocalTime: now.Add(-2 * time.Hour)},
+	}
+
+	severities := map[string]Severity{"example.com/c": SeverityLow, "example.com/d": SeverityHigh, "example.com/a": SeverityUnknown}
+
+	assert.Equal(t, []string{"example.com/a", "example.com/b", "example.com/c", "example.com/d"}, Prioritize(results, PriorityAlpha, nil))
+	assert.Equal(t, []string{"example.com/b", "example.com/c", "example.com/a", "example.com/d"}, Prioritize(results, PriorityType, nil))
+	assert.Equal(t, []string{"example.com/b", "example.com/d", "example.com/a", "example.com/c"}, Prioritize(results, PriorityStaleness, nil))
+	assert.Equal(t, []string{"example.com/d", "example.com/c", "example.com/a", "example.com/b"}, Prioritize(results, PrioritySecurity, severities))
+}
+
+func TestParsePriority(t *testing.T) {
+	p, err := ParsePriority("staleness")
+	assert.NoError(t, err)
+	assert.Equal(t, PriorityStaleness, p)
+
+	_, err = ParsePriority("size")
+	assert.EqualError(t, err, `invalid priority "size", must be one of security, staleness, type, alpha`)
+}
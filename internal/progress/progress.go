@@ -0,0 +1,119 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/beatlabs/gomodctl/internal/logger"
+	"github.com/beatlabs/gomodctl/internal/printer"
+	"golang.org/x/term"
+)
+
+// interval is how often the spinner frame changes.
+const interval = 100 * time.Millisecond
+
+var frames = []string{"|", "/", "-", "\\"}
+
+// Spinner renders an animated spinner with a done/total counter on a single terminal line.
+// Methods of a nil Spinner do nothing, so callers don't need to check whether progress is enabled.
+type Spinner struct {
+	w      io.Writer
+	format string
+
+	mu    sync.Mutex
+	done  int
+	total int
+	frame int
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// Enabled reports whether progress should be rendered, only for table output and if stderr is a terminal.
+// Debug logs are written to stderr too, so there is no progress with them.
+func Enabled(format string) bool {
+	return format == printer.FormatTable && !logger.Enabled(logger.LevelDebug) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// Start starts rendering the spinner to w, format is used with done and total counts, e.g. "checked %d/%d modules".
+func Start(w io.Writer, format string) *Spinner {
+	s := &Spinner{
+		w:       w,
+		format:  format,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s
+}
+
+// StartIfEnabled starts a spinner on stderr if Enabled, it returns nil otherwise.
+func StartIfEnabled(outputFormat, format string) *Spinner {
+	if !Enabled(outputFormat) {
+		return nil
+	}
+
+	return Start(os.Stderr, format)
+}
+
+func (s *Spinner) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			s.frame = (s.frame + 1) % len(frames)
+			s.render()
+			s.mu.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Update sets the counts and renders them, it is safe for concurrent use.
+func (s *Spinner) Update(done, total int) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done, s.total = done, total
+	s.render()
+}
+
+// Stop stops the spinner and clears its line.
+func (s *Spinner) Stop() {
+	if s == nil {
+		return
+	}
+
+	close(s.stop)
+	<-s.stopped
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Fprint(s.w, "\r\x1b[K")
+}
+
+// render must be called with mu held, counts are left out until the total is known.
+func (s *Spinner) render() {
+	if s.total == 0 {
+		fmt.Fprintf(s.w, "\r\x1b[K%s", frames[s.frame])
+		return
+	}
+
+	fmt.Fprintf(s.w, "\r\x1b[K%s %s", frames[s.frame], fmt.Sprintf(s.format, s.done, s.total))
+}
@@ -0,0 +1,33 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpinner(t *testing.T) {
+	var buf bytes.Buffer
+
+	s := Start(&buf, "checked %d/%d modules")
+	s.Update(1, 3)
+	s.Update(3, 3)
+	s.Stop()
+
+	out := buf.String()
+	assert.Contains(t, out, "checked 1/3 modules")
+	assert.Contains(t, out, "checked 3/3 modules")
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte("\r\x1b[K")))
+}
+
+func TestSpinner_Nil(t *testing.T) {
+	var s *Spinner
+
+	s.Update(1, 2)
+	s.Stop()
+}
+
+func TestEnabled(t *testing.T) {
+	assert.False(t, Enabled("json"))
+}
@@ -0,0 +1,15 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pseudoVersionRE matches the three pseudo-version forms, e.g. v0.0.0-20191109021931-daa7c04131f5 for a module without
+// tags, v1.2.4-0.20191109021931-daa7c04131f5 after the v1.2.3 tag and v1.2.3-pre.0.20191109021931-daa7c04131f5 after a prerelease.
+var pseudoVersionRE = regexp.MustCompile(`^v[0-9]+\.(0\.0-|[0-9]+\.[0-9]+-([^+]*\.)?0\.)[0-9]{14}-[A-Za-z0-9]+(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// IsPseudoVersion reports whether the version is a pseudo-version the go command generates for an untagged commit.
+func IsPseudoVersion(version string) bool {
+	return strings.Count(version, "-") >= 2 && pseudoVersionRE.MatchString(version)
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPseudoVersion(t *testing.T) {
+	for _, version := range []string{
+		"v0.0.0-20191109021931-daa7c04131f5",
+		"v1.2.4-0.20191109021931-daa7c04131f5",
+		"v1.2.3-pre.0.20191109021931-daa7c04131f5",
+		"v2.0.0-20191109021931-daa7c04131f5+incompatible",
+	} {
+		assert.True(t, IsPseudoVersion(version), version)
+	}
+
+	for _, version := range []string{
+		"v1.2.3",
+		"v1.2.3-rc.1",
+		"v1.2.3-20191109021931",
+		"v0.0.0-2019110902193-daa7c04131f5",
+		"v3.3.5+incompatible",
+	} {
+		assert.False(t, IsPseudoVersion(version), version)
+	}
+}
@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"net/url"
+	"strings"
+)
+
+// PackageURL returns package URL of the given Go module version, e.g. `pkg:golang/github.com/beatlabs/gomodctl@v0.3.0`.
+// Version is omitted if it is empty. See https://github.com/package-url/purl-spec.
+func PackageURL(path, version string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	purl := "pkg:golang/" + strings.Join(segments, "/")
+	if version != "" {
+		// '+' of build metadata, e.g. +incompatible, must be percent-encoded.
+		purl += "@" + strings.ReplaceAll(url.PathEscape(version), "+", "%2B")
+	}
+
+	return purl
+}
@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageURL(t *testing.T) {
+	tests := []struct {
+		path    string
+		version string
+		want    string
+	}{
+		{"github.com/beatlabs/gomodctl", "v0.3.0", "pkg:golang/github.com/beatlabs/gomodctl@v0.3.0"},
+		{"github.com/BurntSushi/toml", "v0.3.1", "pkg:golang/github.com/BurntSushi/toml@v0.3.1"},
+		{"gopkg.in/yaml.v2", "v2.4.0+incompatible", "pkg:golang/gopkg.in/yaml.v2@v2.4.0%2Bincompatible"},
+		{"github.com/beatlabs/gomodctl", "", "pkg:golang/github.com/beatlabs/gomodctl"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, PackageURL(tt.path, tt.version))
+	}
+}
@@ -0,0 +1,122 @@
+package sbom
+
+import (
+	"context"
+	"sort"
+
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/license"
+	"github.com/beatlabs/gomodctl/internal/module"
+)
+
+// mainIndex is the component index of the main module.
+const mainIndex = -1
+
+// Generator collects modules, their resolved versions, licenses and requirements into a BOM.
+type Generator struct {
+	parser  *module.ModParser
+	checker *license.Checker
+}
+
+// NewGenerator creates a new instance of Generator.
+func NewGenerator(ctx context.Context, checker *license.Checker) *Generator {
+	return &Generator{
+		parser:  module.NewModParser(ctx),
+		checker: checker,
+	}
+}
+
+// Generate returns BOM of the module in the given path, components are sorted by path.
+func (g *Generator) Generate(path string) (*internal.BOM, error) {
+	results, err := g.parser.ParseAll(path)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := g.parser.Graph(path)
+	if err != nil {
+		return nil, err
+	}
+
+	bom := &internal.BOM{}
+
+	// selected maps graph nodes of the selected versions to the component index,
+	// byPath maps module paths to the component index.
+	selected := make(map[string]int)
+	byPath := make(map[string]int)
+
+	for _, result := range results {
+		if result.Main {
+			bom.Main = internal.Component{
+				Path:    result.Path,
+				License: g.checker.LocalLicense(result.Dir),
+			}
+			selected[result.Path] = mainIndex
+			byPath[result.Path] = mainIndex
+
+			continue
+		}
+
+		selected[result.Path+"@"+result.Version] = len(bom.Components)
+		byPath[result.Path] = len(bom.Components)
+
+		bom.Components = append(bom.Components, internal.Component{
+			Path:     result.ResolvePath(),
+			Version:  result.LocalVersion.Original(),
+			Indirect: result.Indirect,
+			License:  g.checker.License(result.ResolvePath(), result.LocalVersion),
+		})
+	}
+
+	component := func(i int) *internal.Component {
+		if i == mainIndex {
+			return &bom.Main
+		}
+
+		return &bom.Components[i]
+	}
+
+	for from, requirements := range graph {
+		node := from.Path
+		if from.Version != "" {
+			node += "@" + from.Version
+		}
+
+		i, ok := selected[node]
+		if !ok {
+			continue
+		}
+
+		c := component(i)
+		for _, requirement := range requirements {
+			if j, ok := byPath[requirement.Path]; ok {
+				c.Dependencies = append(c.Dependencies, component(j).Path)
+			}
+		}
+	}
+
+	bom.Main.Dependencies = unique(bom.Main.Dependencies)
+	for i := range bom.Components {
+		bom.Components[i].Dependencies = unique(bom.Components[i].Dependencies)
+	}
+
+	sort.Slice(bom.Components, func(i, j int) bool {
+		return bom.Components[i].Path < bom.Components[j].Path
+	})
+
+	return bom, nil
+}
+
+// unique returns sorted paths without duplicates.
+func unique(paths []string) []string {
+	sort.Strings(paths)
+
+	var result []string
+	for i, path := range paths {
+		if i == 0 || paths[i-1] != path {
+			result = append(result, path)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Severity is the qualitative severity rating of a vulnerability, see https://www.first.org/cvss/v3.1/specification-document#Qualitative-Severity-Rating-Scale.
+type Severity string
+
+const (
+	// SeverityUnknown means the advisory isn't rated.
+	SeverityUnknown Severity = ""
+	// SeverityLow is CVSS score 0.1-3.9.
+	SeverityLow Severity = "low"
+	// SeverityMedium is CVSS score 4.0-6.9.
+	SeverityMedium Severity = "medium"
+	// SeverityHigh is CVSS score 7.0-8.9.
+	SeverityHigh Severity = "high"
+	// SeverityCritical is CVSS score 9.0-10.0.
+	SeverityCritical Severity = "critical"
+)
+
+var severityRanks = map[Severity]int{
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// ParseSeverity parses a severity rating, GitHub's moderate is accepted as medium.
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if severity == "moderate" {
+		return SeverityMedium, nil
+	}
+
+	if _, ok := severityRanks[severity]; !ok {
+		return SeverityUnknown, fmt.Errorf("invalid severity %q, must be low, medium, high or critical", s)
+	}
+
+	return severity, nil
+}
+
+// AtLeast reports whether the severity is equal or higher than the given one.
+// Unknown severity is at least any severity, since it can't be told to be lower.
+func (s Severity) AtLeast(min Severity) bool {
+	if s == SeverityUnknown {
+		return true
+	}
+
+	return severityRanks[s] >= severityRanks[min]
+}
+
+// Rank orders severities from 1 for low to 4 for critical, it is 0 for unknown severity.
+func (s Severity) Rank() int {
+	return severityRanks[s]
+}
+
+// SeverityOfScore returns rating of the CVSS score.
+func SeverityOfScore(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+
+	return SeverityUnknown
+}
+
+var cvssWeights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// CVSSScore returns base score of a CVSS v3 vector, e.g. `CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H`.
+func CVSSScore(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, fmt.Errorf("unsupported CVSS vector %q", vector)
+	}
+
+	metrics := make(map[string]string)
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return 0, fmt.Errorf("invalid CVSS vector %q", vector)
+		}
+
+		metrics[kv[0]] = kv[1]
+	}
+
+	weight := func(metric string) (float64, error) {
+		w, ok := cvssWeights[metric][metrics[metric]]
+		if !ok {
+			return 0, fmt.Errorf("invalid CVSS vector %q: missing or invalid %s", vector, metric)
+		}
+
+		return w, nil
+	}
+
+	var w [6]float64
+	for i, metric := range []string{"AV", "AC", "UI", "C", "I", "A"} {
+		var err error
+		if w[i], err = weight(metric); err != nil {
+			return 0, err
+		}
+	}
+	av, ac, ui, c, i, a := w[0], w[1], w[2], w[3], w[4], w[5]
+
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, fmt.Errorf("invalid CVSS vector %q: missing or invalid S", vector)
+	}
+
+	var pr float64
+	switch metrics["PR"] {
+	case "N":
+		pr = 0.85
+	case "L":
+		pr = 0.62
+		if changed {
+			pr = 0.68
+		}
+	case "H":
+		pr = 0.27
+		if changed {
+			pr = 0.5
+		}
+	default:
+		return 0, fmt.Errorf("invalid CVSS vector %q: missing or invalid PR", vector)
+	}
+
+	iss := 1 - (1-c)*(1-i)*(1-a)
+
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+
+	if impact <= 0 {
+		return 0, nil
+	}
+
+	exploitability := 8.22 * av * ac * pr * ui
+
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// roundUp returns the smallest number with one decimal equal or higher than the input, as defined by CVSS v3.1.
+func roundUp(f float64) float64 {
+	i := int64(math.Round(f * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+
+	return float64(i/10000+1) / 10
+}
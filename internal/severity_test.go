@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCVSSScore(t *testing.T) {
+	tests := []struct {
+		vector string
+		score  float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H", 7.5},
+		{"CVSS:3.0/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N", 5.4},
+		{"CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0},
+	}
+
+	for _, tt := range tests {
+		score, err := CVSSScore(tt.vector)
+		assert.NoError(t, err, tt.vector)
+		assert.Equal(t, tt.score, score, tt.vector)
+	}
+
+	_, err := CVSSScore("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+	assert.Error(t, err)
+
+	_, err = CVSSScore("CVSS:3.1/AV:N/AC:L")
+	assert.Error(t, err)
+}
+
+func TestSeverity(t *testing.T) {
+	assert.Equal(t, SeverityCritical, SeverityOfScore(9.8))
+	assert.Equal(t, SeverityHigh, SeverityOfScore(7.5))
+	assert.Equal(t, SeverityMedium, SeverityOfScore(4))
+	assert.Equal(t, SeverityLow, SeverityOfScore(1.8))
+	assert.Equal(t, SeverityUnknown, SeverityOfScore(0))
+
+	severity, err := ParseSeverity("MODERATE")
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityMedium, severity)
+
+	_, err = ParseSeverity("urgent")
+	assert.Error(t, err)
+
+	assert.True(t, SeverityHigh.AtLeast(SeverityMedium))
+	assert.True(t, SeverityHigh.AtLeast(SeverityHigh))
+	assert.False(t, SeverityLow.AtLeast(SeverityHigh))
+	assert.True(t, SeverityUnknown.AtLeast(SeverityCritical))
+}
@@ -0,0 +1,347 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+var spdxTokenRegexp = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+
+// NoAssertion is the SPDX identifier of licenses which can't be determined.
+const NoAssertion = "NOASSERTION"
+
+// spdxIDs contains canonical SPDX identifiers, they are matched by licenseKey too.
+var spdxIDs = []string{
+	"0BSD", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.1", "Apache-2.0", "Artistic-2.0",
+	"BSD-2-Clause", "BSD-3-Clause", "BSD-4-Clause", "BSL-1.0", "CC-BY-4.0", "CC-BY-SA-4.0", "CC0-1.0",
+	"CDDL-1.0", "EPL-1.0", "EPL-2.0", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0-only", "GPL-3.0-or-later",
+	"ISC", "LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-3.0-only", "LGPL-3.0-or-later", "MIT", "MPL-1.1",
+	"MPL-2.0", "OpenSSL", "PostgreSQL", "Python-2.0", "Unlicense", "WTFPL", "Zlib",
+}
+
+// spdxAliases maps common license names to SPDX identifiers.
+var spdxAliases = map[string]string{
+	"Apache 2":                 "Apache-2.0",
+	"Apache2":                  "Apache-2.0",
+	"ASL 2.0":                  "Apache-2.0",
+	"Apache License 2.0":       "Apache-2.0",
+	"Apache Software License":  "Apache-2.0",
+	"Expat":                    "MIT",
+	"New BSD":                  "BSD-3-Clause",
+	"Modified BSD":             "BSD-3-Clause",
+	"BSD-3":                    "BSD-3-Clause",
+	"Simplified BSD":           "BSD-2-Clause",
+	"FreeBSD":                  "BSD-2-Clause",
+	"BSD-2":                    "BSD-2-Clause",
+	"Original BSD":             "BSD-4-Clause",
+	"Boost Software License":   "BSL-1.0",
+	"Mozilla Public License 2": "MPL-2.0",
+	"GPL-2.0":                  "GPL-2.0-only",
+	"GPLv2":                    "GPL-2.0-only",
+	"GPL-2.0+":                 "GPL-2.0-or-later",
+	"GPL-3.0":                  "GPL-3.0-only",
+	"GPLv3":                    "GPL-3.0-only",
+	"GPL-3.0+":                 "GPL-3.0-or-later",
+	"LGPL-2.1":                 "LGPL-2.1-only",
+	"LGPL-2.1+":                "LGPL-2.1-or-later",
+	"LGPL-3.0":                 "LGPL-3.0-only",
+	"LGPLv3":                   "LGPL-3.0-only",
+	"LGPL-3.0+":                "LGPL-3.0-or-later",
+	"AGPL-3.0":                 "AGPL-3.0-only",
+	"AGPLv3":                   "AGPL-3.0-only",
+	"AGPL-3.0+":                "AGPL-3.0-or-later",
+	"CC0":                      "CC0-1.0",
+}
+
+// ambiguousLicenses are names which don't identify a single license, e.g. BSD is either BSD-2-Clause or BSD-3-Clause
+// and public domain isn't a license at all. They are NOASSERTION, so a human reviews them instead of a guess passing a license policy.
+var ambiguousLicenses = map[string]bool{
+	licenseKey("BSD"):           true,
+	licenseKey("Public Domain"): true,
+}
+
+var spdxLookup = func() map[string]string {
+	m := make(map[string]string)
+
+	for _, id := range spdxIDs {
+		m[licenseKey(id)] = id
+	}
+
+	for alias, id := range spdxAliases {
+		m[licenseKey(alias)] = id
+	}
+
+	return m
+}()
+
+// licenseKey reduces a license name to a form where spelling differences like
+// `Apache 2.0`, `Apache-2.0` and `Apache License, Version 2.0` are equal.
+func licenseKey(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "+", "plus")
+
+	for _, word := range []string{"the ", "license", "licence", "version"} {
+		name = strings.ReplaceAll(name, word, "")
+	}
+
+	var b strings.Builder
+
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// NormalizeLicense maps a detected license name to its SPDX identifier, NOASSERTION if it can't be mapped.
+func NormalizeLicense(name string) string {
+	if id, ok := spdxLookup[licenseKey(name)]; ok {
+		return id
+	}
+
+	return NoAssertion
+}
+
+// NewLicenseResult creates result of the detected license type, compound expressions are parsed into their licenses.
+func NewLicenseResult(version *semver.Version, licenseType string) LicenseResult {
+	licenseResult := LicenseResult{
+		LocalVersion: version,
+		Type:         licenseType,
+		SPDXID:       NormalizeLicense(licenseType),
+	}
+
+	expression, err := ParseLicenseExpression(licenseType)
+	if err != nil {
+		return licenseResult
+	}
+
+	if expression.IsCompound() {
+		licenseResult.SPDXID = expression.String()
+		licenseResult.Expression = expression.String()
+		licenseResult.Licenses = expression.Licenses()
+	} else if licenseResult.SPDXID == NoAssertion && spdxTokenRegexp.MatchString(licenseType) && licenseType != UnknownLicense {
+		// identifiers of SPDX-License-Identifier which aren't in the lookup table.
+		licenseResult.SPDXID = expression.String()
+	}
+
+	return licenseResult
+}
+
+// LicenseExpression is a parsed SPDX license expression like `MIT OR Apache-2.0`.
+// It is either a single License, optionally WITH an Exception, or an Operator applied to Operands.
+type LicenseExpression struct {
+	License   string
+	Exception string
+	Operator  string
+	Operands  []*LicenseExpression
+}
+
+const (
+	opAnd  = "AND"
+	opOr   = "OR"
+	opWith = "WITH"
+)
+
+// ParseLicenseExpression parses an SPDX license expression, license identifiers are normalized.
+func ParseLicenseExpression(expression string) (*LicenseExpression, error) {
+	p := &expressionParser{tokens: tokenizeExpression(expression)}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in license expression %q", p.tokens[p.pos], expression)
+	}
+
+	return e, nil
+}
+
+// IsCompound reports whether the expression combines more than one license.
+func (e *LicenseExpression) IsCompound() bool {
+	return e.Operator != ""
+}
+
+// Licenses returns every license of the expression.
+func (e *LicenseExpression) Licenses() []string {
+	if !e.IsCompound() {
+		return []string{e.License}
+	}
+
+	var licenses []string
+	for _, o := range e.Operands {
+		licenses = append(licenses, o.Licenses()...)
+	}
+
+	return licenses
+}
+
+// Satisfies reports whether the expression complies with accept,
+// any operand of OR and every operand of AND must be accepted.
+func (e *LicenseExpression) Satisfies(accept func(license string) bool) bool {
+	switch e.Operator {
+	case opOr:
+		for _, o := range e.Operands {
+			if o.Satisfies(accept) {
+				return true
+			}
+		}
+		return false
+	case opAnd:
+		for _, o := range e.Operands {
+			if !o.Satisfies(accept) {
+				return false
+			}
+		}
+		return true
+	default:
+		return accept(e.License)
+	}
+}
+
+// String is exported.
+func (e *LicenseExpression) String() string {
+	if !e.IsCompound() {
+		if e.Exception != "" {
+			return e.License + " " + opWith + " " + e.Exception
+		}
+
+		return e.License
+	}
+
+	parts := make([]string, len(e.Operands))
+	for i, o := range e.Operands {
+		parts[i] = o.String()
+		// AND binds tighter than OR, so nested OR needs parentheses.
+		if e.Operator == opAnd && o.Operator == opOr {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+
+	return strings.Join(parts, " "+e.Operator+" ")
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func tokenizeExpression(expression string) []string {
+	expression = strings.ReplaceAll(expression, "(", " ( ")
+	expression = strings.ReplaceAll(expression, ")", " ) ")
+
+	return strings.Fields(expression)
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+func (p *expressionParser) parseOr() (*LicenseExpression, error) {
+	return p.parseBinary(opOr, p.parseAnd)
+}
+
+func (p *expressionParser) parseAnd() (*LicenseExpression, error) {
+	return p.parseBinary(opAnd, p.parseWith)
+}
+
+func (p *expressionParser) parseBinary(operator string, operand func() (*LicenseExpression, error)) (*LicenseExpression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []*LicenseExpression{first}
+
+	for strings.EqualFold(p.peek(), operator) {
+		p.pos++
+
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		operands = append(operands, next)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	return &LicenseExpression{Operator: operator, Operands: operands}, nil
+}
+
+func (p *expressionParser) parseWith() (*LicenseExpression, error) {
+	e, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(p.peek(), opWith) {
+		p.pos++
+
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" {
+			return nil, errors.New("missing exception after WITH in license expression")
+		}
+		p.pos++
+
+		if e.IsCompound() {
+			return nil, errors.New("WITH must follow a single license in license expression")
+		}
+
+		e.Exception = exception
+	}
+
+	return e, nil
+}
+
+func (p *expressionParser) parseAtom() (*LicenseExpression, error) {
+	token := p.peek()
+
+	switch {
+	case token == "":
+		return nil, errors.New("unexpected end of license expression")
+	case token == "(":
+		p.pos++
+
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ")" {
+			return nil, errors.New("missing ) in license expression")
+		}
+		p.pos++
+
+		return e, nil
+	case token == ")" || isOperator(token):
+		return nil, fmt.Errorf("unexpected %q in license expression", token)
+	}
+
+	p.pos++
+
+	license := NormalizeLicense(token)
+	if license == NoAssertion && !ambiguousLicenses[licenseKey(token)] {
+		// keep identifiers which aren't in the lookup table, they may still be valid SPDX identifiers.
+		license = token
+	}
+
+	return &LicenseExpression{License: license}, nil
+}
+
+func isOperator(token string) bool {
+	return strings.EqualFold(token, opAnd) || strings.EqualFold(token, opOr) || strings.EqualFold(token, opWith)
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLicense(t *testing.T) {
+	tests := map[string]string{
+		"Apache 2.0":                  "Apache-2.0",
+		"Apache-2.0":                  "Apache-2.0",
+		"ASL 2.0":                     "Apache-2.0",
+		"Apache License, Version 2.0": "Apache-2.0",
+		"MIT License":                 "MIT",
+		"bsd-3-clause":                "BSD-3-Clause",
+		"GPL-2.0":                     "GPL-2.0-only",
+		"GPL-2.0+":                    "GPL-2.0-or-later",
+		"LGPL-2.1":                    "LGPL-2.1-only",
+		"BSD":                         NoAssertion,
+		"BSD License":                 NoAssertion,
+		"Public Domain":               NoAssertion,
+		UnknownLicense:                NoAssertion,
+		"":                            NoAssertion,
+	}
+
+	for name, expected := range tests {
+		assert.Equal(t, expected, NormalizeLicense(name), name)
+	}
+}
+
+func TestParseLicenseExpression(t *testing.T) {
+	e, err := ParseLicenseExpression("MIT OR Apache-2.0")
+	assert.NoError(t, err)
+	assert.True(t, e.IsCompound())
+	assert.Equal(t, []string{"MIT", "Apache-2.0"}, e.Licenses())
+	assert.Equal(t, "MIT OR Apache-2.0", e.String())
+
+	e, err = ParseLicenseExpression("(mit or bsd-3-clause) and GPL-2.0 WITH Classpath-exception-2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "(MIT OR BSD-3-Clause) AND GPL-2.0-only WITH Classpath-exception-2.0", e.String())
+
+	allowed := map[string]bool{"MIT": true, "GPL-2.0-only": true}
+	assert.True(t, e.Satisfies(func(l string) bool { return allowed[l] }))
+
+	delete(allowed, "GPL-2.0-only")
+	assert.False(t, e.Satisfies(func(l string) bool { return allowed[l] }))
+
+	e, err = ParseLicenseExpression("MIT")
+	assert.NoError(t, err)
+	assert.False(t, e.IsCompound())
+
+	e, err = ParseLicenseExpression("MIT OR BSD")
+	assert.NoError(t, err)
+	assert.Equal(t, "MIT OR NOASSERTION", e.String(), "ambiguous names aren't kept as identifiers")
+	assert.Equal(t, NoAssertion, NewLicenseResult(nil, "BSD").SPDXID)
+
+	for _, invalid := range []string{"", "MIT OR", "(MIT", "AND MIT", "MIT Apache-2.0"} {
+		_, err = ParseLicenseExpression(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,194 @@
+package gomodctl
+
+import (
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/module"
+)
+
+// scopes maps update scopes to the ones of the implementation, their values don't have to match.
+var scopes = map[UpdateScope]internal.UpdateScope{
+	ScopeLatest: internal.ScopeLatest,
+	ScopeMajor:  internal.ScopeMajor,
+	ScopeMinor:  internal.ScopeMinor,
+	ScopePatch:  internal.ScopePatch,
+}
+
+func (o CheckOptions) internal() internal.CheckOptions {
+	return internal.CheckOptions{
+		Scope:         scopes[o.Scope],
+		Exclude:       o.Exclude,
+		DirectOnly:    o.DirectOnly,
+		Prerelease:    o.Prerelease,
+		Progress:      o.Progress,
+		GoVersion:     o.GoVersion,
+		Changed:       o.Changed,
+		Base:          o.Base,
+		Vendor:        o.Vendor,
+		Secure:        o.Secure,
+		Modules:       requirements(o.Modules),
+		Retries:       o.Retries,
+		ModuleTimeout: o.ModuleTimeout,
+		NoCache:       o.NoCache,
+	}
+}
+
+func (o UpdateOptions) internal() internal.UpdateOptions {
+	return internal.UpdateOptions{
+		CheckOptions: o.CheckOptions.internal(),
+		Backup:       o.Backup,
+		DryRun:       o.DryRun,
+		Verify:       o.Verify,
+		Remove:       o.Remove,
+	}
+}
+
+func (o ScanOptions) internal() internal.ScanOptions {
+	return internal.ScanOptions{
+		MinSeverity: internal.Severity(o.MinSeverity),
+		Progress:    o.Progress,
+		Changed:     o.Changed,
+		DB:          o.DB,
+		DBURL:       o.DBURL,
+		Upgrades:    o.Upgrades,
+		ProdOnly:    o.ProdOnly,
+		Trust:       o.Trust,
+	}
+}
+
+func requirements(rs []Requirement) []internal.Requirement {
+	if rs == nil {
+		return nil
+	}
+
+	m := make([]internal.Requirement, 0, len(rs))
+	for _, r := range rs {
+		m = append(m, internal.Requirement{Path: r.Path, Version: r.Version})
+	}
+
+	return m
+}
+
+func fromRequirements(rs []internal.Requirement) []Requirement {
+	if rs == nil {
+		return nil
+	}
+
+	m := make([]Requirement, 0, len(rs))
+	for _, r := range rs {
+		m = append(m, Requirement{Path: r.Path, Version: r.Version})
+	}
+
+	return m
+}
+
+func checkResult(r internal.CheckResult) CheckResult {
+	return CheckResult{
+		LocalVersion:  r.LocalVersion,
+		LatestVersion: r.LatestVersion,
+		Error:         r.Error,
+		Replaced:      r.Replaced,
+		Indirect:      r.Indirect,
+		Retracted:     r.Retracted,
+		Pseudo:        r.Pseudo,
+		Deprecated:    r.Deprecated,
+		LatestPath:    r.LatestPath,
+		LocalTime:     r.LocalTime,
+		LatestTime:    r.LatestTime,
+		Advisories:    r.Advisories,
+		SecureVersion: r.SecureVersion,
+	}
+}
+
+// internal converts the result back, e.g. for Updater.Apply of results returned by Updater.Plan.
+func (r CheckResult) internal() internal.CheckResult {
+	return internal.CheckResult{
+		LocalVersion:  r.LocalVersion,
+		LatestVersion: r.LatestVersion,
+		Error:         r.Error,
+		Replaced:      r.Replaced,
+		Indirect:      r.Indirect,
+		Retracted:     r.Retracted,
+		Pseudo:        r.Pseudo,
+		Deprecated:    r.Deprecated,
+		LatestPath:    r.LatestPath,
+		LocalTime:     r.LocalTime,
+		LatestTime:    r.LatestTime,
+		Advisories:    r.Advisories,
+		SecureVersion: r.SecureVersion,
+	}
+}
+
+func checkResults(results map[string]internal.CheckResult) map[string]CheckResult {
+	if results == nil {
+		return nil
+	}
+
+	m := make(map[string]CheckResult, len(results))
+	for name, result := range results {
+		m[name] = checkResult(result)
+	}
+
+	return m
+}
+
+func vulnerabilityResult(r internal.VulnerabilityResult) VulnerabilityResult {
+	result := VulnerabilityResult{
+		LocalVersion: r.LocalVersion,
+		Error:        r.Error,
+		Position:     Position{Line: r.Position.Line, Column: r.Position.Column},
+		Upgrades:     fromRequirements(r.Upgrades),
+		Trusted:      r.Trusted,
+	}
+
+	for _, v := range r.Vulnerabilities {
+		result.Vulnerabilities = append(result.Vulnerabilities, Vulnerability{
+			ID:       v.ID,
+			Aliases:  v.Aliases,
+			Summary:  v.Summary,
+			Severity: v.Severity,
+			Score:    v.Score,
+			Rating:   Severity(v.Rating),
+			Fixed:    v.Fixed,
+		})
+	}
+
+	return result
+}
+
+func licenseResult(r internal.LicenseResult) LicenseResult {
+	result := LicenseResult{
+		LocalVersion: r.LocalVersion,
+		Type:         r.Type,
+		SPDXID:       r.SPDXID,
+		Expression:   r.Expression,
+		Licenses:     r.Licenses,
+		Confidence:   r.Confidence,
+		Overridden:   r.Overridden,
+		Error:        r.Error,
+	}
+
+	for _, f := range r.Files {
+		result.Files = append(result.Files, LicenseFile{Path: f.Path, License: f.License, Confidence: f.Confidence})
+	}
+
+	return result
+}
+
+func packageResults(results []module.PackageResult) []PackageResult {
+	m := make([]PackageResult, 0, len(results))
+
+	for _, r := range results {
+		m = append(m, PackageResult{
+			Path:         r.Path,
+			LocalVersion: r.LocalVersion,
+			Dir:          r.Dir,
+			Replaced:     r.Replaced,
+			ReplacePath:  r.ReplacePath,
+			Indirect:     r.Indirect,
+			Main:         r.Main,
+			Position:     Position{Line: r.Position.Line, Column: r.Position.Column},
+		})
+	}
+
+	return m
+}
@@ -0,0 +1,44 @@
+package gomodctl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOptions(t *testing.T) {
+	for scope, expected := range map[UpdateScope]internal.UpdateScope{
+		ScopeLatest: internal.ScopeLatest,
+		ScopeMajor:  internal.ScopeMajor,
+		ScopeMinor:  internal.ScopeMinor,
+		ScopePatch:  internal.ScopePatch,
+	} {
+		assert.Equal(t, expected, CheckOptions{Scope: scope}.internal().Scope)
+	}
+
+	options := UpdateOptions{
+		CheckOptions: CheckOptions{DirectOnly: true, Modules: []Requirement{{Path: "example.com/a", Version: "v1.0.0"}}},
+		Verify:       true,
+	}.internal()
+	assert.True(t, options.DirectOnly)
+	assert.True(t, options.Verify)
+	assert.Equal(t, []internal.Requirement{{Path: "example.com/a", Version: "v1.0.0"}}, options.Modules)
+}
+
+func TestCheckResult(t *testing.T) {
+	r := internal.CheckResult{
+		LocalVersion:  semver.MustParse("v1.0.0"),
+		LatestVersion: semver.MustParse("v1.2.0"),
+		Indirect:      true,
+		LocalTime:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Advisories:    []string{"GO-2020-0001"},
+	}
+
+	result := checkResult(r)
+	assert.True(t, result.Updatable())
+	assert.Equal(t, UpdateMinor, result.UpdateType())
+	assert.Equal(t, r, result.internal(), "results of Plan are passed to Apply unchanged")
+}
@@ -0,0 +1,39 @@
+package gomodctl_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/beatlabs/gomodctl/pkg/gomodctl"
+)
+
+func ExampleChecker_Check() {
+	checker := gomodctl.NewChecker(context.Background())
+
+	results, err := checker.Check(".", gomodctl.CheckOptions{Scope: gomodctl.ScopeMinor, DirectOnly: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for path, result := range results {
+		if result.Updatable() {
+			fmt.Println(path, result.LocalVersion.Original(), "->", result.LatestVersion.Original())
+		}
+	}
+}
+
+func ExampleScanner_Scan() {
+	scanner := gomodctl.NewScanner(context.Background())
+
+	results, err := scanner.Scan(".", gomodctl.ScanOptions{MinSeverity: gomodctl.SeverityHigh})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for path, result := range results {
+		for _, v := range result.Vulnerabilities {
+			fmt.Println(path, v.ID, v.Rating, "fixed in", v.Fixed)
+		}
+	}
+}
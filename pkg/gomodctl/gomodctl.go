@@ -0,0 +1,212 @@
+// Package gomodctl lets other Go tools check, update, scan and look up licenses of module dependencies
+// the way the gomodctl command does, without running the binary.
+//
+// The types of this package are its stable API, they are converted from the implementation used by the command,
+// so changes of the internal packages don't leak into it. Configuration such as the proxy, the cache and ignored
+// modules is read through viper like the command reads gomodctl.yml, zero values fall back to the same defaults.
+package gomodctl
+
+import (
+	"context"
+
+	"github.com/Masterminds/semver"
+	"github.com/beatlabs/gomodctl/internal"
+	"github.com/beatlabs/gomodctl/internal/license"
+	"github.com/beatlabs/gomodctl/internal/module"
+)
+
+// Errors set in CheckResult.Error or returned by the checkers, compare them with errors.Is.
+var (
+	ErrNoVersionAvailable = module.ErrNoVersionAvailable
+	ErrModuleIgnored      = module.ErrModuleIgnored
+	ErrModuleTimeout      = module.ErrModuleTimeout
+	ErrPrivateModule      = module.ErrPrivateModule
+	ErrVendorMismatch     = internal.ErrVendorMismatch
+	ErrVersionNotFound    = module.ErrVersionNotFound
+	ErrDowngrade          = module.ErrDowngrade
+	ErrNoBackup           = module.ErrNoBackup
+)
+
+// Categories of errors set in CheckResult.Error, compare them with errors.Is, see ErrorCategory.
+var (
+	ErrNetwork  = internal.ErrNetwork
+	ErrNotFound = internal.ErrNotFound
+	ErrProxy    = internal.ErrProxy
+	ErrParse    = internal.ErrParse
+)
+
+// Checker resolves the latest versions of dependencies, see NewChecker.
+type Checker struct {
+	checker module.Checker
+}
+
+// NewChecker returns a Checker, the context cancels the go commands and HTTP requests it runs.
+func NewChecker(ctx context.Context) *Checker {
+	return &Checker{checker: module.Checker{Ctx: ctx}}
+}
+
+// Check resolves the latest versions of the modules required by go.mod in the given path, keyed by module path.
+func (c *Checker) Check(path string, options CheckOptions) (map[string]CheckResult, error) {
+	results, err := c.checker.Check(path, options.internal())
+
+	return checkResults(results), err
+}
+
+// CheckModule resolves the latest version of a single module, the version is optional.
+func (c *Checker) CheckModule(modulePath, version string, options CheckOptions) (CheckResult, error) {
+	result, err := c.checker.CheckModule(modulePath, version, options.internal())
+
+	return checkResult(result), err
+}
+
+// Versions returns the available versions of the module.
+func (c *Checker) Versions(modulePath string) ([]*semver.Version, error) {
+	return c.checker.Versions(modulePath)
+}
+
+// Unused returns modules required by go.mod in the given path which go mod tidy would remove.
+func (c *Checker) Unused(path string) ([]UnusedModule, error) {
+	unused, err := c.checker.Unused(path)
+
+	modules := make([]UnusedModule, 0, len(unused))
+	for _, u := range unused {
+		modules = append(modules, UnusedModule{Path: u.Path, Version: u.Version, Indirect: u.Indirect})
+	}
+
+	return modules, err
+}
+
+// Updater updates versions of dependencies in go.mod, see NewUpdater.
+type Updater struct {
+	updater module.Updater
+}
+
+// NewUpdater returns an Updater, the context cancels the go commands and HTTP requests it runs.
+func NewUpdater(ctx context.Context) *Updater {
+	return &Updater{updater: module.Updater{Ctx: ctx}}
+}
+
+// Update resolves updates of the modules required by go.mod in the given path and writes them into go.mod.
+func (u *Updater) Update(path string, options UpdateOptions) (map[string]CheckResult, error) {
+	results, err := u.updater.Update(path, options.internal())
+
+	return checkResults(results), err
+}
+
+// Plan resolves the updates Update would write without changing any file.
+func (u *Updater) Plan(path string, options UpdateOptions) (map[string]CheckResult, error) {
+	results, err := u.updater.Plan(path, options.internal())
+
+	return checkResults(results), err
+}
+
+// Apply writes the given updates, e.g. returned by Plan, into go.mod in the given path.
+func (u *Updater) Apply(path string, updates map[string]CheckResult, options UpdateOptions) error {
+	results := make(map[string]internal.CheckResult, len(updates))
+	for name, result := range updates {
+		results[name] = result.internal()
+	}
+
+	return u.updater.Apply(path, results, options.internal())
+}
+
+// Restore restores go.mod and go.sum from the backup written with UpdateOptions.Backup.
+func (u *Updater) Restore(path string) error {
+	return u.updater.Restore(path)
+}
+
+// Scanner checks dependencies against the OSV vulnerability database, see NewScanner.
+type Scanner struct {
+	scanner module.Scanner
+}
+
+// NewScanner returns a Scanner, the context cancels the go commands and HTTP requests it runs.
+func NewScanner(ctx context.Context) *Scanner {
+	return &Scanner{scanner: module.Scanner{Ctx: ctx}}
+}
+
+// Scan looks up advisories of all dependencies of the module in the given path, keyed by module path.
+func (s *Scanner) Scan(path string, options ScanOptions) (map[string]VulnerabilityResult, error) {
+	results, err := s.scanner.Scan(path, options.internal())
+
+	m := make(map[string]VulnerabilityResult, len(results))
+	for name, result := range results {
+		m[name] = vulnerabilityResult(result)
+	}
+
+	return m, err
+}
+
+// ModParser reads required modules of go.mod, see NewModParser.
+type ModParser struct {
+	parser *module.ModParser
+}
+
+// NewModParser returns a ModParser, the context cancels the go commands it runs.
+func NewModParser(ctx context.Context) *ModParser {
+	return &ModParser{parser: module.NewModParser(ctx)}
+}
+
+// Parse returns the modules required by go.mod in the given path.
+func (p *ModParser) Parse(path string) ([]PackageResult, error) {
+	results, err := p.parser.Parse(path)
+
+	return packageResults(results), err
+}
+
+// ParseAll returns the main module and all modules of its build list, including indirect ones.
+func (p *ModParser) ParseAll(path string) ([]PackageResult, error) {
+	results, err := p.parser.ParseAll(path)
+
+	return packageResults(results), err
+}
+
+// LicenseChecker looks up licenses of dependencies, see NewLicenseChecker.
+type LicenseChecker struct {
+	checker *license.Checker
+}
+
+// NewLicenseChecker returns a LicenseChecker, it fails if the embedded license database can't be loaded.
+func NewLicenseChecker(ctx context.Context) (*LicenseChecker, error) {
+	checker, err := license.NewChecker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LicenseChecker{checker: checker}, nil
+}
+
+// Type returns the license name of the module version, the latest version if version is empty.
+func (l *LicenseChecker) Type(modulePath, version string) (string, error) {
+	return l.checker.Type(modulePath, version)
+}
+
+// Types returns licenses of the modules required by go.mod in the given path, keyed by module path.
+func (l *LicenseChecker) Types(path string) (map[string]LicenseResult, error) {
+	results, err := l.checker.Types(path)
+
+	m := make(map[string]LicenseResult, len(results))
+	for name, result := range results {
+		m[name] = licenseResult(result)
+	}
+
+	return m, err
+}
+
+// License returns the license of the module version.
+func (l *LicenseChecker) License(modulePath string, version *semver.Version) LicenseResult {
+	return licenseResult(l.checker.License(modulePath, version))
+}
+
+// ParseSeverity parses a severity rating, e.g. for ScanOptions.MinSeverity.
+func ParseSeverity(s string) (Severity, error) {
+	severity, err := internal.ParseSeverity(s)
+
+	return Severity(severity), err
+}
+
+// ErrorCategory returns the name of the category of an error like the errorCategory field of check JSON output:
+// network, not-found, proxy or parse, empty if it has none.
+func ErrorCategory(err error) string {
+	return internal.ErrorCategory(err)
+}
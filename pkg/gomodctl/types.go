@@ -0,0 +1,237 @@
+package gomodctl
+
+import (
+	"time"
+
+	"github.com/Masterminds/semver"
+)
+
+// UpdateScope limits the versions considered as an update candidate.
+type UpdateScope int
+
+// Update scopes of CheckOptions.
+const (
+	// ScopeLatest considers every available version.
+	ScopeLatest UpdateScope = iota
+	// ScopeMajor considers only versions with a higher major than the local version.
+	ScopeMajor
+	// ScopeMinor considers only versions within the major of the local version.
+	ScopeMinor
+	// ScopePatch considers only versions within the major.minor of the local version.
+	ScopePatch
+)
+
+// UpdateType is kind of the update between two versions.
+type UpdateType string
+
+// Update types returned by CheckResult.UpdateType.
+const (
+	UpdateNone  UpdateType = "none"
+	UpdateMajor UpdateType = "major"
+	UpdateMinor UpdateType = "minor"
+	UpdatePatch UpdateType = "patch"
+)
+
+// Severity is the qualitative severity rating of a vulnerability.
+type Severity string
+
+// Severity ratings of vulnerabilities.
+const (
+	SeverityUnknown  Severity = ""
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// CheckOptions contains options for version check.
+type CheckOptions struct {
+	Scope UpdateScope
+	// Exclude contains glob patterns of module paths to be ignored, see path.Match.
+	Exclude []string
+	// DirectOnly leaves out modules required with an // indirect comment.
+	DirectOnly bool
+	// Prerelease considers prereleases as update candidates, by default they are suggested only if there is no stable version.
+	Prerelease bool
+	// Progress is called with the number of resolved and all modules whenever a module is done, it may be nil.
+	Progress func(done, total int)
+	// GoVersion skips update candidates whose go.mod requires a newer Go, e.g. 1.21. The version of the go command is used if it is empty.
+	GoVersion string
+	// Changed checks only modules whose require lines in go.mod changed since git HEAD.
+	Changed bool
+	// Base is a git revision, e.g. origin/main, only modules added or required with another version than by go.mod of it are checked.
+	Base string
+	// Vendor compares versions in vendor/modules.txt with go.mod, modules which differ fail with ErrVendorMismatch.
+	Vendor bool
+	// Secure looks up advisories of the local versions in the OSV database, see CheckResult.SecureVersion.
+	Secure bool
+	// Modules are checked instead of the requirements of go.mod, which isn't read then.
+	Modules []Requirement
+	// Retries is how often versions of a module are looked up again after they failed with ErrNetwork.
+	Retries int
+	// ModuleTimeout limits the time resolving a module may take, a module which takes longer fails with ErrModuleTimeout.
+	ModuleTimeout time.Duration
+	// NoCache looks up versions again instead of reading them from the cache, like the no_cache config.
+	NoCache bool
+}
+
+// UpdateOptions contains options for update.
+type UpdateOptions struct {
+	CheckOptions
+	// Backup copies go.mod and go.sum to go.mod.bak and go.sum.bak before go.mod is changed.
+	Backup bool
+	// DryRun resolves updates without changing any file.
+	DryRun bool
+	// Verify runs go mod tidy and go build ./... after the update and restores go.mod and go.sum if they fail.
+	Verify bool
+	// Remove contains modules to be dropped from go.mod, e.g. unused modules.
+	Remove []string
+}
+
+// ScanOptions contains options for vulnerability scan.
+type ScanOptions struct {
+	// MinSeverity leaves out advisories rated lower, advisories without rating are always reported.
+	MinSeverity Severity
+	// Progress is called with the number of scanned and all modules whenever a module is done, it may be nil.
+	Progress func(done, total int)
+	// Changed scans only modules whose require lines in go.mod changed since git HEAD.
+	Changed bool
+	// DB is a local copy of the OSV database, a directory or a file of OSV JSON records or a zip of them.
+	DB string
+	// DBURL is the URL of an OSV API mirror, osv_url config or the public API are used if it is empty.
+	DBURL string
+	// Upgrades resolves direct dependency upgrades fixing vulnerable transitive modules, see VulnerabilityResult.Upgrades.
+	Upgrades bool
+	// ProdOnly scans only modules providing packages imported by non-test packages of the main module.
+	ProdOnly bool
+	// Trust contains module path prefixes which aren't scanned, they are added to the trusted_prefixes config.
+	Trust []string
+}
+
+// CheckResult is the result of version check of a module.
+type CheckResult struct {
+	LocalVersion  *semver.Version
+	LatestVersion *semver.Version
+	Error         error
+	// Replaced is true if the module is redirected by a replace directive, it is never updatable then.
+	Replaced bool
+	// Indirect is true if go.mod requires the module with an // indirect comment.
+	Indirect bool
+	// Retracted is true if the local version is retracted by the module author.
+	Retracted bool
+	// Pseudo is true if the local version is a pseudo-version of an untagged commit.
+	Pseudo bool
+	// Deprecated is the deprecation message of go.mod of the latest version, empty if the module isn't deprecated.
+	Deprecated string
+	// LatestPath is set if LatestVersion belongs to a module with a higher major version suffix.
+	LatestPath string
+	// LocalTime and LatestTime are publish times of the versions, zero if they are unknown.
+	LocalTime  time.Time
+	LatestTime time.Time
+	// Advisories are IDs of the advisories affecting the local version, they are only resolved with CheckOptions.Secure.
+	Advisories []string
+	// SecureVersion is the lowest available version fixing all Advisories, nil if any of them isn't fixed.
+	SecureVersion *semver.Version
+}
+
+// Updatable reports whether there is a newer version to update to.
+func (r CheckResult) Updatable() bool {
+	return r.Error == nil && !r.Replaced && r.LocalVersion != nil && r.LatestVersion != nil && r.LatestVersion.GreaterThan(r.LocalVersion)
+}
+
+// UpdateType compares local and latest versions.
+func (r CheckResult) UpdateType() UpdateType {
+	return UpdateType(r.internal().UpdateType())
+}
+
+// UnusedModule is a module required by go.mod which go mod tidy would remove.
+type UnusedModule struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+// VulnerabilityResult is the result of vulnerability scan of a module.
+type VulnerabilityResult struct {
+	LocalVersion    *semver.Version
+	Vulnerabilities []Vulnerability
+	Error           error
+	// Position is where go.mod requires the module, zero if it is only required transitively.
+	Position Position
+	// Upgrades are direct dependency versions pulling in a version of the module which fixes all its vulnerabilities.
+	Upgrades []Requirement
+	// Trusted is true if the module matches a trusted prefix, it isn't scanned then.
+	Trusted bool
+}
+
+// Vulnerability is an advisory of the OSV database affecting a module version.
+type Vulnerability struct {
+	// ID is the OSV identifier, e.g. GO-2021-0001 or GHSA-xxxx-xxxx-xxxx.
+	ID string
+	// Aliases contains other identifiers of the advisory such as CVE IDs.
+	Aliases []string
+	Summary string
+	// Severity is the CVSS vector or the severity rating of the advisory, empty if it isn't rated.
+	Severity string
+	// Score is the CVSS v3 base score, zero if the advisory has no CVSS v3 vector.
+	Score  float64
+	Rating Severity
+	// Fixed is the first version fixing the vulnerability, empty if there is no fix.
+	Fixed string
+}
+
+// LicenseResult is the license of a module version.
+type LicenseResult struct {
+	LocalVersion *semver.Version
+	// Type is the license name as detected.
+	Type string
+	// SPDXID is Type normalized to an SPDX identifier, NOASSERTION if it can't be mapped.
+	SPDXID string
+	// Expression and Licenses are set when the module has more than one license, e.g. `MIT OR Apache-2.0`.
+	Expression string
+	Licenses   []string
+	// Confidence is the score of the license text match from 0 to 1, 1 for an SPDX-License-Identifier.
+	Confidence float64
+	// Files are the license files found at the module root, empty if the module has none.
+	Files []LicenseFile
+	// Overridden is true if the license is set by the license_overrides config key, it isn't detected then.
+	Overridden bool
+	Error      error
+}
+
+// LicenseFile is a license file of a module and the license detected in it.
+type LicenseFile struct {
+	// Path is relative to the module root, e.g. LICENSE, LICENSE.md or COPYING.
+	Path       string
+	License    string
+	Confidence float64
+}
+
+// PackageResult is a module required by the main module, as returned by ModParser.
+type PackageResult struct {
+	Path         string
+	LocalVersion *semver.Version
+	Dir          string
+	// Replaced is true when the module is redirected by a replace directive,
+	// in that case LocalVersion belongs to ReplacePath.
+	Replaced    bool
+	ReplacePath string
+	// Indirect is true for modules which aren't imported by the main module.
+	Indirect bool
+	// Main is true for the main module, only ParseAll returns it.
+	Main bool
+	// Position is where go.mod requires the module, zero if it is only required transitively.
+	Position Position
+}
+
+// Requirement is a module version, the main module has no version.
+type Requirement struct {
+	Path    string
+	Version string
+}
+
+// Position is a position of a directive in go.mod, lines and columns start at 1.
+type Position struct {
+	Line   int
+	Column int
+}